@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"log"
 	"net/http"
@@ -10,6 +11,7 @@ import (
 	"syscall"
 	"time"
 
+	"connectrpc.com/connect"
 	"golang.org/x/net/http2"
 	"golang.org/x/net/http2/h2c"
 
@@ -18,6 +20,10 @@ import (
 	"github.com/kkkkikiki/coupon/gen/coupon/v1/couponv1connect"
 	"github.com/kkkkikiki/coupon/internal/config"
 	"github.com/kkkkikiki/coupon/internal/database"
+	"github.com/kkkkikiki/coupon/internal/interceptor"
+	"github.com/kkkkikiki/coupon/internal/metrics"
+	"github.com/kkkkikiki/coupon/internal/repository"
+	"github.com/kkkkikiki/coupon/internal/rest"
 	"github.com/kkkkikiki/coupon/internal/service"
 )
 
@@ -31,6 +37,9 @@ func main() {
 	}
 
 	log.Printf("Starting coupon service in %s mode", cfg.App.Environment)
+	if cfg.App.AdminAPIKey == "" {
+		log.Println("APP_ADMIN_API_KEY not set; admin RPCs and /config are unauthenticated")
+	}
 
 	// Initialize database connections
 	db, err := database.NewDB(ctx, cfg)
@@ -43,15 +52,96 @@ func main() {
 		}
 	}()
 
+	repository.SetSlowQueryThreshold(time.Duration(cfg.Database.SlowQueryThreshold) * time.Millisecond)
+	if cfg.App.Debug {
+		repository.SetPlanSampleRate(cfg.App.PlanSampleRate)
+	}
+
+	// Periodically ping the database so /readyz reflects connectivity
+	healthChecker := database.NewHealthChecker(
+		db,
+		time.Duration(cfg.Database.HealthCheckInterval)*time.Second,
+		cfg.Database.HealthCheckResetPool,
+		cfg.Database.MinConns,
+	)
+	if cfg.Database.HealthCheckInterval > 0 {
+		healthChecker.Start()
+	}
+
 	// Create coupon service with direct DB access
-	couponService := service.NewCouponServer(db.Postgres)
+	couponService := service.NewCouponServer(db.Postgres, cfg)
+
+	// Run campaign activation side effects (cache preload, webhook)
+	activations := service.NewActivationScheduler(couponService, cfg.App.ActivationWebhookURL)
+	activations.Start()
+
+	// Delete idempotency keys past their TTL
+	idempotencyPruner := service.NewIdempotencyPruner(couponService)
+	idempotencyPruner.Start()
+
+	// Tripwire for issuance concurrency bugs: sampled issued vs total counts
+	overissuanceChecker := service.NewOverissuanceChecker(couponService)
+	overissuanceChecker.Start()
+
+	// Move coupons of long-finished campaigns out of the hot table
+	couponArchiver := service.NewCouponArchiver(couponService)
+	couponArchiver.Start()
 
 	// Create HTTP mux
 	mux := http.NewServeMux()
 
+	// Concurrency caps shared by the connect handler and the REST facade
+	issueLimit := interceptor.NewIssueConcurrencyLimit(cfg.MaxConcurrentIssues())
+	createLimit := interceptor.NewCreateConcurrencyLimit(cfg.App.CreateConcurrency)
+
 	// Register coupon service handler
-	path, handler := couponv1connect.NewCouponServiceHandler(couponService)
-	mux.Handle(path, handler)
+	path, handler := couponv1connect.NewCouponServiceHandler(
+		couponService,
+		connect.WithInterceptors(
+			interceptor.NewRequestIDInterceptor(),
+			interceptor.NewAdminAuthInterceptor(cfg.App.AdminAPIKey,
+				couponv1connect.CouponServiceRegenerateCouponsProcedure,
+				couponv1connect.CouponServiceDeleteCampaignProcedure,
+				couponv1connect.CouponServiceUpdateCampaignProcedure,
+				couponv1connect.CouponServiceRestoreCampaignProcedure,
+				couponv1connect.CouponServiceTransferCouponsProcedure,
+				couponv1connect.CouponServiceRevokeCampaignCouponsProcedure,
+				couponv1connect.CouponServiceDrainCampaignProcedure,
+				couponv1connect.CouponServicePeekAvailableCouponsProcedure,
+				couponv1connect.CouponServiceVerifyCampaignCodesProcedure,
+			),
+			// After admin auth, so unauthenticated callers learn nothing of the limits
+			interceptor.NewRequestLimitsInterceptor(interceptor.RequestLimits{
+				IssueBatchQuantity: cfg.App.MaxIssueBatchQuantity,
+				TransferCodes:      cfg.App.MaxTransferCodes,
+				BatchCampaigns:     cfg.App.MaxBatchCampaigns,
+				PeekLimit:          cfg.App.MaxPeekLimit,
+				CampaignCoupons:    cfg.App.MaxRequestCoupons,
+			}),
+			issueLimit.Interceptor(
+				couponv1connect.CouponServiceIssueCouponProcedure,
+				couponv1connect.CouponServiceIssueBatchProcedure,
+			),
+			createLimit.Interceptor(
+				couponv1connect.CouponServiceCreateCampaignProcedure,
+				couponv1connect.CouponServiceBatchCreateCampaignsProcedure,
+				couponv1connect.CouponServiceCreateCampaignStreamProcedure,
+			),
+			// Innermost, so every other interceptor sees connect errors
+			interceptor.NewErrorInterceptor(),
+		),
+		connect.WithReadMaxBytes(cfg.Server.MaxReadBytes),
+		connect.WithSendMaxBytes(cfg.Server.MaxSendBytes),
+	)
+	// Streaming handlers extend their write deadline through the request context
+	mux.Handle(path, service.WithResponseController(handler))
+
+	// Plain JSON REST facade for clients that can't use connect
+	mux.Handle("/v1/", rest.NewHandler(couponService, rest.Options{
+		MaxBodyBytes: int64(cfg.Server.MaxReadBytes),
+		CreateLimit:  createLimit,
+		IssueLimit:   issueLimit,
+	}))
 
 	// Add health check endpoint
 	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
@@ -72,8 +162,52 @@ func main() {
 		w.Write([]byte(`{"status":"ok","postgres":"connected"}`))
 	})
 
+	// Add readiness endpoint backed by the background DB health check
+	mux.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
+		if !healthChecker.Healthy() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte(`{"status":"error","message":"postgres unavailable"}`))
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"status":"ok"}`))
+	})
+
+	// Add admin endpoint exposing the effective configuration
+	mux.Handle("/config", interceptor.RequireAdmin(cfg.App.AdminAPIKey, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(cfg.Redacted()); err != nil {
+			log.Printf("Failed to encode config: %v", err)
+		}
+	})))
+
+	// Add admin endpoint streaming issued coupons as NDJSON for analytics
+	mux.Handle("/admin/export/issuances", interceptor.RequireAdmin(cfg.App.AdminAPIKey, http.HandlerFunc(couponService.ExportIssuances)))
+
 	// Add Prometheus metrics endpoint
-	mux.Handle("/metrics", promhttp.Handler())
+	if cfg.App.MetricsEnabled {
+		if _, err := metrics.Register(cfg.App.NativeHistograms); err != nil {
+			if cfg.App.ObservabilityRequired {
+				log.Fatalf("Failed to initialize metrics: %v", err)
+			}
+			log.Printf("Failed to initialize metrics, continuing without them: %v", err)
+		} else {
+			mux.Handle("/metrics", promhttp.Handler())
+			if limit := cfg.App.RemainingMetricCampaigns; limit > 0 {
+				err := metrics.RegisterRemainingCollector(func(ctx context.Context) (map[int64]int64, error) {
+					return couponService.ActiveRemaining(ctx, limit)
+				})
+				if err != nil {
+					log.Printf("Failed to register remaining coupon metric: %v", err)
+				}
+			}
+		}
+	} else {
+		log.Println("Metrics disabled; /metrics endpoint not registered")
+	}
+
+	// Count in-flight requests so shutdown can report what it cut off
+	inFlight := interceptor.NewInFlightTracker()
 
 	// Create server with configuration optimized for high concurrency
 	server := &http.Server{
@@ -83,7 +217,7 @@ func main() {
 		IdleTimeout:    120 * time.Second, // Keep connections alive longer
 		MaxHeaderBytes: 1 << 20,           // 1MB
 		// Use h2c so we can serve HTTP/2 without TLS
-		Handler: h2c.NewHandler(mux, &http2.Server{
+		Handler: h2c.NewHandler(inFlight.Wrap(mux), &http2.Server{
 			MaxConcurrentStreams: 1000, // Allow more concurrent streams
 		}),
 	}
@@ -103,12 +237,25 @@ func main() {
 	log.Println("Shutting down server...")
 
 	// Graceful shutdown
-	shutdownCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	shutdownTimeout := time.Duration(cfg.Server.ShutdownTimeout) * time.Second
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
 	defer cancel()
 
-	if err := server.Shutdown(shutdownCtx); err != nil {
-		log.Fatalf("Server forced to shutdown: %v", err)
+	shutdownErr := server.Shutdown(shutdownCtx)
+	if shutdownErr != nil {
+		log.Printf("Shutdown timed out after %s with %d requests still in flight", shutdownTimeout, inFlight.Count())
+		server.Close()
 	}
+	// Background jobs stop either way, so none is cut off mid-write
+	activations.Stop()
+	idempotencyPruner.Stop()
+	overissuanceChecker.Stop()
+	couponArchiver.Stop()
+	healthChecker.Stop()
 
+	if shutdownErr != nil {
+		log.Printf("Server forced to shutdown: %v", shutdownErr)
+		os.Exit(1)
+	}
 	log.Println("Server exited gracefully")
 }
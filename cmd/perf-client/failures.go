@@ -0,0 +1,44 @@
+package main
+
+import (
+	"sync"
+
+	"connectrpc.com/connect"
+)
+
+// failureSamplesPerCode is the number of failed requests kept per error code
+// for the report
+const failureSamplesPerCode = 3
+
+// failureSample is a failed request kept to look up in the server logs
+type failureSample struct {
+	requestID string
+	err       string
+}
+
+// failureSamples keeps the first failed requests of each error code.
+// Printing every failure from the workers would flood the terminal during
+// sold-out runs and slow the workers down.
+type failureSamples struct {
+	mu     sync.Mutex
+	byCode [connect.CodeUnauthenticated + 1][]failureSample
+}
+
+// add records a failed request unless its code already has enough samples
+func (f *failureSamples) add(code connect.Code, requestID string, err error) {
+	if int(code) >= len(f.byCode) {
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if len(f.byCode[code]) < failureSamplesPerCode {
+		f.byCode[code] = append(f.byCode[code], failureSample{requestID: requestID, err: err.Error()})
+	}
+}
+
+// of returns the samples kept for code
+func (f *failureSamples) of(code connect.Code) []failureSample {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.byCode[code]
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"connectrpc.com/connect"
+)
+
+// paddingHeader carries filler bytes that inflate request headers
+const paddingHeader = "X-Perf-Padding"
+
+// headerFlags collects repeated -header "Name: value" flags
+type headerFlags http.Header
+
+func (h headerFlags) String() string {
+	pairs := make([]string, 0, len(h))
+	for name, values := range h {
+		for _, value := range values {
+			pairs = append(pairs, name+": "+value)
+		}
+	}
+	return strings.Join(pairs, ", ")
+}
+
+func (h headerFlags) Set(s string) error {
+	name, value, ok := strings.Cut(s, ":")
+	if !ok || strings.TrimSpace(name) == "" {
+		return fmt.Errorf("header must look like \"Name: value\"")
+	}
+	http.Header(h).Add(strings.TrimSpace(name), strings.TrimSpace(value))
+	return nil
+}
+
+// newSimulationInterceptor returns a client interceptor that makes requests
+// look like real client traffic: it waits delay before sending, sets the
+// given headers and adds padding bytes of filler in an extra header. Returns
+// nil when there is nothing to simulate.
+func newSimulationInterceptor(delay time.Duration, headers http.Header, padding int) connect.UnaryInterceptorFunc {
+	if delay <= 0 && len(headers) == 0 && padding <= 0 {
+		return nil
+	}
+	pad := strings.Repeat("x", max(padding, 0))
+
+	return func(next connect.UnaryFunc) connect.UnaryFunc {
+		return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+			for name, values := range headers {
+				for _, value := range values {
+					req.Header().Add(name, value)
+				}
+			}
+			if pad != "" {
+				req.Header().Set(paddingHeader, pad)
+			}
+
+			if delay > 0 {
+				timer := time.NewTimer(delay)
+				select {
+				case <-timer.C:
+				case <-ctx.Done():
+					timer.Stop()
+					return nil, connect.NewError(connect.CodeOf(ctx.Err()), ctx.Err())
+				}
+			}
+
+			return next(ctx, req)
+		}
+	}
+}
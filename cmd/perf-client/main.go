@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"net/http"
 	"os"
@@ -14,8 +15,12 @@ import (
 	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"connectrpc.com/connect"
+	"github.com/google/uuid"
+
 	couponv1 "github.com/kkkkikiki/coupon/gen/coupon/v1"
 	"github.com/kkkkikiki/coupon/gen/coupon/v1/couponv1connect"
+	couponclient "github.com/kkkkikiki/coupon/internal/client"
+	"github.com/kkkkikiki/coupon/internal/interceptor"
 )
 
 // PerfResult gathers aggregated metrics for the test run.
@@ -23,12 +28,28 @@ import (
 // LatencySum & P95Latency are in nanoseconds.
 //
 // P95Latency is maintained via a lightweight reservoir sampler.
+//
+// ErrorsByCode breaks ErrorCount down by connect error code (index = code);
+// EmptyCouponCount counts successful responses without a coupon. SoldOut is
+// set to 1 once the server reports the campaign as sold out. RetryCount
+// counts retried attempts and RetriedSuccessCount the requests that only
+// succeeded after a retry. InFlight is the number of workers currently
+// blocked waiting on a response.
 type PerfResult struct {
-	TotalRequests int64
-	SuccessCount  int64
-	ErrorCount    int64
-	LatencySum    int64
-	P95Latency    int64
+	TotalRequests    int64
+	SuccessCount     int64
+	ErrorCount       int64
+	LatencySum       int64
+	P95Latency       int64
+	ErrorsByCode     [connect.CodeUnauthenticated + 1]int64
+	FailureSamples   failureSamples // First failed request IDs per code
+	EmptyCouponCount int64
+	SoldOut          int32
+
+	RetryCount          int64
+	RetriedSuccessCount int64
+
+	InFlight int64
 }
 
 const (
@@ -38,48 +59,96 @@ const (
 	defaultTimeout  = 30 * time.Second
 	fixedCoupons    = 50000
 	fixedCreateCamp = true
+
+	// populatePollInterval and populateTimeout bound the wait for a new
+	// campaign's coupons to become available
+	populatePollInterval = 200 * time.Millisecond
+	populateTimeout      = 2 * time.Minute
 )
 
 func main() {
+	// ─── Flags ───────────────────────────────────────────────────
+	campaignFlag := flag.Int64("campaign", 0, "reuse this campaign instead of creating one")
+	reuseFlag := flag.Bool("reuse", false, "reuse the most recent campaign that still has coupons")
+	delayFlag := flag.Duration("delay", 0, "artificial delay before each request (included in reported latency)")
+	headers := headerFlags{}
+	flag.Var(headers, "header", "extra request header as \"Name: value\" (repeatable), e.g. auth headers")
+	headerPadFlag := flag.Int("header-pad", 0, "bytes of filler sent in an extra header to simulate large headers")
+	retriesFlag := flag.Int("retries", 0, "retry transient failures up to N times with jittered exponential backoff")
+	stopOnSoldOutFlag := flag.Bool("stop-on-sold-out", false, "end the run as soon as the campaign is sold out")
+	protocolFlag := flag.String("protocol", "connect", "wire protocol: connect, grpc or grpcweb")
+	codecFlag := flag.String("codec", "proto", "message codec: proto or json")
+	cpuProfileFlag := flag.String("cpu-profile", "", "save a server CPU profile of the run (from /debug/pprof/profile) to this file")
+	flag.Parse()
+
 	// ─── Fixed Configuration ─────────────────────────────────────
-	campaignIDStr := ""
 	rps := fixedRPSTarget
 	duration := fixedDuration
 	workers := fixedWorkers
-	createCampaign := fixedCreateCamp
+	createCampaign := fixedCreateCamp && *campaignFlag == 0 && !*reuseFlag
 	coupons := fixedCoupons
 
 	// ─── HTTP Client & Transport ─────────────────────────────────
-	transport := &http.Transport{
-		MaxIdleConns:        workers * 4,
-		MaxIdleConnsPerHost: workers * 4,
-		IdleConnTimeout:     90 * time.Second,
+	// setup creates the campaign and checks results without the simulated
+	// client behavior; client sends the load through it
+	setup, err := couponclient.NewClient(couponclient.Options{
+		Protocol:     *protocolFlag,
+		Codec:        *codecFlag,
+		Timeout:      defaultTimeout,
+		MaxIdleConns: workers * 4,
+	})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
 	}
-	httpClient := &http.Client{
-		Transport: transport,
-		Timeout:   defaultTimeout,
+	client := setup.CouponServiceClient
+	if simulation := newSimulationInterceptor(*delayFlag, http.Header(headers), *headerPadFlag); simulation != nil {
+		client = setup.WithInterceptors(simulation).CouponServiceClient
 	}
 
 	// ─── Campaign handling ───────────────────────────────────────
 	var campaignID int64
-	var err error
-	if campaignIDStr == "" || createCampaign {
-		campaignID, err = createNewCampaign(httpClient, coupons)
+	switch {
+	case createCampaign:
+		createStart := time.Now()
+		campaignID, err = createNewCampaign(setup, coupons)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "failed to create campaign: %v\n", err)
 			os.Exit(1)
 		}
-		campaignIDStr = strconv.FormatInt(campaignID, 10)
-		fmt.Printf("✅ 새 캠페인 생성됨: ID %s (%d개 쿠폰)\n", campaignIDStr, coupons)
-	} else {
-		campaignID, err = strconv.ParseInt(campaignIDStr, 10, 64)
-		if err != nil || campaignID <= 0 {
-			fmt.Fprintf(os.Stderr, "invalid campaign id: %q\n", campaignIDStr)
+		// Don't start the load against a campaign still being populated
+		if err := waitForCoupons(setup, campaignID, coupons); err != nil {
+			fmt.Fprintf(os.Stderr, "campaign %d not populated: %v\n", campaignID, err)
+			os.Exit(1)
+		}
+		fmt.Printf("✅ 새 캠페인 생성됨: ID %d (%d개 쿠폰, 생성 %v)\n",
+			campaignID, coupons, time.Since(createStart).Round(time.Millisecond))
+	case *campaignFlag != 0:
+		if *campaignFlag < 0 {
+			fmt.Fprintf(os.Stderr, "invalid campaign id: %d\n", *campaignFlag)
 			os.Exit(1)
 		}
+		campaignID = *campaignFlag
+	default:
+		campaignID, err = findReusableCampaign(client)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to find a reusable campaign: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("♻️  기존 캠페인 재사용: ID %d\n", campaignID)
 	}
+	campaignIDStr := strconv.FormatInt(campaignID, 10)
 
-	client := couponv1connect.NewCouponServiceClient(httpClient, "http://localhost")
+	// Coupons issued before this run, so the consistency check only counts ours
+	baseline, err := getCampaignStats(client, campaignID)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to get campaign stats: %v\n", err)
+		os.Exit(1)
+	}
+	if planned := int64(float64(rps) * duration.Seconds()); int64(baseline.AvailableCount) < planned {
+		fmt.Printf("⚠️  남은 쿠폰(%d개)이 예상 요청 수(%d건)보다 적습니다. 품절 이후 요청은 실패로 집계됩니다.\n",
+			baseline.AvailableCount, planned)
+	}
 
 	// ─── Banner ──────────────────────────────────────────────────
 	fmt.Println("==========================================")
@@ -87,6 +156,7 @@ func main() {
 	fmt.Println("==========================================")
 	fmt.Printf("캠페인 ID  : %s\n", campaignIDStr)
 	fmt.Printf("RPS   : %d\n", rps)
+	fmt.Printf("프로토콜   : %s + %s\n", *protocolFlag, *codecFlag)
 	fmt.Printf("테스트 시간: %v\n", duration)
 	fmt.Println("==========================================")
 
@@ -96,6 +166,7 @@ func main() {
 		burst = 1
 	}
 	limiter := rate.NewLimiter(rate.Limit(rps), burst)
+	retry := retryPolicy{retries: max(*retriesFlag, 0), limiter: limiter}
 
 	ctx, cancel := context.WithTimeout(context.Background(), duration)
 	defer cancel()
@@ -107,6 +178,20 @@ func main() {
 	latencyChan := make(chan time.Duration, 4096)
 	go trackP95(latencyChan, &result)
 
+	// Sample how many workers wait on the server to locate the bottleneck
+	saturation := newSaturationMonitor(workers, &result)
+	monitorDone := make(chan struct{})
+	go func() {
+		defer close(monitorDone)
+		saturation.run(ctx)
+	}()
+
+	// Profile the server over exactly the measured window
+	var profileDone <-chan error
+	if *cpuProfileFlag != "" {
+		profileDone = startCPUProfile(couponclient.DefaultBaseURL, *cpuProfileFlag, duration, http.Header(headers))
+	}
+
 	// ─── Workers ────────────────────────────────────────────────
 	for i := 0; i < workers; i++ {
 		wg.Add(1)
@@ -116,7 +201,20 @@ func main() {
 				if err := limiter.Wait(ctx); err != nil { // context cancelled → exit
 					return
 				}
-				doRequest(ctx, client, campaignID, &result, latencyChan)
+				delay := doRequest(ctx, client, campaignID, retry, &result, latencyChan)
+				// Further requests would only count as sold-out failures
+				if *stopOnSoldOutFlag && atomic.LoadInt32(&result.SoldOut) == 1 {
+					cancel()
+					return
+				}
+				// Back off as instructed by rate-limit rejections
+				if delay > 0 {
+					select {
+					case <-time.After(delay):
+					case <-ctx.Done():
+						return
+					}
+				}
 			}
 		}()
 	}
@@ -127,6 +225,7 @@ func main() {
 	// ─── Cleanup ────────────────────────────────────────────────
 	wg.Wait()
 	close(latencyChan)
+	<-monitorDone
 
 	totalDur := time.Since(start)
 
@@ -134,10 +233,24 @@ func main() {
 	fmt.Println("==========================================")
 	fmt.Println("📊 성능 테스트 결과")
 	fmt.Println("==========================================")
+	if result.SoldOut == 1 {
+		if *stopOnSoldOutFlag {
+			fmt.Println("🛑 캠페인 쿠폰이 모두 소진되어 테스트를 조기 종료했습니다.")
+		} else {
+			fmt.Println("⚠️  테스트 중 캠페인 쿠폰이 모두 소진되었습니다. 이후 요청은 실패로 집계됩니다 (-stop-on-sold-out 참고).")
+		}
+		fmt.Printf("시작 시 남은 쿠폰  : %d\n", baseline.AvailableCount)
+		fmt.Printf("이번 실행 발급     : %d\n", result.SuccessCount)
+	}
 	fmt.Printf("테스트 시간        : %.2f초\n", totalDur.Seconds())
 	fmt.Printf("총 요청 수         : %d\n", result.TotalRequests)
 	fmt.Printf("성공한 요청        : %d\n", result.SuccessCount)
 	fmt.Printf("실패한 요청        : %d\n", result.ErrorCount)
+	printErrorBreakdown(&result)
+	if retry.retries > 0 {
+		fmt.Printf("재시도 횟수        : %d\n", result.RetryCount)
+		fmt.Printf("재시도 후 성공     : %d\n", result.RetriedSuccessCount)
+	}
 
 	actualRPS := float64(result.SuccessCount) / totalDur.Seconds()
 	successRate := float64(result.SuccessCount) / float64(result.TotalRequests) * 100
@@ -151,9 +264,19 @@ func main() {
 	fmt.Printf("성공률             : %.2f%%\n", successRate)
 	fmt.Printf("평균 레이턴시      : %v\n", avgLatency)
 	fmt.Printf("P95 레이턴시       : %v\n", time.Duration(result.P95Latency))
+	saturation.report()
 
 	fmt.Printf("⚠️  현재 성능: %.2f RPS\n", actualRPS)
 
+	// The profile spans the full duration even when the run ended early
+	if profileDone != nil {
+		if err := <-profileDone; err != nil {
+			fmt.Printf("⚠️  서버 CPU 프로파일 수집 실패: %v\n", err)
+		} else {
+			fmt.Printf("🔥 서버 CPU 프로파일 저장됨: %s (go tool pprof -http=: %s)\n", *cpuProfileFlag, *cpuProfileFlag)
+		}
+	}
+
 	fmt.Println("==========================================")
 
 	// ─── Data Consistency Check ─────────────────────────────────
@@ -161,7 +284,7 @@ func main() {
 	fmt.Println("🔍 데이터 정합성 검증")
 	fmt.Println("==========================================")
 
-	if err := verifyDataConsistency(httpClient, campaignID, result.SuccessCount); err != nil {
+	if err := verifyDataConsistency(setup, campaignID, int64(baseline.IssuedCount)+result.SuccessCount); err != nil {
 		fmt.Printf("❌ 정합성 검증 실패: %v\n", err)
 	} else {
 		fmt.Println("✅ 데이터 정합성 확인 완료")
@@ -170,8 +293,7 @@ func main() {
 }
 
 // createNewCampaign creates a new campaign with the specified number of coupons.
-func createNewCampaign(httpClient *http.Client, coupons int) (int64, error) {
-	client := couponv1connect.NewCouponServiceClient(httpClient, "http://localhost")
+func createNewCampaign(client couponv1connect.CouponServiceClient, coupons int) (int64, error) {
 
 	startTime := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
 	req := connect.NewRequest(&couponv1.CreateCampaignRequest{
@@ -192,23 +314,139 @@ func createNewCampaign(httpClient *http.Client, coupons int) (int64, error) {
 	return resp.Msg.Campaign.Id, nil
 }
 
-// doRequest performs a single IssueCoupon RPC and collects metrics.
-func doRequest(parent context.Context, client couponv1connect.CouponServiceClient, campaignID int64, result *PerfResult, latencyChan chan<- time.Duration) {
-	// Use independent context to avoid cancellation when test ends
-	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+// waitForCoupons polls the campaign's stats until all of its coupons are
+// available, so a run never starts against a campaign whose coupons are still
+// being generated. Failed polls are retried until populateTimeout.
+func waitForCoupons(client couponv1connect.CouponServiceClient, campaignID int64, coupons int) error {
+	deadline := time.Now().Add(populateTimeout)
+	for {
+		stats, err := getCampaignStats(client, campaignID)
+		if err == nil && int(stats.AvailableCount) >= coupons {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			if err != nil {
+				return fmt.Errorf("timed out after %v: %w", populateTimeout, err)
+			}
+			return fmt.Errorf("timed out after %v with %d of %d coupons available",
+				populateTimeout, stats.AvailableCount, coupons)
+		}
+		time.Sleep(populatePollInterval)
+	}
+}
+
+// printErrorBreakdown prints failed requests per connect error code, so a
+// sold-out campaign (resource_exhausted) is easy to tell apart from server
+// failures, with the request IDs of a few failures of each code to look up
+// in the server logs
+func printErrorBreakdown(result *PerfResult) {
+	if result.ErrorCount == 0 {
+		return
+	}
+	for code, count := range result.ErrorsByCode {
+		if count == 0 {
+			continue
+		}
+		fmt.Printf("  - %-17s: %d\n", connect.Code(code), count)
+		for _, sample := range result.FailureSamples.of(connect.Code(code)) {
+			fmt.Printf("      request_id=%s: %s\n", sample.requestID, sample.err)
+		}
+	}
+	if result.EmptyCouponCount > 0 {
+		fmt.Printf("  - %-17s: %d\n", "empty coupon", result.EmptyCouponCount)
+	}
+}
+
+// findReusableCampaign returns the most recent campaign that still has
+// available coupons
+func findReusableCampaign(client couponv1connect.CouponServiceClient) (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
-	req := connect.NewRequest(&couponv1.IssueCouponRequest{CampaignId: campaignID})
+	// Campaigns are listed oldest first, so collect all IDs before scanning
+	var ids []int64
+	var pageToken string
+	for {
+		resp, err := client.ListCampaigns(ctx, connect.NewRequest(&couponv1.ListCampaignsRequest{
+			Page: &couponv1.PageRequest{PageSize: 100, PageToken: pageToken},
+		}))
+		if err != nil {
+			return 0, fmt.Errorf("list campaigns failed: %w", err)
+		}
+		for _, campaign := range resp.Msg.Campaigns {
+			ids = append(ids, campaign.Id)
+		}
+		pageToken = resp.Msg.GetPage().GetNextPageToken()
+		if pageToken == "" {
+			break
+		}
+	}
+
+	for i := len(ids) - 1; i >= 0; i-- {
+		resp, err := client.GetRemaining(ctx, connect.NewRequest(&couponv1.GetRemainingRequest{CampaignId: ids[i]}))
+		if err != nil {
+			return 0, fmt.Errorf("get remaining failed for campaign %d: %w", ids[i], err)
+		}
+		if resp.Msg.AvailableCount > 0 {
+			return ids[i], nil
+		}
+	}
+	return 0, fmt.Errorf("no campaign with available coupons")
+}
+
+// getCampaignStats returns the coupon counts of a campaign
+func getCampaignStats(client couponv1connect.CouponServiceClient, campaignID int64) (*couponv1.CampaignStats, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	resp, err := client.GetCampaignStats(ctx, connect.NewRequest(&couponv1.GetCampaignStatsRequest{CampaignId: campaignID}))
+	if err != nil {
+		return nil, err
+	}
+	return resp.Msg.Stats, nil
+}
+
+// doRequest performs a single IssueCoupon RPC, retrying transient failures
+// per the retry policy, and collects metrics. Latency spans all attempts.
+// It returns the server's retry hint when the request was rate limited.
+func doRequest(parent context.Context, client couponv1connect.CouponServiceClient, campaignID int64, retry retryPolicy, result *PerfResult, latencyChan chan<- time.Duration) time.Duration {
+	requestID := uuid.NewString()
+	msg := &couponv1.IssueCouponRequest{Campaign: &couponv1.IssueCouponRequest_CampaignId{CampaignId: campaignID}}
+	if retry.retries > 0 {
+		// A retry after a lost response must not issue a second coupon
+		msg.IdempotencyKey = requestID
+	}
 
 	start := time.Now()
 	atomic.AddInt64(&result.TotalRequests, 1)
 
-	resp, err := client.IssueCoupon(ctx, req)
+	var resp *connect.Response[couponv1.IssueCouponResponse]
+	var err error
+	for attempt := 0; ; attempt++ {
+		atomic.AddInt64(&result.InFlight, 1)
+		resp, err = issueOnce(client, msg, requestID)
+		atomic.AddInt64(&result.InFlight, -1)
+		if err == nil || attempt >= retry.retries || !couponclient.Retryable(err) || !retry.wait(parent, attempt, err) {
+			if err == nil && attempt > 0 {
+				atomic.AddInt64(&result.RetriedSuccessCount, 1)
+			}
+			break
+		}
+		atomic.AddInt64(&result.RetryCount, 1)
+	}
 	latency := time.Since(start)
 
 	if err != nil {
 		atomic.AddInt64(&result.ErrorCount, 1)
-		return
+		code := connect.CodeOf(err)
+		if int(code) < len(result.ErrorsByCode) {
+			atomic.AddInt64(&result.ErrorsByCode[code], 1)
+		}
+		if couponclient.IsSoldOut(err) {
+			atomic.StoreInt32(&result.SoldOut, 1)
+		}
+		result.FailureSamples.add(code, requestID, err)
+		return couponclient.RetryDelay(err)
 	}
 	if resp.Msg.GetCoupon() != nil && resp.Msg.Coupon.Code != "" {
 		atomic.AddInt64(&result.SuccessCount, 1)
@@ -219,7 +457,20 @@ func doRequest(parent context.Context, client couponv1connect.CouponServiceClien
 		}
 	} else {
 		atomic.AddInt64(&result.ErrorCount, 1)
+		atomic.AddInt64(&result.EmptyCouponCount, 1)
 	}
+	return 0
+}
+
+// issueOnce sends one IssueCoupon attempt
+func issueOnce(client couponv1connect.CouponServiceClient, msg *couponv1.IssueCouponRequest, requestID string) (*connect.Response[couponv1.IssueCouponResponse], error) {
+	// Use independent context to avoid cancellation when test ends
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	req := connect.NewRequest(msg)
+	req.Header().Set(interceptor.RequestIDHeader, requestID)
+	return client.IssueCoupon(ctx, req)
 }
 
 // trackP95 maintains a best‑effort rolling P95 latency estimation.
@@ -276,11 +527,10 @@ func quickSort(arr []int64) {
 }
 
 // verifyDataConsistency checks if the issued coupon count matches the database state
-func verifyDataConsistency(httpClient *http.Client, campaignID int64, expectedIssued int64) error {
-	client := couponv1connect.NewCouponServiceClient(httpClient, "http://localhost")
+func verifyDataConsistency(client couponv1connect.CouponServiceClient, campaignID int64, expectedIssued int64) error {
 
 	req := connect.NewRequest(&couponv1.GetCampaignRequest{
-		CampaignId: campaignID,
+		Campaign: &couponv1.GetCampaignRequest_CampaignId{CampaignId: campaignID},
 	})
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
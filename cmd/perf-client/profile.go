@@ -0,0 +1,58 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"os"
+	"time"
+)
+
+// profileGrace is how long past the profiled window the server may take to
+// return the profile
+const profileGrace = 30 * time.Second
+
+// startCPUProfile asks the server's /debug/pprof/profile for a CPU profile
+// covering the next duration and writes it to path in the background. The
+// returned channel yields the outcome once the profile is saved; the run
+// doesn't depend on it, so a server without pprof only costs a warning.
+func startCPUProfile(baseURL, path string, duration time.Duration, header http.Header) <-chan error {
+	done := make(chan error, 1)
+	go func() {
+		done <- captureCPUProfile(baseURL, path, duration, header)
+	}()
+	return done
+}
+
+// captureCPUProfile fetches a CPU profile of duration and saves it to path
+func captureCPUProfile(baseURL, path string, duration time.Duration, header http.Header) error {
+	seconds := max(int(math.Ceil(duration.Seconds())), 1)
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/debug/pprof/profile?seconds=%d", baseURL, seconds), nil)
+	if err != nil {
+		return err
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+
+	client := &http.Client{Timeout: time.Duration(seconds)*time.Second + profileGrace}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("server returned %s; is pprof enabled?", resp.Status)
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, resp.Body); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to save profile: %w", err)
+	}
+	return f.Close()
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	couponclient "github.com/kkkkikiki/coupon/internal/client"
+)
+
+// retryPolicy retries transient IssueCoupon failures like a real client would.
+// Retries take a token from the shared limiter, so they count against the
+// RPS ceiling like first attempts.
+type retryPolicy struct {
+	retries int
+	limiter *rate.Limiter
+}
+
+// wait sleeps before retry number attempt and takes a rate limiter token. It
+// returns false when the retry would not start before the test deadline.
+func (p retryPolicy) wait(ctx context.Context, attempt int, err error) bool {
+	delay := couponclient.Backoff(attempt, couponclient.RetryDelay(err))
+	if deadline, ok := ctx.Deadline(); ok && time.Now().Add(delay).After(deadline) {
+		return false
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+		return false
+	}
+	return p.limiter.Wait(ctx) == nil
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+// saturationSampleInterval is how often the number of workers blocked on a
+// response is sampled
+const saturationSampleInterval = 500 * time.Millisecond
+
+// saturationMonitor samples PerfResult.InFlight to tell whether the RPS
+// ceiling is set by the client (workers idle, waiting on the rate limiter)
+// or by the server (every worker blocked waiting on a response)
+type saturationMonitor struct {
+	workers int
+	result  *PerfResult
+	start   time.Time
+
+	samples   int64
+	busySum   int64
+	peak      int64
+	saturated int64
+}
+
+func newSaturationMonitor(workers int, result *PerfResult) *saturationMonitor {
+	return &saturationMonitor{workers: workers, result: result}
+}
+
+// run samples until ctx is done, logging when all workers become saturated
+// and when they recover
+func (m *saturationMonitor) run(ctx context.Context) {
+	m.start = time.Now()
+	ticker := time.NewTicker(saturationSampleInterval)
+	defer ticker.Stop()
+
+	wasSaturated := false
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		busy := atomic.LoadInt64(&m.result.InFlight)
+		m.samples++
+		m.busySum += busy
+		m.peak = max(m.peak, busy)
+
+		saturated := busy >= int64(m.workers)
+		if saturated {
+			m.saturated++
+		}
+		elapsed := time.Since(m.start).Truncate(100 * time.Millisecond)
+		switch {
+		case saturated && !wasSaturated:
+			fmt.Printf("⏳ [%v] 워커 %d개 모두 응답 대기 중 (P95 %v): 서버가 병목입니다\n",
+				elapsed, m.workers, time.Duration(atomic.LoadInt64(&m.result.P95Latency)))
+		case !saturated && wasSaturated:
+			fmt.Printf("✅ [%v] 워커 포화 해소 (응답 대기 %d/%d)\n", elapsed, busy, m.workers)
+		}
+		wasSaturated = saturated
+	}
+}
+
+// report prints the sampled worker usage and which side limited throughput.
+// Call it after run has returned.
+func (m *saturationMonitor) report() {
+	if m.samples == 0 {
+		return
+	}
+	avg := float64(m.busySum) / float64(m.samples)
+	saturatedPct := float64(m.saturated) / float64(m.samples) * 100
+
+	fmt.Printf("응답 대기 워커     : 평균 %.1f / 최대 %d (전체 %d)\n", avg, m.peak, m.workers)
+	fmt.Printf("워커 포화 비율     : %.1f%%\n", saturatedPct)
+	if saturatedPct >= 50 {
+		fmt.Println("🔎 병목: 서버 (워커 대부분이 응답을 기다림, 워커를 늘려도 서버가 느리면 RPS가 오르지 않습니다)")
+	} else {
+		fmt.Println("🔎 병목: 클라이언트 (워커 여유 있음, RPS 상한은 클라이언트 레이트 리미터 또는 재시도 대기)")
+	}
+}
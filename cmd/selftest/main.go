@@ -0,0 +1,143 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"connectrpc.com/connect"
+
+	couponv1 "github.com/kkkkikiki/coupon/gen/coupon/v1"
+	"github.com/kkkkikiki/coupon/gen/coupon/v1/couponv1connect"
+	couponclient "github.com/kkkkikiki/coupon/internal/client"
+	"github.com/kkkkikiki/coupon/internal/interceptor"
+)
+
+const (
+	selftestCoupons = 3
+	selftestIssues  = 2
+	stepTimeout     = 10 * time.Second
+)
+
+// selftest runs a full campaign lifecycle against a deployed server:
+// create → issue → verify stats → delete → verify deletion.
+func main() {
+	target := flag.String("url", "http://localhost", "base URL of the coupon service")
+	adminKey := flag.String("admin-key", os.Getenv("APP_ADMIN_API_KEY"), "admin API key for DeleteCampaign")
+	flag.Parse()
+
+	client, err := couponclient.NewClient(couponclient.Options{BaseURL: *target, Timeout: stepTimeout})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	fmt.Println("==========================================")
+	fmt.Println("🩺 쿠폰 서비스 셀프 테스트")
+	fmt.Println("==========================================")
+	fmt.Printf("대상: %s\n", *target)
+
+	var campaignID int64
+	steps := []struct {
+		name string
+		run  func(ctx context.Context) error
+	}{
+		{"캠페인 생성", func(ctx context.Context) error {
+			resp, err := client.CreateCampaign(ctx, connect.NewRequest(&couponv1.CreateCampaignRequest{
+				AvailableCoupons: selftestCoupons,
+				StartDate:        timestamppb.New(time.Now().Add(-time.Minute)),
+			}))
+			if err != nil {
+				return err
+			}
+			campaignID = resp.Msg.GetCampaign().GetId()
+			if campaignID == 0 {
+				return errors.New("campaign id missing in response")
+			}
+			return nil
+		}},
+		{"쿠폰 발급", func(ctx context.Context) error {
+			for i := 0; i < selftestIssues; i++ {
+				resp, err := client.IssueCoupon(ctx, connect.NewRequest(&couponv1.IssueCouponRequest{Campaign: &couponv1.IssueCouponRequest_CampaignId{CampaignId: campaignID}}))
+				if err != nil {
+					return err
+				}
+				if resp.Msg.GetCoupon().GetCode() == "" {
+					return errors.New("issued coupon has no code")
+				}
+			}
+			return nil
+		}},
+		{"통계 확인", func(ctx context.Context) error {
+			resp, err := client.GetCampaignStats(ctx, connect.NewRequest(&couponv1.GetCampaignStatsRequest{CampaignId: campaignID}))
+			if err != nil {
+				return err
+			}
+			stats := resp.Msg.GetStats()
+			if stats.GetTotalCount() != selftestCoupons ||
+				stats.GetIssuedCount() != selftestIssues ||
+				stats.GetAvailableCount() != selftestCoupons-selftestIssues {
+				return fmt.Errorf("unexpected stats: total=%d issued=%d available=%d",
+					stats.GetTotalCount(), stats.GetIssuedCount(), stats.GetAvailableCount())
+			}
+			return nil
+		}},
+		{"캠페인 삭제", func(ctx context.Context) error {
+			_, err := client.DeleteCampaign(ctx, newDeleteRequest(campaignID, *adminKey))
+			return err
+		}},
+		{"삭제 확인", func(ctx context.Context) error {
+			_, err := client.GetCampaign(ctx, connect.NewRequest(&couponv1.GetCampaignRequest{Campaign: &couponv1.GetCampaignRequest_CampaignId{CampaignId: campaignID}}))
+			if connect.CodeOf(err) != connect.CodeNotFound {
+				return fmt.Errorf("expected NotFound after delete, got %v", err)
+			}
+			return nil
+		}},
+	}
+
+	for _, step := range steps {
+		ctx, cancel := context.WithTimeout(context.Background(), stepTimeout)
+		err := step.run(ctx)
+		cancel()
+		if err != nil {
+			fmt.Printf("❌ %s 실패: %v\n", step.name, err)
+			cleanup(client, campaignID, *adminKey)
+			fmt.Println("==========================================")
+			os.Exit(1)
+		}
+		fmt.Printf("✅ %s\n", step.name)
+	}
+
+	fmt.Println("==========================================")
+	fmt.Println("✅ 셀프 테스트 통과")
+}
+
+// cleanup best-effort deletes the campaign left behind by a failed run
+func cleanup(client couponv1connect.CouponServiceClient, campaignID int64, adminKey string) {
+	if campaignID == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), stepTimeout)
+	defer cancel()
+
+	_, err := client.DeleteCampaign(ctx, newDeleteRequest(campaignID, adminKey))
+	if err != nil && connect.CodeOf(err) != connect.CodeNotFound {
+		fmt.Printf("⚠️  테스트 캠페인 %d 정리 실패: %v\n", campaignID, err)
+	}
+}
+
+// newDeleteRequest builds an admin-authenticated DeleteCampaign request. The
+// test campaign always has issued coupons, so the delete is forced.
+func newDeleteRequest(campaignID int64, adminKey string) *connect.Request[couponv1.DeleteCampaignRequest] {
+	req := connect.NewRequest(&couponv1.DeleteCampaignRequest{CampaignId: campaignID, Force: true})
+	if adminKey != "" {
+		req.Header().Set(interceptor.AdminKeyHeader, adminKey)
+	}
+	return req
+}
@@ -9,6 +9,7 @@ package couponv1
 import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
 	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
@@ -22,32 +23,3753 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+// IssuanceOrder is the order in which a campaign's available coupons are issued
+type IssuanceOrder int32
+
+const (
+	IssuanceOrder_ISSUANCE_ORDER_UNSPECIFIED IssuanceOrder = 0 // Same as CREATED
+	IssuanceOrder_ISSUANCE_ORDER_CREATED     IssuanceOrder = 1 // Oldest coupons first
+	// A fixed pseudo-random order derived from shuffle_seed and each code, so
+	// the order can be reproduced for audits
+	IssuanceOrder_ISSUANCE_ORDER_SHUFFLED IssuanceOrder = 2
+	// Soonest-expiring coupons first, by their pool's expires_at; coupons
+	// without an expiry come last
+	IssuanceOrder_ISSUANCE_ORDER_EXPIRES_AT IssuanceOrder = 3
+	IssuanceOrder_ISSUANCE_ORDER_PRIORITY   IssuanceOrder = 4 // Coupons of the highest-priority pool first
+)
+
+// Enum value maps for IssuanceOrder.
+var (
+	IssuanceOrder_name = map[int32]string{
+		0: "ISSUANCE_ORDER_UNSPECIFIED",
+		1: "ISSUANCE_ORDER_CREATED",
+		2: "ISSUANCE_ORDER_SHUFFLED",
+		3: "ISSUANCE_ORDER_EXPIRES_AT",
+		4: "ISSUANCE_ORDER_PRIORITY",
+	}
+	IssuanceOrder_value = map[string]int32{
+		"ISSUANCE_ORDER_UNSPECIFIED": 0,
+		"ISSUANCE_ORDER_CREATED":     1,
+		"ISSUANCE_ORDER_SHUFFLED":    2,
+		"ISSUANCE_ORDER_EXPIRES_AT":  3,
+		"ISSUANCE_ORDER_PRIORITY":    4,
+	}
+)
+
+func (x IssuanceOrder) Enum() *IssuanceOrder {
+	p := new(IssuanceOrder)
+	*p = x
+	return p
+}
+
+func (x IssuanceOrder) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (IssuanceOrder) Descriptor() protoreflect.EnumDescriptor {
+	return file_coupon_v1_coupon_proto_enumTypes[0].Descriptor()
+}
+
+func (IssuanceOrder) Type() protoreflect.EnumType {
+	return &file_coupon_v1_coupon_proto_enumTypes[0]
+}
+
+func (x IssuanceOrder) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use IssuanceOrder.Descriptor instead.
+func (IssuanceOrder) EnumDescriptor() ([]byte, []int) {
+	return file_coupon_v1_coupon_proto_rawDescGZIP(), []int{0}
+}
+
+// CouponStatus is the lifecycle state of a coupon
+type CouponStatus int32
+
+const (
+	CouponStatus_COUPON_STATUS_UNSPECIFIED CouponStatus = 0
+	CouponStatus_COUPON_STATUS_AVAILABLE   CouponStatus = 1 // Generated and not yet issued
+	CouponStatus_COUPON_STATUS_ISSUED      CouponStatus = 2 // Issued to a user
+	CouponStatus_COUPON_STATUS_RESERVED    CouponStatus = 3 // Held for a user pending confirmation
+	CouponStatus_COUPON_STATUS_REDEEMED    CouponStatus = 4 // Used by its holder
+	CouponStatus_COUPON_STATUS_EXPIRED     CouponStatus = 5 // Issued but past its expiry
+	CouponStatus_COUPON_STATUS_REVOKED     CouponStatus = 6 // Invalidated by an operator
+)
+
+// Enum value maps for CouponStatus.
+var (
+	CouponStatus_name = map[int32]string{
+		0: "COUPON_STATUS_UNSPECIFIED",
+		1: "COUPON_STATUS_AVAILABLE",
+		2: "COUPON_STATUS_ISSUED",
+		3: "COUPON_STATUS_RESERVED",
+		4: "COUPON_STATUS_REDEEMED",
+		5: "COUPON_STATUS_EXPIRED",
+		6: "COUPON_STATUS_REVOKED",
+	}
+	CouponStatus_value = map[string]int32{
+		"COUPON_STATUS_UNSPECIFIED": 0,
+		"COUPON_STATUS_AVAILABLE":   1,
+		"COUPON_STATUS_ISSUED":      2,
+		"COUPON_STATUS_RESERVED":    3,
+		"COUPON_STATUS_REDEEMED":    4,
+		"COUPON_STATUS_EXPIRED":     5,
+		"COUPON_STATUS_REVOKED":     6,
+	}
+)
+
+func (x CouponStatus) Enum() *CouponStatus {
+	p := new(CouponStatus)
+	*p = x
+	return p
+}
+
+func (x CouponStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (CouponStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_coupon_v1_coupon_proto_enumTypes[1].Descriptor()
+}
+
+func (CouponStatus) Type() protoreflect.EnumType {
+	return &file_coupon_v1_coupon_proto_enumTypes[1]
+}
+
+func (x CouponStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use CouponStatus.Descriptor instead.
+func (CouponStatus) EnumDescriptor() ([]byte, []int) {
+	return file_coupon_v1_coupon_proto_rawDescGZIP(), []int{1}
+}
+
+// CampaignState tells whether a campaign issues coupons, evaluated with the
+// same rules as IssueCoupon
+type CampaignState int32
+
+const (
+	CampaignState_CAMPAIGN_STATE_UNSPECIFIED CampaignState = 0
+	CampaignState_CAMPAIGN_STATE_UPCOMING    CampaignState = 1 // Before start_date, or outside the daily issuance window
+	CampaignState_CAMPAIGN_STATE_ACTIVE      CampaignState = 2 // Issuing coupons
+	CampaignState_CAMPAIGN_STATE_ENDED       CampaignState = 3 // Deleted, out of coupons or out of budget
+)
+
+// Enum value maps for CampaignState.
+var (
+	CampaignState_name = map[int32]string{
+		0: "CAMPAIGN_STATE_UNSPECIFIED",
+		1: "CAMPAIGN_STATE_UPCOMING",
+		2: "CAMPAIGN_STATE_ACTIVE",
+		3: "CAMPAIGN_STATE_ENDED",
+	}
+	CampaignState_value = map[string]int32{
+		"CAMPAIGN_STATE_UNSPECIFIED": 0,
+		"CAMPAIGN_STATE_UPCOMING":    1,
+		"CAMPAIGN_STATE_ACTIVE":      2,
+		"CAMPAIGN_STATE_ENDED":       3,
+	}
+)
+
+func (x CampaignState) Enum() *CampaignState {
+	p := new(CampaignState)
+	*p = x
+	return p
+}
+
+func (x CampaignState) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (CampaignState) Descriptor() protoreflect.EnumDescriptor {
+	return file_coupon_v1_coupon_proto_enumTypes[2].Descriptor()
+}
+
+func (CampaignState) Type() protoreflect.EnumType {
+	return &file_coupon_v1_coupon_proto_enumTypes[2]
+}
+
+func (x CampaignState) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use CampaignState.Descriptor instead.
+func (CampaignState) EnumDescriptor() ([]byte, []int) {
+	return file_coupon_v1_coupon_proto_rawDescGZIP(), []int{2}
+}
+
+// IssueOutcome is the result of an IssueCoupon call made with report_outcome
+type IssueOutcome int32
+
+const (
+	IssueOutcome_ISSUE_OUTCOME_UNSPECIFIED IssueOutcome = 0
+	IssueOutcome_ISSUE_OUTCOME_ISSUED      IssueOutcome = 1 // coupon is set
+	IssueOutcome_ISSUE_OUTCOME_SOLD_OUT    IssueOutcome = 2 // No coupons or budget left; sold_out is set
+	// Before start_date, or outside the daily issuance window (issuance_window
+	// is set then)
+	IssueOutcome_ISSUE_OUTCOME_NOT_STARTED IssueOutcome = 3
+)
+
+// Enum value maps for IssueOutcome.
+var (
+	IssueOutcome_name = map[int32]string{
+		0: "ISSUE_OUTCOME_UNSPECIFIED",
+		1: "ISSUE_OUTCOME_ISSUED",
+		2: "ISSUE_OUTCOME_SOLD_OUT",
+		3: "ISSUE_OUTCOME_NOT_STARTED",
+	}
+	IssueOutcome_value = map[string]int32{
+		"ISSUE_OUTCOME_UNSPECIFIED": 0,
+		"ISSUE_OUTCOME_ISSUED":      1,
+		"ISSUE_OUTCOME_SOLD_OUT":    2,
+		"ISSUE_OUTCOME_NOT_STARTED": 3,
+	}
+)
+
+func (x IssueOutcome) Enum() *IssueOutcome {
+	p := new(IssueOutcome)
+	*p = x
+	return p
+}
+
+func (x IssueOutcome) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (IssueOutcome) Descriptor() protoreflect.EnumDescriptor {
+	return file_coupon_v1_coupon_proto_enumTypes[3].Descriptor()
+}
+
+func (IssueOutcome) Type() protoreflect.EnumType {
+	return &file_coupon_v1_coupon_proto_enumTypes[3]
+}
+
+func (x IssueOutcome) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use IssueOutcome.Descriptor instead.
+func (IssueOutcome) EnumDescriptor() ([]byte, []int) {
+	return file_coupon_v1_coupon_proto_rawDescGZIP(), []int{3}
+}
+
+// SoldOutReason tells why a campaign can't issue more coupons
+type SoldOutReason int32
+
+const (
+	SoldOutReason_SOLD_OUT_REASON_UNSPECIFIED      SoldOutReason = 0
+	SoldOutReason_SOLD_OUT_REASON_NO_COUPONS       SoldOutReason = 1 // Every coupon has been issued
+	SoldOutReason_SOLD_OUT_REASON_BUDGET_EXHAUSTED SoldOutReason = 2 // Issuing another coupon would exceed the campaign budget
+)
+
+// Enum value maps for SoldOutReason.
+var (
+	SoldOutReason_name = map[int32]string{
+		0: "SOLD_OUT_REASON_UNSPECIFIED",
+		1: "SOLD_OUT_REASON_NO_COUPONS",
+		2: "SOLD_OUT_REASON_BUDGET_EXHAUSTED",
+	}
+	SoldOutReason_value = map[string]int32{
+		"SOLD_OUT_REASON_UNSPECIFIED":      0,
+		"SOLD_OUT_REASON_NO_COUPONS":       1,
+		"SOLD_OUT_REASON_BUDGET_EXHAUSTED": 2,
+	}
+)
+
+func (x SoldOutReason) Enum() *SoldOutReason {
+	p := new(SoldOutReason)
+	*p = x
+	return p
+}
+
+func (x SoldOutReason) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (SoldOutReason) Descriptor() protoreflect.EnumDescriptor {
+	return file_coupon_v1_coupon_proto_enumTypes[4].Descriptor()
+}
+
+func (SoldOutReason) Type() protoreflect.EnumType {
+	return &file_coupon_v1_coupon_proto_enumTypes[4]
+}
+
+func (x SoldOutReason) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use SoldOutReason.Descriptor instead.
+func (SoldOutReason) EnumDescriptor() ([]byte, []int) {
+	return file_coupon_v1_coupon_proto_rawDescGZIP(), []int{4}
+}
+
 // Campaign represents a coupon campaign
 type Campaign struct {
 	state             protoimpl.MessageState `protogen:"open.v1"`
 	Id                int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
-	AvailableCoupons  int32                  `protobuf:"varint,2,opt,name=available_coupons,json=availableCoupons,proto3" json:"available_coupons,omitempty"`     // Number of available coupons
-	StartDate         *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=start_date,json=startDate,proto3" json:"start_date,omitempty"`                           // Specific start date and time
-	IssuedCouponCodes []string               `protobuf:"bytes,4,rep,name=issued_coupon_codes,json=issuedCouponCodes,proto3" json:"issued_coupon_codes,omitempty"` // Only successfully issued coupon codes
+	AvailableCoupons  int32                  `protobuf:"varint,2,opt,name=available_coupons,json=availableCoupons,proto3" json:"available_coupons,omitempty"`                      // Number of available coupons
+	StartDate         *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=start_date,json=startDate,proto3" json:"start_date,omitempty"`                                            // Specific start date and time
+	IssuedCouponCodes []string               `protobuf:"bytes,4,rep,name=issued_coupon_codes,json=issuedCouponCodes,proto3" json:"issued_coupon_codes,omitempty"`                  // Only successfully issued coupon codes
+	CodeFormat        *CodeFormat            `protobuf:"bytes,5,opt,name=code_format,json=codeFormat,proto3" json:"code_format,omitempty"`                                         // Format used to generate the campaign's coupon codes
+	DiscountValue     int64                  `protobuf:"varint,6,opt,name=discount_value,json=discountValue,proto3" json:"discount_value,omitempty"`                               // Value of one coupon in minor currency units
+	Budget            int64                  `protobuf:"varint,7,opt,name=budget,proto3" json:"budget,omitempty"`                                                                  // Maximum total value of issued coupons; 0 means unlimited
+	IssuedValue       int64                  `protobuf:"varint,8,opt,name=issued_value,json=issuedValue,proto3" json:"issued_value,omitempty"`                                     // Total value of coupons issued so far
+	PerUserLimit      int32                  `protobuf:"varint,9,opt,name=per_user_limit,json=perUserLimit,proto3" json:"per_user_limit,omitempty"`                                // Maximum coupons one user may receive; 0 means unlimited
+	DeletedAt         *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=deleted_at,json=deletedAt,proto3" json:"deleted_at,omitempty"`                                           // Set only for soft-deleted campaigns
+	MaxIssueRps       float64                `protobuf:"fixed64,11,opt,name=max_issue_rps,json=maxIssueRps,proto3" json:"max_issue_rps,omitempty"`                                 // Issuance requests per second; 0 uses the server default
+	IssuanceOrder     IssuanceOrder          `protobuf:"varint,12,opt,name=issuance_order,json=issuanceOrder,proto3,enum=coupon.v1.IssuanceOrder" json:"issuance_order,omitempty"` // Order in which available coupons are issued
+	ShuffleSeed       int64                  `protobuf:"varint,13,opt,name=shuffle_seed,json=shuffleSeed,proto3" json:"shuffle_seed,omitempty"`                                    // Seed of the shuffled issuance order; 0 unless shuffled
+	Slug              string                 `protobuf:"bytes,14,opt,name=slug,proto3" json:"slug,omitempty"`                                                                      // Human-readable unique name; empty when the campaign has none
+	IssuanceWindow    *IssuanceWindow        `protobuf:"bytes,15,opt,name=issuance_window,json=issuanceWindow,proto3" json:"issuance_window,omitempty"`                            // Daily window coupons are issued in; unset when always open
 	unknownFields     protoimpl.UnknownFields
 	sizeCache         protoimpl.SizeCache
 }
 
-func (x *Campaign) Reset() {
-	*x = Campaign{}
-	mi := &file_coupon_v1_coupon_proto_msgTypes[0]
+func (x *Campaign) Reset() {
+	*x = Campaign{}
+	mi := &file_coupon_v1_coupon_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Campaign) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Campaign) ProtoMessage() {}
+
+func (x *Campaign) ProtoReflect() protoreflect.Message {
+	mi := &file_coupon_v1_coupon_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Campaign.ProtoReflect.Descriptor instead.
+func (*Campaign) Descriptor() ([]byte, []int) {
+	return file_coupon_v1_coupon_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Campaign) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Campaign) GetAvailableCoupons() int32 {
+	if x != nil {
+		return x.AvailableCoupons
+	}
+	return 0
+}
+
+func (x *Campaign) GetStartDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartDate
+	}
+	return nil
+}
+
+func (x *Campaign) GetIssuedCouponCodes() []string {
+	if x != nil {
+		return x.IssuedCouponCodes
+	}
+	return nil
+}
+
+func (x *Campaign) GetCodeFormat() *CodeFormat {
+	if x != nil {
+		return x.CodeFormat
+	}
+	return nil
+}
+
+func (x *Campaign) GetDiscountValue() int64 {
+	if x != nil {
+		return x.DiscountValue
+	}
+	return 0
+}
+
+func (x *Campaign) GetBudget() int64 {
+	if x != nil {
+		return x.Budget
+	}
+	return 0
+}
+
+func (x *Campaign) GetIssuedValue() int64 {
+	if x != nil {
+		return x.IssuedValue
+	}
+	return 0
+}
+
+func (x *Campaign) GetPerUserLimit() int32 {
+	if x != nil {
+		return x.PerUserLimit
+	}
+	return 0
+}
+
+func (x *Campaign) GetDeletedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DeletedAt
+	}
+	return nil
+}
+
+func (x *Campaign) GetMaxIssueRps() float64 {
+	if x != nil {
+		return x.MaxIssueRps
+	}
+	return 0
+}
+
+func (x *Campaign) GetIssuanceOrder() IssuanceOrder {
+	if x != nil {
+		return x.IssuanceOrder
+	}
+	return IssuanceOrder_ISSUANCE_ORDER_UNSPECIFIED
+}
+
+func (x *Campaign) GetShuffleSeed() int64 {
+	if x != nil {
+		return x.ShuffleSeed
+	}
+	return 0
+}
+
+func (x *Campaign) GetSlug() string {
+	if x != nil {
+		return x.Slug
+	}
+	return ""
+}
+
+func (x *Campaign) GetIssuanceWindow() *IssuanceWindow {
+	if x != nil {
+		return x.IssuanceWindow
+	}
+	return nil
+}
+
+// IssuanceWindow restricts issuance to a daily time window, e.g. 12:00-13:00
+// on weekdays
+type IssuanceWindow struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	StartTime string                 `protobuf:"bytes,1,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"` // Opening time of day, "HH:MM"
+	// Closing time of day (exclusive), "HH:MM"; earlier than start_time when
+	// the window spans midnight
+	EndTime  string `protobuf:"bytes,2,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`
+	TimeZone string `protobuf:"bytes,3,opt,name=time_zone,json=timeZone,proto3" json:"time_zone,omitempty"` // IANA time zone of the times, e.g. "Asia/Seoul"; defaults to UTC
+	// ISO weekdays the window opens on, 1 (Monday) to 7 (Sunday); empty means
+	// every day
+	Days          []int32 `protobuf:"varint,4,rep,packed,name=days,proto3" json:"days,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *IssuanceWindow) Reset() {
+	*x = IssuanceWindow{}
+	mi := &file_coupon_v1_coupon_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IssuanceWindow) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IssuanceWindow) ProtoMessage() {}
+
+func (x *IssuanceWindow) ProtoReflect() protoreflect.Message {
+	mi := &file_coupon_v1_coupon_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IssuanceWindow.ProtoReflect.Descriptor instead.
+func (*IssuanceWindow) Descriptor() ([]byte, []int) {
+	return file_coupon_v1_coupon_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *IssuanceWindow) GetStartTime() string {
+	if x != nil {
+		return x.StartTime
+	}
+	return ""
+}
+
+func (x *IssuanceWindow) GetEndTime() string {
+	if x != nil {
+		return x.EndTime
+	}
+	return ""
+}
+
+func (x *IssuanceWindow) GetTimeZone() string {
+	if x != nil {
+		return x.TimeZone
+	}
+	return ""
+}
+
+func (x *IssuanceWindow) GetDays() []int32 {
+	if x != nil {
+		return x.Days
+	}
+	return nil
+}
+
+// CodeFormat describes how coupon codes are generated for a campaign
+type CodeFormat struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Alphabet string                 `protobuf:"bytes,1,opt,name=alphabet,proto3" json:"alphabet,omitempty"` // Characters codes are drawn from (default: digits + 28 Hangul syllables)
+	Length   int32                  `protobuf:"varint,2,opt,name=length,proto3" json:"length,omitempty"`    // Total code length including the prefix (default 10, max 32)
+	Prefix   string                 `protobuf:"bytes,3,opt,name=prefix,proto3" json:"prefix,omitempty"`     // Fixed prefix prepended to every code
+	// End every code in an 8-character tag encoding the campaign ID, so codes
+	// can be routed to their campaign without a lookup. The tag counts toward
+	// length and is always Crockford base32, whatever the alphabet.
+	RoutingTag    bool `protobuf:"varint,4,opt,name=routing_tag,json=routingTag,proto3" json:"routing_tag,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CodeFormat) Reset() {
+	*x = CodeFormat{}
+	mi := &file_coupon_v1_coupon_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CodeFormat) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CodeFormat) ProtoMessage() {}
+
+func (x *CodeFormat) ProtoReflect() protoreflect.Message {
+	mi := &file_coupon_v1_coupon_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CodeFormat.ProtoReflect.Descriptor instead.
+func (*CodeFormat) Descriptor() ([]byte, []int) {
+	return file_coupon_v1_coupon_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *CodeFormat) GetAlphabet() string {
+	if x != nil {
+		return x.Alphabet
+	}
+	return ""
+}
+
+func (x *CodeFormat) GetLength() int32 {
+	if x != nil {
+		return x.Length
+	}
+	return 0
+}
+
+func (x *CodeFormat) GetPrefix() string {
+	if x != nil {
+		return x.Prefix
+	}
+	return ""
+}
+
+func (x *CodeFormat) GetRoutingTag() bool {
+	if x != nil {
+		return x.RoutingTag
+	}
+	return false
+}
+
+// Coupon represents an issued coupon
+type Coupon struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Code          string                 `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	CampaignId    int64                  `protobuf:"varint,2,opt,name=campaign_id,json=campaignId,proto3" json:"campaign_id,omitempty"`
+	ExpiresAt     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"` // Unset when the coupon never expires, and on idempotent replays
+	Status        CouponStatus           `protobuf:"varint,4,opt,name=status,proto3,enum=coupon.v1.CouponStatus" json:"status,omitempty"`
+	Pool          string                 `protobuf:"bytes,5,opt,name=pool,proto3" json:"pool,omitempty"` // Pool the coupon was issued from; empty for campaigns without pools and on idempotent replays
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Coupon) Reset() {
+	*x = Coupon{}
+	mi := &file_coupon_v1_coupon_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Coupon) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Coupon) ProtoMessage() {}
+
+func (x *Coupon) ProtoReflect() protoreflect.Message {
+	mi := &file_coupon_v1_coupon_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Coupon.ProtoReflect.Descriptor instead.
+func (*Coupon) Descriptor() ([]byte, []int) {
+	return file_coupon_v1_coupon_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Coupon) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *Coupon) GetCampaignId() int64 {
+	if x != nil {
+		return x.CampaignId
+	}
+	return 0
+}
+
+func (x *Coupon) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+func (x *Coupon) GetStatus() CouponStatus {
+	if x != nil {
+		return x.Status
+	}
+	return CouponStatus_COUPON_STATUS_UNSPECIFIED
+}
+
+func (x *Coupon) GetPool() string {
+	if x != nil {
+		return x.Pool
+	}
+	return ""
+}
+
+// CreateCampaignRequest
+type CreateCampaignRequest struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	AvailableCoupons int32                  `protobuf:"varint,1,opt,name=available_coupons,json=availableCoupons,proto3" json:"available_coupons,omitempty"`                                                                    // Number of available coupons
+	StartDate        *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=start_date,json=startDate,proto3" json:"start_date,omitempty"`                                                                                          // Specific start date and time
+	DryRun           bool                   `protobuf:"varint,3,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`                                                                                                  // Validate and preview sample codes without persisting anything
+	SampleSize       int32                  `protobuf:"varint,4,opt,name=sample_size,json=sampleSize,proto3" json:"sample_size,omitempty"`                                                                                      // Number of sample codes returned in dry-run mode (default 10, max 100)
+	CodeFormat       *CodeFormat            `protobuf:"bytes,5,opt,name=code_format,json=codeFormat,proto3" json:"code_format,omitempty"`                                                                                       // Optional code format; unset fields use the default format
+	DiscountValue    int64                  `protobuf:"varint,6,opt,name=discount_value,json=discountValue,proto3" json:"discount_value,omitempty"`                                                                             // Value of one coupon in minor currency units
+	Budget           int64                  `protobuf:"varint,7,opt,name=budget,proto3" json:"budget,omitempty"`                                                                                                                // Optional cap on the total value of issued coupons (requires discount_value)
+	CouponMetadata   map[string]string      `protobuf:"bytes,8,rep,name=coupon_metadata,json=couponMetadata,proto3" json:"coupon_metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // Metadata attached to every generated coupon
+	PerUserLimit     int32                  `protobuf:"varint,9,opt,name=per_user_limit,json=perUserLimit,proto3" json:"per_user_limit,omitempty"`                                                                              // Optional cap on coupons per user; requires user_id on issuance
+	// Optional; a retry with the same request_id returns the campaign created by
+	// the first request instead of creating another. Other fields of the retry
+	// are not compared. Ignored in dry-run mode.
+	RequestId   string  `protobuf:"bytes,10,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	MaxIssueRps float64 `protobuf:"fixed64,11,opt,name=max_issue_rps,json=maxIssueRps,proto3" json:"max_issue_rps,omitempty"` // Optional issuance rate limit; 0 uses the server default
+	// Optional tiers with independent stock. When set, the campaign's coupons
+	// are the sum of the pool counts and available_coupons must be 0 or match it.
+	Pools         []*CouponPool `protobuf:"bytes,12,rep,name=pools,proto3" json:"pools,omitempty"`
+	IssuanceOrder IssuanceOrder `protobuf:"varint,13,opt,name=issuance_order,json=issuanceOrder,proto3,enum=coupon.v1.IssuanceOrder" json:"issuance_order,omitempty"` // Optional; defaults to CREATED
+	ShuffleSeed   int64         `protobuf:"varint,14,opt,name=shuffle_seed,json=shuffleSeed,proto3" json:"shuffle_seed,omitempty"`                                    // Optional seed for SHUFFLED; 0 lets the server pick one
+	// Optional unique name usable instead of the campaign ID: 1-64 lowercase
+	// letters, digits and single hyphens, e.g. "spring-sale-2025"
+	Slug           string          `protobuf:"bytes,15,opt,name=slug,proto3" json:"slug,omitempty"`
+	IssuanceWindow *IssuanceWindow `protobuf:"bytes,16,opt,name=issuance_window,json=issuanceWindow,proto3" json:"issuance_window,omitempty"` // Optional daily issuance window
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *CreateCampaignRequest) Reset() {
+	*x = CreateCampaignRequest{}
+	mi := &file_coupon_v1_coupon_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateCampaignRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateCampaignRequest) ProtoMessage() {}
+
+func (x *CreateCampaignRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_coupon_v1_coupon_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateCampaignRequest.ProtoReflect.Descriptor instead.
+func (*CreateCampaignRequest) Descriptor() ([]byte, []int) {
+	return file_coupon_v1_coupon_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *CreateCampaignRequest) GetAvailableCoupons() int32 {
+	if x != nil {
+		return x.AvailableCoupons
+	}
+	return 0
+}
+
+func (x *CreateCampaignRequest) GetStartDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartDate
+	}
+	return nil
+}
+
+func (x *CreateCampaignRequest) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+func (x *CreateCampaignRequest) GetSampleSize() int32 {
+	if x != nil {
+		return x.SampleSize
+	}
+	return 0
+}
+
+func (x *CreateCampaignRequest) GetCodeFormat() *CodeFormat {
+	if x != nil {
+		return x.CodeFormat
+	}
+	return nil
+}
+
+func (x *CreateCampaignRequest) GetDiscountValue() int64 {
+	if x != nil {
+		return x.DiscountValue
+	}
+	return 0
+}
+
+func (x *CreateCampaignRequest) GetBudget() int64 {
+	if x != nil {
+		return x.Budget
+	}
+	return 0
+}
+
+func (x *CreateCampaignRequest) GetCouponMetadata() map[string]string {
+	if x != nil {
+		return x.CouponMetadata
+	}
+	return nil
+}
+
+func (x *CreateCampaignRequest) GetPerUserLimit() int32 {
+	if x != nil {
+		return x.PerUserLimit
+	}
+	return 0
+}
+
+func (x *CreateCampaignRequest) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+func (x *CreateCampaignRequest) GetMaxIssueRps() float64 {
+	if x != nil {
+		return x.MaxIssueRps
+	}
+	return 0
+}
+
+func (x *CreateCampaignRequest) GetPools() []*CouponPool {
+	if x != nil {
+		return x.Pools
+	}
+	return nil
+}
+
+func (x *CreateCampaignRequest) GetIssuanceOrder() IssuanceOrder {
+	if x != nil {
+		return x.IssuanceOrder
+	}
+	return IssuanceOrder_ISSUANCE_ORDER_UNSPECIFIED
+}
+
+func (x *CreateCampaignRequest) GetShuffleSeed() int64 {
+	if x != nil {
+		return x.ShuffleSeed
+	}
+	return 0
+}
+
+func (x *CreateCampaignRequest) GetSlug() string {
+	if x != nil {
+		return x.Slug
+	}
+	return ""
+}
+
+func (x *CreateCampaignRequest) GetIssuanceWindow() *IssuanceWindow {
+	if x != nil {
+		return x.IssuanceWindow
+	}
+	return nil
+}
+
+// CouponPool is a named set of coupons within a campaign, e.g. a tier
+type CouponPool struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Name     string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`          // 1-32 bytes, unique within the campaign
+	Count    int32                  `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`       // Number of coupons generated for the pool
+	Priority int32                  `protobuf:"varint,3,opt,name=priority,proto3" json:"priority,omitempty"` // Higher pools are issued first by campaigns ordered by PRIORITY
+	// Optional expiry of the pool's coupons, which are no longer issued after
+	// it; unset gives issued coupons the server's default expiry
+	ExpiresAt     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CouponPool) Reset() {
+	*x = CouponPool{}
+	mi := &file_coupon_v1_coupon_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CouponPool) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CouponPool) ProtoMessage() {}
+
+func (x *CouponPool) ProtoReflect() protoreflect.Message {
+	mi := &file_coupon_v1_coupon_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CouponPool.ProtoReflect.Descriptor instead.
+func (*CouponPool) Descriptor() ([]byte, []int) {
+	return file_coupon_v1_coupon_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *CouponPool) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CouponPool) GetCount() int32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *CouponPool) GetPriority() int32 {
+	if x != nil {
+		return x.Priority
+	}
+	return 0
+}
+
+func (x *CouponPool) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+// CreateCampaignResponse
+type CreateCampaignResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Campaign      *Campaign              `protobuf:"bytes,1,opt,name=campaign,proto3" json:"campaign,omitempty"`                          // In dry-run mode the campaign is not persisted and has no id
+	SampleCodes   []string               `protobuf:"bytes,2,rep,name=sample_codes,json=sampleCodes,proto3" json:"sample_codes,omitempty"` // Sample generated codes (dry-run only)
+	Replayed      bool                   `protobuf:"varint,3,opt,name=replayed,proto3" json:"replayed,omitempty"`                         // True when the campaign was created by an earlier request with the same request_id
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateCampaignResponse) Reset() {
+	*x = CreateCampaignResponse{}
+	mi := &file_coupon_v1_coupon_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateCampaignResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateCampaignResponse) ProtoMessage() {}
+
+func (x *CreateCampaignResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_coupon_v1_coupon_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateCampaignResponse.ProtoReflect.Descriptor instead.
+func (*CreateCampaignResponse) Descriptor() ([]byte, []int) {
+	return file_coupon_v1_coupon_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *CreateCampaignResponse) GetCampaign() *Campaign {
+	if x != nil {
+		return x.Campaign
+	}
+	return nil
+}
+
+func (x *CreateCampaignResponse) GetSampleCodes() []string {
+	if x != nil {
+		return x.SampleCodes
+	}
+	return nil
+}
+
+func (x *CreateCampaignResponse) GetReplayed() bool {
+	if x != nil {
+		return x.Replayed
+	}
+	return false
+}
+
+// CreateCampaignProgress is one message of a CreateCampaignStream. Codes are
+// all generated before any is inserted, so generated reaches total first; the
+// last message carries the campaign.
+type CreateCampaignProgress struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Generated     int32                  `protobuf:"varint,1,opt,name=generated,proto3" json:"generated,omitempty"` // Coupon codes generated so far
+	Inserted      int32                  `protobuf:"varint,2,opt,name=inserted,proto3" json:"inserted,omitempty"`   // Coupons inserted so far (not visible until the campaign is created)
+	Total         int32                  `protobuf:"varint,3,opt,name=total,proto3" json:"total,omitempty"`         // Coupons to create
+	Campaign      *Campaign              `protobuf:"bytes,4,opt,name=campaign,proto3" json:"campaign,omitempty"`    // Set on the last message only
+	Replayed      bool                   `protobuf:"varint,5,opt,name=replayed,proto3" json:"replayed,omitempty"`   // True when the campaign was created by an earlier request with the same request_id
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateCampaignProgress) Reset() {
+	*x = CreateCampaignProgress{}
+	mi := &file_coupon_v1_coupon_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateCampaignProgress) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateCampaignProgress) ProtoMessage() {}
+
+func (x *CreateCampaignProgress) ProtoReflect() protoreflect.Message {
+	mi := &file_coupon_v1_coupon_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateCampaignProgress.ProtoReflect.Descriptor instead.
+func (*CreateCampaignProgress) Descriptor() ([]byte, []int) {
+	return file_coupon_v1_coupon_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *CreateCampaignProgress) GetGenerated() int32 {
+	if x != nil {
+		return x.Generated
+	}
+	return 0
+}
+
+func (x *CreateCampaignProgress) GetInserted() int32 {
+	if x != nil {
+		return x.Inserted
+	}
+	return 0
+}
+
+func (x *CreateCampaignProgress) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *CreateCampaignProgress) GetCampaign() *Campaign {
+	if x != nil {
+		return x.Campaign
+	}
+	return nil
+}
+
+func (x *CreateCampaignProgress) GetReplayed() bool {
+	if x != nil {
+		return x.Replayed
+	}
+	return false
+}
+
+// BatchCreateCampaignsRequest
+type BatchCreateCampaignsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Up to 100 campaigns (APP_MAX_BATCH_CAMPAIGNS) with at most 100000
+	// coupons in total. dry_run and
+	// request_id are not supported within a batch.
+	Campaigns     []*CreateCampaignRequest `protobuf:"bytes,1,rep,name=campaigns,proto3" json:"campaigns,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchCreateCampaignsRequest) Reset() {
+	*x = BatchCreateCampaignsRequest{}
+	mi := &file_coupon_v1_coupon_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchCreateCampaignsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchCreateCampaignsRequest) ProtoMessage() {}
+
+func (x *BatchCreateCampaignsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_coupon_v1_coupon_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchCreateCampaignsRequest.ProtoReflect.Descriptor instead.
+func (*BatchCreateCampaignsRequest) Descriptor() ([]byte, []int) {
+	return file_coupon_v1_coupon_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *BatchCreateCampaignsRequest) GetCampaigns() []*CreateCampaignRequest {
+	if x != nil {
+		return x.Campaigns
+	}
+	return nil
+}
+
+// BatchCreateCampaignsResponse
+type BatchCreateCampaignsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CampaignIds   []int64                `protobuf:"varint,1,rep,packed,name=campaign_ids,json=campaignIds,proto3" json:"campaign_ids,omitempty"` // IDs of the created campaigns, in request order
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchCreateCampaignsResponse) Reset() {
+	*x = BatchCreateCampaignsResponse{}
+	mi := &file_coupon_v1_coupon_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchCreateCampaignsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchCreateCampaignsResponse) ProtoMessage() {}
+
+func (x *BatchCreateCampaignsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_coupon_v1_coupon_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchCreateCampaignsResponse.ProtoReflect.Descriptor instead.
+func (*BatchCreateCampaignsResponse) Descriptor() ([]byte, []int) {
+	return file_coupon_v1_coupon_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *BatchCreateCampaignsResponse) GetCampaignIds() []int64 {
+	if x != nil {
+		return x.CampaignIds
+	}
+	return nil
+}
+
+// GetCampaignRequest
+type GetCampaignRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Campaign:
+	//
+	//	*GetCampaignRequest_CampaignId
+	//	*GetCampaignRequest_Slug
+	Campaign       isGetCampaignRequest_Campaign `protobuf_oneof:"campaign"`
+	IncludeDeleted bool                          `protobuf:"varint,2,opt,name=include_deleted,json=includeDeleted,proto3" json:"include_deleted,omitempty"` // Admin only: also return soft-deleted campaigns
+	// Return up to this many randomly chosen issued codes instead of all of
+	// them (max 100), for spot checks; 0 returns every issued code
+	SampleSize    int32 `protobuf:"varint,4,opt,name=sample_size,json=sampleSize,proto3" json:"sample_size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCampaignRequest) Reset() {
+	*x = GetCampaignRequest{}
+	mi := &file_coupon_v1_coupon_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCampaignRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCampaignRequest) ProtoMessage() {}
+
+func (x *GetCampaignRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_coupon_v1_coupon_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCampaignRequest.ProtoReflect.Descriptor instead.
+func (*GetCampaignRequest) Descriptor() ([]byte, []int) {
+	return file_coupon_v1_coupon_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *GetCampaignRequest) GetCampaign() isGetCampaignRequest_Campaign {
+	if x != nil {
+		return x.Campaign
+	}
+	return nil
+}
+
+func (x *GetCampaignRequest) GetCampaignId() int64 {
+	if x != nil {
+		if x, ok := x.Campaign.(*GetCampaignRequest_CampaignId); ok {
+			return x.CampaignId
+		}
+	}
+	return 0
+}
+
+func (x *GetCampaignRequest) GetSlug() string {
+	if x != nil {
+		if x, ok := x.Campaign.(*GetCampaignRequest_Slug); ok {
+			return x.Slug
+		}
+	}
+	return ""
+}
+
+func (x *GetCampaignRequest) GetIncludeDeleted() bool {
+	if x != nil {
+		return x.IncludeDeleted
+	}
+	return false
+}
+
+func (x *GetCampaignRequest) GetSampleSize() int32 {
+	if x != nil {
+		return x.SampleSize
+	}
+	return 0
+}
+
+type isGetCampaignRequest_Campaign interface {
+	isGetCampaignRequest_Campaign()
+}
+
+type GetCampaignRequest_CampaignId struct {
+	CampaignId int64 `protobuf:"varint,1,opt,name=campaign_id,json=campaignId,proto3,oneof"`
+}
+
+type GetCampaignRequest_Slug struct {
+	Slug string `protobuf:"bytes,3,opt,name=slug,proto3,oneof"` // Look the campaign up by its slug instead
+}
+
+func (*GetCampaignRequest_CampaignId) isGetCampaignRequest_Campaign() {}
+
+func (*GetCampaignRequest_Slug) isGetCampaignRequest_Campaign() {}
+
+// GetCampaignResponse
+type GetCampaignResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Campaign      *Campaign              `protobuf:"bytes,1,opt,name=campaign,proto3" json:"campaign,omitempty"`
+	State         CampaignState          `protobuf:"varint,2,opt,name=state,proto3,enum=coupon.v1.CampaignState" json:"state,omitempty"` // Whether coupons can be issued from the campaign now
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCampaignResponse) Reset() {
+	*x = GetCampaignResponse{}
+	mi := &file_coupon_v1_coupon_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCampaignResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCampaignResponse) ProtoMessage() {}
+
+func (x *GetCampaignResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_coupon_v1_coupon_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCampaignResponse.ProtoReflect.Descriptor instead.
+func (*GetCampaignResponse) Descriptor() ([]byte, []int) {
+	return file_coupon_v1_coupon_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *GetCampaignResponse) GetCampaign() *Campaign {
+	if x != nil {
+		return x.Campaign
+	}
+	return nil
+}
+
+func (x *GetCampaignResponse) GetState() CampaignState {
+	if x != nil {
+		return x.State
+	}
+	return CampaignState_CAMPAIGN_STATE_UNSPECIFIED
+}
+
+// IssueCouponRequest
+type IssueCouponRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Campaign:
+	//
+	//	*IssueCouponRequest_CampaignId
+	//	*IssueCouponRequest_Slug
+	Campaign isIssueCouponRequest_Campaign `protobuf_oneof:"campaign"`
+	UserId   string                        `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"` // Optional; recorded on the coupon and used for per-user limits
+	// Optional; retries with the same key return the same coupon until the key
+	// expires (APP_IDEMPOTENCY_KEY_TTL, default 24h), then fail with FAILED_PRECONDITION
+	IdempotencyKey string `protobuf:"bytes,3,opt,name=idempotency_key,json=idempotencyKey,proto3" json:"idempotency_key,omitempty"`
+	Pool           string `protobuf:"bytes,4,opt,name=pool,proto3" json:"pool,omitempty"` // Optional; reserve only from this pool instead of any pool
+	// Optional; report a sold-out or not yet open campaign as a successful
+	// response with outcome set instead of a RESOURCE_EXHAUSTED or
+	// FAILED_PRECONDITION error. Other failures are still errors.
+	ReportOutcome bool `protobuf:"varint,6,opt,name=report_outcome,json=reportOutcome,proto3" json:"report_outcome,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *IssueCouponRequest) Reset() {
+	*x = IssueCouponRequest{}
+	mi := &file_coupon_v1_coupon_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IssueCouponRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IssueCouponRequest) ProtoMessage() {}
+
+func (x *IssueCouponRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_coupon_v1_coupon_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IssueCouponRequest.ProtoReflect.Descriptor instead.
+func (*IssueCouponRequest) Descriptor() ([]byte, []int) {
+	return file_coupon_v1_coupon_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *IssueCouponRequest) GetCampaign() isIssueCouponRequest_Campaign {
+	if x != nil {
+		return x.Campaign
+	}
+	return nil
+}
+
+func (x *IssueCouponRequest) GetCampaignId() int64 {
+	if x != nil {
+		if x, ok := x.Campaign.(*IssueCouponRequest_CampaignId); ok {
+			return x.CampaignId
+		}
+	}
+	return 0
+}
+
+func (x *IssueCouponRequest) GetSlug() string {
+	if x != nil {
+		if x, ok := x.Campaign.(*IssueCouponRequest_Slug); ok {
+			return x.Slug
+		}
+	}
+	return ""
+}
+
+func (x *IssueCouponRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *IssueCouponRequest) GetIdempotencyKey() string {
+	if x != nil {
+		return x.IdempotencyKey
+	}
+	return ""
+}
+
+func (x *IssueCouponRequest) GetPool() string {
+	if x != nil {
+		return x.Pool
+	}
+	return ""
+}
+
+func (x *IssueCouponRequest) GetReportOutcome() bool {
+	if x != nil {
+		return x.ReportOutcome
+	}
+	return false
+}
+
+type isIssueCouponRequest_Campaign interface {
+	isIssueCouponRequest_Campaign()
+}
+
+type IssueCouponRequest_CampaignId struct {
+	CampaignId int64 `protobuf:"varint,1,opt,name=campaign_id,json=campaignId,proto3,oneof"`
+}
+
+type IssueCouponRequest_Slug struct {
+	Slug string `protobuf:"bytes,5,opt,name=slug,proto3,oneof"` // Issue from the campaign with this slug instead
+}
+
+func (*IssueCouponRequest_CampaignId) isIssueCouponRequest_Campaign() {}
+
+func (*IssueCouponRequest_Slug) isIssueCouponRequest_Campaign() {}
+
+// IssueCouponResponse
+type IssueCouponResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Coupon         *Coupon                `protobuf:"bytes,1,opt,name=coupon,proto3" json:"coupon,omitempty"`                                       // Unset unless outcome is ISSUED
+	Replayed       bool                   `protobuf:"varint,2,opt,name=replayed,proto3" json:"replayed,omitempty"`                                  // True when the coupon was issued by an earlier request with the same idempotency key
+	Outcome        IssueOutcome           `protobuf:"varint,3,opt,name=outcome,proto3,enum=coupon.v1.IssueOutcome" json:"outcome,omitempty"`        // ISSUED on every success; other values only with report_outcome
+	SoldOut        *SoldOutInfo           `protobuf:"bytes,4,opt,name=sold_out,json=soldOut,proto3" json:"sold_out,omitempty"`                      // Set with the SOLD_OUT outcome, as in the error detail
+	IssuanceWindow *IssuanceWindowInfo    `protobuf:"bytes,5,opt,name=issuance_window,json=issuanceWindow,proto3" json:"issuance_window,omitempty"` // Set with NOT_STARTED outside the daily window
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *IssueCouponResponse) Reset() {
+	*x = IssueCouponResponse{}
+	mi := &file_coupon_v1_coupon_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IssueCouponResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IssueCouponResponse) ProtoMessage() {}
+
+func (x *IssueCouponResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_coupon_v1_coupon_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IssueCouponResponse.ProtoReflect.Descriptor instead.
+func (*IssueCouponResponse) Descriptor() ([]byte, []int) {
+	return file_coupon_v1_coupon_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *IssueCouponResponse) GetCoupon() *Coupon {
+	if x != nil {
+		return x.Coupon
+	}
+	return nil
+}
+
+func (x *IssueCouponResponse) GetReplayed() bool {
+	if x != nil {
+		return x.Replayed
+	}
+	return false
+}
+
+func (x *IssueCouponResponse) GetOutcome() IssueOutcome {
+	if x != nil {
+		return x.Outcome
+	}
+	return IssueOutcome_ISSUE_OUTCOME_UNSPECIFIED
+}
+
+func (x *IssueCouponResponse) GetSoldOut() *SoldOutInfo {
+	if x != nil {
+		return x.SoldOut
+	}
+	return nil
+}
+
+func (x *IssueCouponResponse) GetIssuanceWindow() *IssuanceWindowInfo {
+	if x != nil {
+		return x.IssuanceWindow
+	}
+	return nil
+}
+
+// IssueBatchRequest
+type IssueBatchRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CampaignId    int64                  `protobuf:"varint,1,opt,name=campaign_id,json=campaignId,proto3" json:"campaign_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"` // Required; all coupons are issued to this user
+	Quantity      int32                  `protobuf:"varint,3,opt,name=quantity,proto3" json:"quantity,omitempty"`          // Number of coupons requested (1 to APP_MAX_ISSUE_BATCH_QUANTITY, default 100)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *IssueBatchRequest) Reset() {
+	*x = IssueBatchRequest{}
+	mi := &file_coupon_v1_coupon_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IssueBatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IssueBatchRequest) ProtoMessage() {}
+
+func (x *IssueBatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_coupon_v1_coupon_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IssueBatchRequest.ProtoReflect.Descriptor instead.
+func (*IssueBatchRequest) Descriptor() ([]byte, []int) {
+	return file_coupon_v1_coupon_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *IssueBatchRequest) GetCampaignId() int64 {
+	if x != nil {
+		return x.CampaignId
+	}
+	return 0
+}
+
+func (x *IssueBatchRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *IssueBatchRequest) GetQuantity() int32 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+// IssueBatchResponse
+type IssueBatchResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Coupons        []*Coupon              `protobuf:"bytes,1,rep,name=coupons,proto3" json:"coupons,omitempty"` // Granted coupons
+	RequestedCount int32                  `protobuf:"varint,2,opt,name=requested_count,json=requestedCount,proto3" json:"requested_count,omitempty"`
+	GrantedCount   int32                  `protobuf:"varint,3,opt,name=granted_count,json=grantedCount,proto3" json:"granted_count,omitempty"` // Less than requested when the per-user limit, stock or budget ran out
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *IssueBatchResponse) Reset() {
+	*x = IssueBatchResponse{}
+	mi := &file_coupon_v1_coupon_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IssueBatchResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IssueBatchResponse) ProtoMessage() {}
+
+func (x *IssueBatchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_coupon_v1_coupon_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IssueBatchResponse.ProtoReflect.Descriptor instead.
+func (*IssueBatchResponse) Descriptor() ([]byte, []int) {
+	return file_coupon_v1_coupon_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *IssueBatchResponse) GetCoupons() []*Coupon {
+	if x != nil {
+		return x.Coupons
+	}
+	return nil
+}
+
+func (x *IssueBatchResponse) GetRequestedCount() int32 {
+	if x != nil {
+		return x.RequestedCount
+	}
+	return 0
+}
+
+func (x *IssueBatchResponse) GetGrantedCount() int32 {
+	if x != nil {
+		return x.GrantedCount
+	}
+	return 0
+}
+
+// CampaignStats holds coupon counts for a campaign
+type CampaignStats struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	CampaignId          int64                  `protobuf:"varint,1,opt,name=campaign_id,json=campaignId,proto3" json:"campaign_id,omitempty"`
+	TotalCount          int32                  `protobuf:"varint,2,opt,name=total_count,json=totalCount,proto3" json:"total_count,omitempty"`                              // Number of coupons generated for the campaign
+	AvailableCount      int32                  `protobuf:"varint,3,opt,name=available_count,json=availableCount,proto3" json:"available_count,omitempty"`                  // Coupons not yet issued
+	IssuedCount         int32                  `protobuf:"varint,4,opt,name=issued_count,json=issuedCount,proto3" json:"issued_count,omitempty"`                           // Successfully issued coupons
+	RecentlyIssuedCount int32                  `protobuf:"varint,5,opt,name=recently_issued_count,json=recentlyIssuedCount,proto3" json:"recently_issued_count,omitempty"` // Coupons issued within recent_window
+	RecentWindow        *durationpb.Duration   `protobuf:"bytes,6,opt,name=recent_window,json=recentWindow,proto3" json:"recent_window,omitempty"`                         // Lookback window applied to recently_issued_count
+	Pools               []*PoolStats           `protobuf:"bytes,7,rep,name=pools,proto3" json:"pools,omitempty"`                                                           // Per-pool counts, ordered by name; empty for campaigns without pools
+	RevokedCount        int32                  `protobuf:"varint,8,opt,name=revoked_count,json=revokedCount,proto3" json:"revoked_count,omitempty"`                        // Issued coupons later revoked; not included in issued_count
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *CampaignStats) Reset() {
+	*x = CampaignStats{}
+	mi := &file_coupon_v1_coupon_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CampaignStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CampaignStats) ProtoMessage() {}
+
+func (x *CampaignStats) ProtoReflect() protoreflect.Message {
+	mi := &file_coupon_v1_coupon_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CampaignStats.ProtoReflect.Descriptor instead.
+func (*CampaignStats) Descriptor() ([]byte, []int) {
+	return file_coupon_v1_coupon_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *CampaignStats) GetCampaignId() int64 {
+	if x != nil {
+		return x.CampaignId
+	}
+	return 0
+}
+
+func (x *CampaignStats) GetTotalCount() int32 {
+	if x != nil {
+		return x.TotalCount
+	}
+	return 0
+}
+
+func (x *CampaignStats) GetAvailableCount() int32 {
+	if x != nil {
+		return x.AvailableCount
+	}
+	return 0
+}
+
+func (x *CampaignStats) GetIssuedCount() int32 {
+	if x != nil {
+		return x.IssuedCount
+	}
+	return 0
+}
+
+func (x *CampaignStats) GetRecentlyIssuedCount() int32 {
+	if x != nil {
+		return x.RecentlyIssuedCount
+	}
+	return 0
+}
+
+func (x *CampaignStats) GetRecentWindow() *durationpb.Duration {
+	if x != nil {
+		return x.RecentWindow
+	}
+	return nil
+}
+
+func (x *CampaignStats) GetPools() []*PoolStats {
+	if x != nil {
+		return x.Pools
+	}
+	return nil
+}
+
+func (x *CampaignStats) GetRevokedCount() int32 {
+	if x != nil {
+		return x.RevokedCount
+	}
+	return 0
+}
+
+// PoolStats holds the coupon counts of one pool of a campaign
+type PoolStats struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Pool           string                 `protobuf:"bytes,1,opt,name=pool,proto3" json:"pool,omitempty"`
+	TotalCount     int32                  `protobuf:"varint,2,opt,name=total_count,json=totalCount,proto3" json:"total_count,omitempty"`
+	AvailableCount int32                  `protobuf:"varint,3,opt,name=available_count,json=availableCount,proto3" json:"available_count,omitempty"`
+	IssuedCount    int32                  `protobuf:"varint,4,opt,name=issued_count,json=issuedCount,proto3" json:"issued_count,omitempty"`
+	RevokedCount   int32                  `protobuf:"varint,5,opt,name=revoked_count,json=revokedCount,proto3" json:"revoked_count,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *PoolStats) Reset() {
+	*x = PoolStats{}
+	mi := &file_coupon_v1_coupon_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PoolStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PoolStats) ProtoMessage() {}
+
+func (x *PoolStats) ProtoReflect() protoreflect.Message {
+	mi := &file_coupon_v1_coupon_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PoolStats.ProtoReflect.Descriptor instead.
+func (*PoolStats) Descriptor() ([]byte, []int) {
+	return file_coupon_v1_coupon_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *PoolStats) GetPool() string {
+	if x != nil {
+		return x.Pool
+	}
+	return ""
+}
+
+func (x *PoolStats) GetTotalCount() int32 {
+	if x != nil {
+		return x.TotalCount
+	}
+	return 0
+}
+
+func (x *PoolStats) GetAvailableCount() int32 {
+	if x != nil {
+		return x.AvailableCount
+	}
+	return 0
+}
+
+func (x *PoolStats) GetIssuedCount() int32 {
+	if x != nil {
+		return x.IssuedCount
+	}
+	return 0
+}
+
+func (x *PoolStats) GetRevokedCount() int32 {
+	if x != nil {
+		return x.RevokedCount
+	}
+	return 0
+}
+
+// GetCampaignStatsRequest
+type GetCampaignStatsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CampaignId    int64                  `protobuf:"varint,1,opt,name=campaign_id,json=campaignId,proto3" json:"campaign_id,omitempty"`
+	RecentWindow  *durationpb.Duration   `protobuf:"bytes,2,opt,name=recent_window,json=recentWindow,proto3" json:"recent_window,omitempty"` // Lookback for recently_issued_count (default 1h, max 24h)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCampaignStatsRequest) Reset() {
+	*x = GetCampaignStatsRequest{}
+	mi := &file_coupon_v1_coupon_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCampaignStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCampaignStatsRequest) ProtoMessage() {}
+
+func (x *GetCampaignStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_coupon_v1_coupon_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCampaignStatsRequest.ProtoReflect.Descriptor instead.
+func (*GetCampaignStatsRequest) Descriptor() ([]byte, []int) {
+	return file_coupon_v1_coupon_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *GetCampaignStatsRequest) GetCampaignId() int64 {
+	if x != nil {
+		return x.CampaignId
+	}
+	return 0
+}
+
+func (x *GetCampaignStatsRequest) GetRecentWindow() *durationpb.Duration {
+	if x != nil {
+		return x.RecentWindow
+	}
+	return nil
+}
+
+// GetCampaignStatsResponse
+type GetCampaignStatsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Stats         *CampaignStats         `protobuf:"bytes,1,opt,name=stats,proto3" json:"stats,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCampaignStatsResponse) Reset() {
+	*x = GetCampaignStatsResponse{}
+	mi := &file_coupon_v1_coupon_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCampaignStatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCampaignStatsResponse) ProtoMessage() {}
+
+func (x *GetCampaignStatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_coupon_v1_coupon_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCampaignStatsResponse.ProtoReflect.Descriptor instead.
+func (*GetCampaignStatsResponse) Descriptor() ([]byte, []int) {
+	return file_coupon_v1_coupon_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *GetCampaignStatsResponse) GetStats() *CampaignStats {
+	if x != nil {
+		return x.Stats
+	}
+	return nil
+}
+
+// GetRemainingRequest
+type GetRemainingRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CampaignId    int64                  `protobuf:"varint,1,opt,name=campaign_id,json=campaignId,proto3" json:"campaign_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRemainingRequest) Reset() {
+	*x = GetRemainingRequest{}
+	mi := &file_coupon_v1_coupon_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRemainingRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRemainingRequest) ProtoMessage() {}
+
+func (x *GetRemainingRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_coupon_v1_coupon_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRemainingRequest.ProtoReflect.Descriptor instead.
+func (*GetRemainingRequest) Descriptor() ([]byte, []int) {
+	return file_coupon_v1_coupon_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *GetRemainingRequest) GetCampaignId() int64 {
+	if x != nil {
+		return x.CampaignId
+	}
+	return 0
+}
+
+// GetRemainingResponse
+type GetRemainingResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	AvailableCount int32                  `protobuf:"varint,1,opt,name=available_count,json=availableCount,proto3" json:"available_count,omitempty"` // May lag behind issuance by up to the cache TTL
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *GetRemainingResponse) Reset() {
+	*x = GetRemainingResponse{}
+	mi := &file_coupon_v1_coupon_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRemainingResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRemainingResponse) ProtoMessage() {}
+
+func (x *GetRemainingResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_coupon_v1_coupon_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRemainingResponse.ProtoReflect.Descriptor instead.
+func (*GetRemainingResponse) Descriptor() ([]byte, []int) {
+	return file_coupon_v1_coupon_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *GetRemainingResponse) GetAvailableCount() int32 {
+	if x != nil {
+		return x.AvailableCount
+	}
+	return 0
+}
+
+// RegenerateCouponsRequest
+type RegenerateCouponsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CampaignId    int64                  `protobuf:"varint,1,opt,name=campaign_id,json=campaignId,proto3" json:"campaign_id,omitempty"`
+	CodeFormat    *CodeFormat            `protobuf:"bytes,2,opt,name=code_format,json=codeFormat,proto3" json:"code_format,omitempty"` // New format; unset alphabet/length keep the campaign's current values
+	DryRun        bool                   `protobuf:"varint,3,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`            // Preview the count and sample codes without changing anything
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RegenerateCouponsRequest) Reset() {
+	*x = RegenerateCouponsRequest{}
+	mi := &file_coupon_v1_coupon_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegenerateCouponsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegenerateCouponsRequest) ProtoMessage() {}
+
+func (x *RegenerateCouponsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_coupon_v1_coupon_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegenerateCouponsRequest.ProtoReflect.Descriptor instead.
+func (*RegenerateCouponsRequest) Descriptor() ([]byte, []int) {
+	return file_coupon_v1_coupon_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *RegenerateCouponsRequest) GetCampaignId() int64 {
+	if x != nil {
+		return x.CampaignId
+	}
+	return 0
+}
+
+func (x *RegenerateCouponsRequest) GetCodeFormat() *CodeFormat {
+	if x != nil {
+		return x.CodeFormat
+	}
+	return nil
+}
+
+func (x *RegenerateCouponsRequest) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+// RegenerateCouponsResponse
+type RegenerateCouponsResponse struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	RegeneratedCount int32                  `protobuf:"varint,1,opt,name=regenerated_count,json=regeneratedCount,proto3" json:"regenerated_count,omitempty"` // Number of available coupons replaced
+	Campaign         *Campaign              `protobuf:"bytes,2,opt,name=campaign,proto3" json:"campaign,omitempty"`                                          // Campaign with its updated code format
+	SampleCodes      []string               `protobuf:"bytes,3,rep,name=sample_codes,json=sampleCodes,proto3" json:"sample_codes,omitempty"`                 // Sample codes in the new format (dry-run only)
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *RegenerateCouponsResponse) Reset() {
+	*x = RegenerateCouponsResponse{}
+	mi := &file_coupon_v1_coupon_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegenerateCouponsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegenerateCouponsResponse) ProtoMessage() {}
+
+func (x *RegenerateCouponsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_coupon_v1_coupon_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegenerateCouponsResponse.ProtoReflect.Descriptor instead.
+func (*RegenerateCouponsResponse) Descriptor() ([]byte, []int) {
+	return file_coupon_v1_coupon_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *RegenerateCouponsResponse) GetRegeneratedCount() int32 {
+	if x != nil {
+		return x.RegeneratedCount
+	}
+	return 0
+}
+
+func (x *RegenerateCouponsResponse) GetCampaign() *Campaign {
+	if x != nil {
+		return x.Campaign
+	}
+	return nil
+}
+
+func (x *RegenerateCouponsResponse) GetSampleCodes() []string {
+	if x != nil {
+		return x.SampleCodes
+	}
+	return nil
+}
+
+// PageRequest selects a page of a list RPC
+type PageRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PageSize      int32                  `protobuf:"varint,1,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`   // Maximum results per page (default 20); values above the server max (default 100) are clamped
+	PageToken     string                 `protobuf:"bytes,2,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"` // Token from a previous response to fetch the next page
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PageRequest) Reset() {
+	*x = PageRequest{}
+	mi := &file_coupon_v1_coupon_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PageRequest) ProtoMessage() {}
+
+func (x *PageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_coupon_v1_coupon_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PageRequest.ProtoReflect.Descriptor instead.
+func (*PageRequest) Descriptor() ([]byte, []int) {
+	return file_coupon_v1_coupon_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *PageRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *PageRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+// PageResponse describes where the next page of a list RPC starts
+type PageResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	NextPageToken string                 `protobuf:"bytes,1,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"` // Empty when there are no more results
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PageResponse) Reset() {
+	*x = PageResponse{}
+	mi := &file_coupon_v1_coupon_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PageResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PageResponse) ProtoMessage() {}
+
+func (x *PageResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_coupon_v1_coupon_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PageResponse.ProtoReflect.Descriptor instead.
+func (*PageResponse) Descriptor() ([]byte, []int) {
+	return file_coupon_v1_coupon_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *PageResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+// SearchCouponsRequest
+type SearchCouponsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CampaignId    int64                  `protobuf:"varint,1,opt,name=campaign_id,json=campaignId,proto3" json:"campaign_id,omitempty"`
+	Prefix        string                 `protobuf:"bytes,2,opt,name=prefix,proto3" json:"prefix,omitempty"` // Leading part of the coupon code
+	Page          *PageRequest           `protobuf:"bytes,5,opt,name=page,proto3" json:"page,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchCouponsRequest) Reset() {
+	*x = SearchCouponsRequest{}
+	mi := &file_coupon_v1_coupon_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchCouponsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchCouponsRequest) ProtoMessage() {}
+
+func (x *SearchCouponsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_coupon_v1_coupon_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchCouponsRequest.ProtoReflect.Descriptor instead.
+func (*SearchCouponsRequest) Descriptor() ([]byte, []int) {
+	return file_coupon_v1_coupon_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *SearchCouponsRequest) GetCampaignId() int64 {
+	if x != nil {
+		return x.CampaignId
+	}
+	return 0
+}
+
+func (x *SearchCouponsRequest) GetPrefix() string {
+	if x != nil {
+		return x.Prefix
+	}
+	return ""
+}
+
+func (x *SearchCouponsRequest) GetPage() *PageRequest {
+	if x != nil {
+		return x.Page
+	}
+	return nil
+}
+
+// CouponSearchResult is a coupon matching a search
+type CouponSearchResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Code          string                 `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	Status        string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`                                                                               // Use coupon_status; lowercase status name (e.g. 'available')
+	IssuedAt      *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=issued_at,json=issuedAt,proto3" json:"issued_at,omitempty"`                                                           // Set only for issued coupons
+	Metadata      map[string]string      `protobuf:"bytes,4,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // Arbitrary partner attributes (e.g. tier, source)
+	CampaignId    int64                  `protobuf:"varint,5,opt,name=campaign_id,json=campaignId,proto3" json:"campaign_id,omitempty"`
+	ExpiresAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"` // Unset when the coupon never expires
+	CouponStatus  CouponStatus           `protobuf:"varint,7,opt,name=coupon_status,json=couponStatus,proto3,enum=coupon.v1.CouponStatus" json:"coupon_status,omitempty"`
+	Pool          string                 `protobuf:"bytes,8,opt,name=pool,proto3" json:"pool,omitempty"` // Empty for campaigns without pools
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CouponSearchResult) Reset() {
+	*x = CouponSearchResult{}
+	mi := &file_coupon_v1_coupon_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CouponSearchResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CouponSearchResult) ProtoMessage() {}
+
+func (x *CouponSearchResult) ProtoReflect() protoreflect.Message {
+	mi := &file_coupon_v1_coupon_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CouponSearchResult.ProtoReflect.Descriptor instead.
+func (*CouponSearchResult) Descriptor() ([]byte, []int) {
+	return file_coupon_v1_coupon_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *CouponSearchResult) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *CouponSearchResult) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *CouponSearchResult) GetIssuedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.IssuedAt
+	}
+	return nil
+}
+
+func (x *CouponSearchResult) GetMetadata() map[string]string {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *CouponSearchResult) GetCampaignId() int64 {
+	if x != nil {
+		return x.CampaignId
+	}
+	return 0
+}
+
+func (x *CouponSearchResult) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+func (x *CouponSearchResult) GetCouponStatus() CouponStatus {
+	if x != nil {
+		return x.CouponStatus
+	}
+	return CouponStatus_COUPON_STATUS_UNSPECIFIED
+}
+
+func (x *CouponSearchResult) GetPool() string {
+	if x != nil {
+		return x.Pool
+	}
+	return ""
+}
+
+// SearchCouponsResponse
+type SearchCouponsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Coupons       []*CouponSearchResult  `protobuf:"bytes,1,rep,name=coupons,proto3" json:"coupons,omitempty"`
+	Page          *PageResponse          `protobuf:"bytes,3,opt,name=page,proto3" json:"page,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchCouponsResponse) Reset() {
+	*x = SearchCouponsResponse{}
+	mi := &file_coupon_v1_coupon_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchCouponsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchCouponsResponse) ProtoMessage() {}
+
+func (x *SearchCouponsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_coupon_v1_coupon_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchCouponsResponse.ProtoReflect.Descriptor instead.
+func (*SearchCouponsResponse) Descriptor() ([]byte, []int) {
+	return file_coupon_v1_coupon_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *SearchCouponsResponse) GetCoupons() []*CouponSearchResult {
+	if x != nil {
+		return x.Coupons
+	}
+	return nil
+}
+
+func (x *SearchCouponsResponse) GetPage() *PageResponse {
+	if x != nil {
+		return x.Page
+	}
+	return nil
+}
+
+// ListCampaignsRequest
+type ListCampaignsRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Page           *PageRequest           `protobuf:"bytes,1,opt,name=page,proto3" json:"page,omitempty"`
+	IncludeDeleted bool                   `protobuf:"varint,2,opt,name=include_deleted,json=includeDeleted,proto3" json:"include_deleted,omitempty"` // Admin only: also list soft-deleted campaigns
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ListCampaignsRequest) Reset() {
+	*x = ListCampaignsRequest{}
+	mi := &file_coupon_v1_coupon_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCampaignsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCampaignsRequest) ProtoMessage() {}
+
+func (x *ListCampaignsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_coupon_v1_coupon_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCampaignsRequest.ProtoReflect.Descriptor instead.
+func (*ListCampaignsRequest) Descriptor() ([]byte, []int) {
+	return file_coupon_v1_coupon_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *ListCampaignsRequest) GetPage() *PageRequest {
+	if x != nil {
+		return x.Page
+	}
+	return nil
+}
+
+func (x *ListCampaignsRequest) GetIncludeDeleted() bool {
+	if x != nil {
+		return x.IncludeDeleted
+	}
+	return false
+}
+
+// ListCampaignsResponse
+type ListCampaignsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Campaigns     []*Campaign            `protobuf:"bytes,1,rep,name=campaigns,proto3" json:"campaigns,omitempty"` // Issued coupon codes are not included
+	Page          *PageResponse          `protobuf:"bytes,2,opt,name=page,proto3" json:"page,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListCampaignsResponse) Reset() {
+	*x = ListCampaignsResponse{}
+	mi := &file_coupon_v1_coupon_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCampaignsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCampaignsResponse) ProtoMessage() {}
+
+func (x *ListCampaignsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_coupon_v1_coupon_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCampaignsResponse.ProtoReflect.Descriptor instead.
+func (*ListCampaignsResponse) Descriptor() ([]byte, []int) {
+	return file_coupon_v1_coupon_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *ListCampaignsResponse) GetCampaigns() []*Campaign {
+	if x != nil {
+		return x.Campaigns
+	}
+	return nil
+}
+
+func (x *ListCampaignsResponse) GetPage() *PageResponse {
+	if x != nil {
+		return x.Page
+	}
+	return nil
+}
+
+// ListCouponsRequest
+type ListCouponsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CampaignId    int64                  `protobuf:"varint,1,opt,name=campaign_id,json=campaignId,proto3" json:"campaign_id,omitempty"`
+	Status        string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"` // Use coupon_status; optional lowercase status name filter
+	Page          *PageRequest           `protobuf:"bytes,3,opt,name=page,proto3" json:"page,omitempty"`
+	MetadataKey   string                 `protobuf:"bytes,4,opt,name=metadata_key,json=metadataKey,proto3" json:"metadata_key,omitempty"`                                 // Optional filter: only coupons whose metadata has this key...
+	MetadataValue string                 `protobuf:"bytes,5,opt,name=metadata_value,json=metadataValue,proto3" json:"metadata_value,omitempty"`                           // ...set to this value
+	CouponStatus  CouponStatus           `protobuf:"varint,6,opt,name=coupon_status,json=couponStatus,proto3,enum=coupon.v1.CouponStatus" json:"coupon_status,omitempty"` // Optional filter; takes precedence over status
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListCouponsRequest) Reset() {
+	*x = ListCouponsRequest{}
+	mi := &file_coupon_v1_coupon_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCouponsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCouponsRequest) ProtoMessage() {}
+
+func (x *ListCouponsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_coupon_v1_coupon_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCouponsRequest.ProtoReflect.Descriptor instead.
+func (*ListCouponsRequest) Descriptor() ([]byte, []int) {
+	return file_coupon_v1_coupon_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *ListCouponsRequest) GetCampaignId() int64 {
+	if x != nil {
+		return x.CampaignId
+	}
+	return 0
+}
+
+func (x *ListCouponsRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *ListCouponsRequest) GetPage() *PageRequest {
+	if x != nil {
+		return x.Page
+	}
+	return nil
+}
+
+func (x *ListCouponsRequest) GetMetadataKey() string {
+	if x != nil {
+		return x.MetadataKey
+	}
+	return ""
+}
+
+func (x *ListCouponsRequest) GetMetadataValue() string {
+	if x != nil {
+		return x.MetadataValue
+	}
+	return ""
+}
+
+func (x *ListCouponsRequest) GetCouponStatus() CouponStatus {
+	if x != nil {
+		return x.CouponStatus
+	}
+	return CouponStatus_COUPON_STATUS_UNSPECIFIED
+}
+
+// GetCouponRequest
+type GetCouponRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Code  string                 `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	// Campaign of the coupon; required when the server scopes codes per
+	// campaign (APP_CODE_NAMESPACE=campaign) unless the code has a routing tag,
+	// optional otherwise
+	CampaignId    int64 `protobuf:"varint,2,opt,name=campaign_id,json=campaignId,proto3" json:"campaign_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCouponRequest) Reset() {
+	*x = GetCouponRequest{}
+	mi := &file_coupon_v1_coupon_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCouponRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCouponRequest) ProtoMessage() {}
+
+func (x *GetCouponRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_coupon_v1_coupon_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCouponRequest.ProtoReflect.Descriptor instead.
+func (*GetCouponRequest) Descriptor() ([]byte, []int) {
+	return file_coupon_v1_coupon_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *GetCouponRequest) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *GetCouponRequest) GetCampaignId() int64 {
+	if x != nil {
+		return x.CampaignId
+	}
+	return 0
+}
+
+// GetCouponResponse
+type GetCouponResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Coupon        *CouponSearchResult    `protobuf:"bytes,1,opt,name=coupon,proto3" json:"coupon,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCouponResponse) Reset() {
+	*x = GetCouponResponse{}
+	mi := &file_coupon_v1_coupon_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCouponResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCouponResponse) ProtoMessage() {}
+
+func (x *GetCouponResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_coupon_v1_coupon_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCouponResponse.ProtoReflect.Descriptor instead.
+func (*GetCouponResponse) Descriptor() ([]byte, []int) {
+	return file_coupon_v1_coupon_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *GetCouponResponse) GetCoupon() *CouponSearchResult {
+	if x != nil {
+		return x.Coupon
+	}
+	return nil
+}
+
+// ValidateCouponRequest
+type ValidateCouponRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Code  string                 `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	// Campaign of the coupon; required when the server scopes codes per
+	// campaign (APP_CODE_NAMESPACE=campaign) unless the code has a routing tag,
+	// optional otherwise
+	CampaignId    int64 `protobuf:"varint,2,opt,name=campaign_id,json=campaignId,proto3" json:"campaign_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ValidateCouponRequest) Reset() {
+	*x = ValidateCouponRequest{}
+	mi := &file_coupon_v1_coupon_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ValidateCouponRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ValidateCouponRequest) ProtoMessage() {}
+
+func (x *ValidateCouponRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_coupon_v1_coupon_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ValidateCouponRequest.ProtoReflect.Descriptor instead.
+func (*ValidateCouponRequest) Descriptor() ([]byte, []int) {
+	return file_coupon_v1_coupon_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *ValidateCouponRequest) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *ValidateCouponRequest) GetCampaignId() int64 {
+	if x != nil {
+		return x.CampaignId
+	}
+	return 0
+}
+
+// ValidateCouponResponse
+type ValidateCouponResponse struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Coupon   *CouponSearchResult    `protobuf:"bytes,1,opt,name=coupon,proto3" json:"coupon,omitempty"`
+	Campaign *CouponCampaign        `protobuf:"bytes,2,opt,name=campaign,proto3" json:"campaign,omitempty"`
+	// True when the coupon is issued, not expired and its campaign has started
+	Redeemable    bool `protobuf:"varint,3,opt,name=redeemable,proto3" json:"redeemable,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ValidateCouponResponse) Reset() {
+	*x = ValidateCouponResponse{}
+	mi := &file_coupon_v1_coupon_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ValidateCouponResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ValidateCouponResponse) ProtoMessage() {}
+
+func (x *ValidateCouponResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_coupon_v1_coupon_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ValidateCouponResponse.ProtoReflect.Descriptor instead.
+func (*ValidateCouponResponse) Descriptor() ([]byte, []int) {
+	return file_coupon_v1_coupon_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *ValidateCouponResponse) GetCoupon() *CouponSearchResult {
+	if x != nil {
+		return x.Coupon
+	}
+	return nil
+}
+
+func (x *ValidateCouponResponse) GetCampaign() *CouponCampaign {
+	if x != nil {
+		return x.Campaign
+	}
+	return nil
+}
+
+func (x *ValidateCouponResponse) GetRedeemable() bool {
+	if x != nil {
+		return x.Redeemable
+	}
+	return false
+}
+
+// CouponCampaign holds the campaign fields relevant when redeeming a coupon
+type CouponCampaign struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	DiscountValue int64                  `protobuf:"varint,2,opt,name=discount_value,json=discountValue,proto3" json:"discount_value,omitempty"` // Value of one coupon in minor currency units
+	StartDate     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=start_date,json=startDate,proto3" json:"start_date,omitempty"`
+	Started       bool                   `protobuf:"varint,4,opt,name=started,proto3" json:"started,omitempty"` // Whether start_date has passed
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CouponCampaign) Reset() {
+	*x = CouponCampaign{}
+	mi := &file_coupon_v1_coupon_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CouponCampaign) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CouponCampaign) ProtoMessage() {}
+
+func (x *CouponCampaign) ProtoReflect() protoreflect.Message {
+	mi := &file_coupon_v1_coupon_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CouponCampaign.ProtoReflect.Descriptor instead.
+func (*CouponCampaign) Descriptor() ([]byte, []int) {
+	return file_coupon_v1_coupon_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *CouponCampaign) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *CouponCampaign) GetDiscountValue() int64 {
+	if x != nil {
+		return x.DiscountValue
+	}
+	return 0
+}
+
+func (x *CouponCampaign) GetStartDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartDate
+	}
+	return nil
+}
+
+func (x *CouponCampaign) GetStarted() bool {
+	if x != nil {
+		return x.Started
+	}
+	return false
+}
+
+// GetCouponPayloadRequest
+type GetCouponPayloadRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Code  string                 `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	// Campaign of the coupon; required when the server scopes codes per
+	// campaign (APP_CODE_NAMESPACE=campaign) unless the code has a routing tag,
+	// optional otherwise
+	CampaignId    int64 `protobuf:"varint,2,opt,name=campaign_id,json=campaignId,proto3" json:"campaign_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCouponPayloadRequest) Reset() {
+	*x = GetCouponPayloadRequest{}
+	mi := &file_coupon_v1_coupon_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCouponPayloadRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCouponPayloadRequest) ProtoMessage() {}
+
+func (x *GetCouponPayloadRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_coupon_v1_coupon_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCouponPayloadRequest.ProtoReflect.Descriptor instead.
+func (*GetCouponPayloadRequest) Descriptor() ([]byte, []int) {
+	return file_coupon_v1_coupon_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *GetCouponPayloadRequest) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *GetCouponPayloadRequest) GetCampaignId() int64 {
+	if x != nil {
+		return x.CampaignId
+	}
+	return 0
+}
+
+// GetCouponPayloadResponse carries the scannable form of an issued coupon.
+//
+// token is "<message>.<signature>", both base64url without padding. message
+// is the UTF-8 text "v1\n<code>\n<campaign_id>\n<expires_at unix seconds, 0 if
+// none>" and signature is HMAC-SHA256 over the decoded message keyed with
+// APP_COUPON_SIGNING_SECRET. Verifiers recompute the HMAC, compare it in
+// constant time, then check the expiry.
+type GetCouponPayloadResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Code          string                 `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	CampaignId    int64                  `protobuf:"varint,2,opt,name=campaign_id,json=campaignId,proto3" json:"campaign_id,omitempty"`
+	ExpiresAt     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"` // Unset when the coupon never expires
+	Token         string                 `protobuf:"bytes,4,opt,name=token,proto3" json:"token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCouponPayloadResponse) Reset() {
+	*x = GetCouponPayloadResponse{}
+	mi := &file_coupon_v1_coupon_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCouponPayloadResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCouponPayloadResponse) ProtoMessage() {}
+
+func (x *GetCouponPayloadResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_coupon_v1_coupon_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCouponPayloadResponse.ProtoReflect.Descriptor instead.
+func (*GetCouponPayloadResponse) Descriptor() ([]byte, []int) {
+	return file_coupon_v1_coupon_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *GetCouponPayloadResponse) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *GetCouponPayloadResponse) GetCampaignId() int64 {
+	if x != nil {
+		return x.CampaignId
+	}
+	return 0
+}
+
+func (x *GetCouponPayloadResponse) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+func (x *GetCouponPayloadResponse) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+// ListCouponsResponse
+type ListCouponsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Coupons       []*CouponSearchResult  `protobuf:"bytes,1,rep,name=coupons,proto3" json:"coupons,omitempty"`
+	Page          *PageResponse          `protobuf:"bytes,2,opt,name=page,proto3" json:"page,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListCouponsResponse) Reset() {
+	*x = ListCouponsResponse{}
+	mi := &file_coupon_v1_coupon_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCouponsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCouponsResponse) ProtoMessage() {}
+
+func (x *ListCouponsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_coupon_v1_coupon_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCouponsResponse.ProtoReflect.Descriptor instead.
+func (*ListCouponsResponse) Descriptor() ([]byte, []int) {
+	return file_coupon_v1_coupon_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *ListCouponsResponse) GetCoupons() []*CouponSearchResult {
+	if x != nil {
+		return x.Coupons
+	}
+	return nil
+}
+
+func (x *ListCouponsResponse) GetPage() *PageResponse {
+	if x != nil {
+		return x.Page
+	}
+	return nil
+}
+
+// DeleteCampaignRequest
+type DeleteCampaignRequest struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	CampaignId int64                  `protobuf:"varint,1,opt,name=campaign_id,json=campaignId,proto3" json:"campaign_id,omitempty"`
+	// Delete the campaign even if it has issued or redeemed coupons
+	Force         bool `protobuf:"varint,2,opt,name=force,proto3" json:"force,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteCampaignRequest) Reset() {
+	*x = DeleteCampaignRequest{}
+	mi := &file_coupon_v1_coupon_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteCampaignRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteCampaignRequest) ProtoMessage() {}
+
+func (x *DeleteCampaignRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_coupon_v1_coupon_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteCampaignRequest.ProtoReflect.Descriptor instead.
+func (*DeleteCampaignRequest) Descriptor() ([]byte, []int) {
+	return file_coupon_v1_coupon_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *DeleteCampaignRequest) GetCampaignId() int64 {
+	if x != nil {
+		return x.CampaignId
+	}
+	return 0
+}
+
+func (x *DeleteCampaignRequest) GetForce() bool {
+	if x != nil {
+		return x.Force
+	}
+	return false
+}
+
+// DeleteCampaignResponse
+type DeleteCampaignResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DeletedAt     *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=deleted_at,json=deletedAt,proto3" json:"deleted_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteCampaignResponse) Reset() {
+	*x = DeleteCampaignResponse{}
+	mi := &file_coupon_v1_coupon_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteCampaignResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteCampaignResponse) ProtoMessage() {}
+
+func (x *DeleteCampaignResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_coupon_v1_coupon_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteCampaignResponse.ProtoReflect.Descriptor instead.
+func (*DeleteCampaignResponse) Descriptor() ([]byte, []int) {
+	return file_coupon_v1_coupon_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *DeleteCampaignResponse) GetDeletedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DeletedAt
+	}
+	return nil
+}
+
+// RestoreCampaignRequest
+type RestoreCampaignRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CampaignId    int64                  `protobuf:"varint,1,opt,name=campaign_id,json=campaignId,proto3" json:"campaign_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RestoreCampaignRequest) Reset() {
+	*x = RestoreCampaignRequest{}
+	mi := &file_coupon_v1_coupon_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RestoreCampaignRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestoreCampaignRequest) ProtoMessage() {}
+
+func (x *RestoreCampaignRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_coupon_v1_coupon_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestoreCampaignRequest.ProtoReflect.Descriptor instead.
+func (*RestoreCampaignRequest) Descriptor() ([]byte, []int) {
+	return file_coupon_v1_coupon_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *RestoreCampaignRequest) GetCampaignId() int64 {
+	if x != nil {
+		return x.CampaignId
+	}
+	return 0
+}
+
+// RestoreCampaignResponse
+type RestoreCampaignResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Campaign      *Campaign              `protobuf:"bytes,1,opt,name=campaign,proto3" json:"campaign,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RestoreCampaignResponse) Reset() {
+	*x = RestoreCampaignResponse{}
+	mi := &file_coupon_v1_coupon_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RestoreCampaignResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestoreCampaignResponse) ProtoMessage() {}
+
+func (x *RestoreCampaignResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_coupon_v1_coupon_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestoreCampaignResponse.ProtoReflect.Descriptor instead.
+func (*RestoreCampaignResponse) Descriptor() ([]byte, []int) {
+	return file_coupon_v1_coupon_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *RestoreCampaignResponse) GetCampaign() *Campaign {
+	if x != nil {
+		return x.Campaign
+	}
+	return nil
+}
+
+// TransferCouponsRequest
+type TransferCouponsRequest struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	SourceCampaignId int64                  `protobuf:"varint,1,opt,name=source_campaign_id,json=sourceCampaignId,proto3" json:"source_campaign_id,omitempty"`
+	TargetCampaignId int64                  `protobuf:"varint,2,opt,name=target_campaign_id,json=targetCampaignId,proto3" json:"target_campaign_id,omitempty"`
+	Codes            []string               `protobuf:"bytes,3,rep,name=codes,proto3" json:"codes,omitempty"` // Available coupons of the source campaign to move (max APP_MAX_TRANSFER_CODES, default 10000)
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *TransferCouponsRequest) Reset() {
+	*x = TransferCouponsRequest{}
+	mi := &file_coupon_v1_coupon_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TransferCouponsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TransferCouponsRequest) ProtoMessage() {}
+
+func (x *TransferCouponsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_coupon_v1_coupon_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TransferCouponsRequest.ProtoReflect.Descriptor instead.
+func (*TransferCouponsRequest) Descriptor() ([]byte, []int) {
+	return file_coupon_v1_coupon_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *TransferCouponsRequest) GetSourceCampaignId() int64 {
+	if x != nil {
+		return x.SourceCampaignId
+	}
+	return 0
+}
+
+func (x *TransferCouponsRequest) GetTargetCampaignId() int64 {
+	if x != nil {
+		return x.TargetCampaignId
+	}
+	return 0
+}
+
+func (x *TransferCouponsRequest) GetCodes() []string {
+	if x != nil {
+		return x.Codes
+	}
+	return nil
+}
+
+// TransferCouponsResponse
+type TransferCouponsResponse struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	TransferredCount int32                  `protobuf:"varint,1,opt,name=transferred_count,json=transferredCount,proto3" json:"transferred_count,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *TransferCouponsResponse) Reset() {
+	*x = TransferCouponsResponse{}
+	mi := &file_coupon_v1_coupon_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TransferCouponsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TransferCouponsResponse) ProtoMessage() {}
+
+func (x *TransferCouponsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_coupon_v1_coupon_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TransferCouponsResponse.ProtoReflect.Descriptor instead.
+func (*TransferCouponsResponse) Descriptor() ([]byte, []int) {
+	return file_coupon_v1_coupon_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *TransferCouponsResponse) GetTransferredCount() int32 {
+	if x != nil {
+		return x.TransferredCount
+	}
+	return 0
+}
+
+// RevokeCampaignCouponsRequest
+type RevokeCampaignCouponsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CampaignId    int64                  `protobuf:"varint,1,opt,name=campaign_id,json=campaignId,proto3" json:"campaign_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokeCampaignCouponsRequest) Reset() {
+	*x = RevokeCampaignCouponsRequest{}
+	mi := &file_coupon_v1_coupon_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeCampaignCouponsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeCampaignCouponsRequest) ProtoMessage() {}
+
+func (x *RevokeCampaignCouponsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_coupon_v1_coupon_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeCampaignCouponsRequest.ProtoReflect.Descriptor instead.
+func (*RevokeCampaignCouponsRequest) Descriptor() ([]byte, []int) {
+	return file_coupon_v1_coupon_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *RevokeCampaignCouponsRequest) GetCampaignId() int64 {
+	if x != nil {
+		return x.CampaignId
+	}
+	return 0
+}
+
+// RevokeCampaignCouponsResponse
+type RevokeCampaignCouponsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RevokedCount  int32                  `protobuf:"varint,1,opt,name=revoked_count,json=revokedCount,proto3" json:"revoked_count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokeCampaignCouponsResponse) Reset() {
+	*x = RevokeCampaignCouponsResponse{}
+	mi := &file_coupon_v1_coupon_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeCampaignCouponsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeCampaignCouponsResponse) ProtoMessage() {}
+
+func (x *RevokeCampaignCouponsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_coupon_v1_coupon_proto_msgTypes[47]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeCampaignCouponsResponse.ProtoReflect.Descriptor instead.
+func (*RevokeCampaignCouponsResponse) Descriptor() ([]byte, []int) {
+	return file_coupon_v1_coupon_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *RevokeCampaignCouponsResponse) GetRevokedCount() int32 {
+	if x != nil {
+		return x.RevokedCount
+	}
+	return 0
+}
+
+// UpdateCampaignRequest
+type UpdateCampaignRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CampaignId    int64                  `protobuf:"varint,1,opt,name=campaign_id,json=campaignId,proto3" json:"campaign_id,omitempty"`
+	MaxIssueRps   float64                `protobuf:"fixed64,2,opt,name=max_issue_rps,json=maxIssueRps,proto3" json:"max_issue_rps,omitempty"` // New issuance rate limit; 0 reverts to the server default
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateCampaignRequest) Reset() {
+	*x = UpdateCampaignRequest{}
+	mi := &file_coupon_v1_coupon_proto_msgTypes[48]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateCampaignRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateCampaignRequest) ProtoMessage() {}
+
+func (x *UpdateCampaignRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_coupon_v1_coupon_proto_msgTypes[48]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateCampaignRequest.ProtoReflect.Descriptor instead.
+func (*UpdateCampaignRequest) Descriptor() ([]byte, []int) {
+	return file_coupon_v1_coupon_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *UpdateCampaignRequest) GetCampaignId() int64 {
+	if x != nil {
+		return x.CampaignId
+	}
+	return 0
+}
+
+func (x *UpdateCampaignRequest) GetMaxIssueRps() float64 {
+	if x != nil {
+		return x.MaxIssueRps
+	}
+	return 0
+}
+
+// UpdateCampaignResponse
+type UpdateCampaignResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Campaign      *Campaign              `protobuf:"bytes,1,opt,name=campaign,proto3" json:"campaign,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateCampaignResponse) Reset() {
+	*x = UpdateCampaignResponse{}
+	mi := &file_coupon_v1_coupon_proto_msgTypes[49]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateCampaignResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateCampaignResponse) ProtoMessage() {}
+
+func (x *UpdateCampaignResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_coupon_v1_coupon_proto_msgTypes[49]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateCampaignResponse.ProtoReflect.Descriptor instead.
+func (*UpdateCampaignResponse) Descriptor() ([]byte, []int) {
+	return file_coupon_v1_coupon_proto_rawDescGZIP(), []int{49}
+}
+
+func (x *UpdateCampaignResponse) GetCampaign() *Campaign {
+	if x != nil {
+		return x.Campaign
+	}
+	return nil
+}
+
+// DrainCampaignRequest
+type DrainCampaignRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CampaignId    int64                  `protobuf:"varint,1,opt,name=campaign_id,json=campaignId,proto3" json:"campaign_id,omitempty"`
+	Recipient     string                 `protobuf:"bytes,2,opt,name=recipient,proto3" json:"recipient,omitempty"` // Required; all coupons are issued to this user ID
+	Confirm       bool                   `protobuf:"varint,3,opt,name=confirm,proto3" json:"confirm,omitempty"`    // Must be true; guards against accidental drains
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DrainCampaignRequest) Reset() {
+	*x = DrainCampaignRequest{}
+	mi := &file_coupon_v1_coupon_proto_msgTypes[50]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DrainCampaignRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DrainCampaignRequest) ProtoMessage() {}
+
+func (x *DrainCampaignRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_coupon_v1_coupon_proto_msgTypes[50]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DrainCampaignRequest.ProtoReflect.Descriptor instead.
+func (*DrainCampaignRequest) Descriptor() ([]byte, []int) {
+	return file_coupon_v1_coupon_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *DrainCampaignRequest) GetCampaignId() int64 {
+	if x != nil {
+		return x.CampaignId
+	}
+	return 0
+}
+
+func (x *DrainCampaignRequest) GetRecipient() string {
+	if x != nil {
+		return x.Recipient
+	}
+	return ""
+}
+
+func (x *DrainCampaignRequest) GetConfirm() bool {
+	if x != nil {
+		return x.Confirm
+	}
+	return false
+}
+
+// DrainCampaignResponse
+type DrainCampaignResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Codes         []string               `protobuf:"bytes,1,rep,name=codes,proto3" json:"codes,omitempty"` // Issued coupon codes
+	IssuedCount   int32                  `protobuf:"varint,2,opt,name=issued_count,json=issuedCount,proto3" json:"issued_count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DrainCampaignResponse) Reset() {
+	*x = DrainCampaignResponse{}
+	mi := &file_coupon_v1_coupon_proto_msgTypes[51]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DrainCampaignResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DrainCampaignResponse) ProtoMessage() {}
+
+func (x *DrainCampaignResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_coupon_v1_coupon_proto_msgTypes[51]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DrainCampaignResponse.ProtoReflect.Descriptor instead.
+func (*DrainCampaignResponse) Descriptor() ([]byte, []int) {
+	return file_coupon_v1_coupon_proto_rawDescGZIP(), []int{51}
+}
+
+func (x *DrainCampaignResponse) GetCodes() []string {
+	if x != nil {
+		return x.Codes
+	}
+	return nil
+}
+
+func (x *DrainCampaignResponse) GetIssuedCount() int32 {
+	if x != nil {
+		return x.IssuedCount
+	}
+	return 0
+}
+
+// PeekAvailableCouponsRequest
+type PeekAvailableCouponsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CampaignId    int64                  `protobuf:"varint,1,opt,name=campaign_id,json=campaignId,proto3" json:"campaign_id,omitempty"`
+	Limit         int32                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"` // Default 10, max APP_MAX_PEEK_LIMIT (default 100)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PeekAvailableCouponsRequest) Reset() {
+	*x = PeekAvailableCouponsRequest{}
+	mi := &file_coupon_v1_coupon_proto_msgTypes[52]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PeekAvailableCouponsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PeekAvailableCouponsRequest) ProtoMessage() {}
+
+func (x *PeekAvailableCouponsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_coupon_v1_coupon_proto_msgTypes[52]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PeekAvailableCouponsRequest.ProtoReflect.Descriptor instead.
+func (*PeekAvailableCouponsRequest) Descriptor() ([]byte, []int) {
+	return file_coupon_v1_coupon_proto_rawDescGZIP(), []int{52}
+}
+
+func (x *PeekAvailableCouponsRequest) GetCampaignId() int64 {
+	if x != nil {
+		return x.CampaignId
+	}
+	return 0
+}
+
+func (x *PeekAvailableCouponsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+// PeekAvailableCouponsResponse
+type PeekAvailableCouponsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Codes         []string               `protobuf:"bytes,1,rep,name=codes,proto3" json:"codes,omitempty"` // Oldest available coupons first
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PeekAvailableCouponsResponse) Reset() {
+	*x = PeekAvailableCouponsResponse{}
+	mi := &file_coupon_v1_coupon_proto_msgTypes[53]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Campaign) String() string {
+func (x *PeekAvailableCouponsResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Campaign) ProtoMessage() {}
+func (*PeekAvailableCouponsResponse) ProtoMessage() {}
 
-func (x *Campaign) ProtoReflect() protoreflect.Message {
-	mi := &file_coupon_v1_coupon_proto_msgTypes[0]
+func (x *PeekAvailableCouponsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_coupon_v1_coupon_proto_msgTypes[53]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -58,63 +3780,93 @@ func (x *Campaign) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Campaign.ProtoReflect.Descriptor instead.
-func (*Campaign) Descriptor() ([]byte, []int) {
-	return file_coupon_v1_coupon_proto_rawDescGZIP(), []int{0}
+// Deprecated: Use PeekAvailableCouponsResponse.ProtoReflect.Descriptor instead.
+func (*PeekAvailableCouponsResponse) Descriptor() ([]byte, []int) {
+	return file_coupon_v1_coupon_proto_rawDescGZIP(), []int{53}
 }
 
-func (x *Campaign) GetId() int64 {
+func (x *PeekAvailableCouponsResponse) GetCodes() []string {
 	if x != nil {
-		return x.Id
+		return x.Codes
 	}
-	return 0
+	return nil
 }
 
-func (x *Campaign) GetAvailableCoupons() int32 {
-	if x != nil {
-		return x.AvailableCoupons
-	}
-	return 0
+// VerifyCampaignCodesRequest
+type VerifyCampaignCodesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CampaignId    int64                  `protobuf:"varint,1,opt,name=campaign_id,json=campaignId,proto3" json:"campaign_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *Campaign) GetStartDate() *timestamppb.Timestamp {
+func (x *VerifyCampaignCodesRequest) Reset() {
+	*x = VerifyCampaignCodesRequest{}
+	mi := &file_coupon_v1_coupon_proto_msgTypes[54]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VerifyCampaignCodesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifyCampaignCodesRequest) ProtoMessage() {}
+
+func (x *VerifyCampaignCodesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_coupon_v1_coupon_proto_msgTypes[54]
 	if x != nil {
-		return x.StartDate
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return nil
+	return mi.MessageOf(x)
 }
 
-func (x *Campaign) GetIssuedCouponCodes() []string {
+// Deprecated: Use VerifyCampaignCodesRequest.ProtoReflect.Descriptor instead.
+func (*VerifyCampaignCodesRequest) Descriptor() ([]byte, []int) {
+	return file_coupon_v1_coupon_proto_rawDescGZIP(), []int{54}
+}
+
+func (x *VerifyCampaignCodesRequest) GetCampaignId() int64 {
 	if x != nil {
-		return x.IssuedCouponCodes
+		return x.CampaignId
 	}
-	return nil
+	return 0
 }
 
-// Coupon represents an issued coupon
-type Coupon struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Code          string                 `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
-	CampaignId    int64                  `protobuf:"varint,2,opt,name=campaign_id,json=campaignId,proto3" json:"campaign_id,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+// VerifyCampaignCodesResponse reports the problems found. Lists hold at most
+// 100 entries; the counts are complete.
+type VerifyCampaignCodesResponse struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	CheckedCount int64                  `protobuf:"varint,1,opt,name=checked_count,json=checkedCount,proto3" json:"checked_count,omitempty"` // Coupons scanned
+	// Coupons without a recorded index, created before indexes were stored;
+	// they can't be verified
+	UnindexedCount   int64    `protobuf:"varint,2,opt,name=unindexed_count,json=unindexedCount,proto3" json:"unindexed_count,omitempty"`
+	MismatchedCount  int64    `protobuf:"varint,3,opt,name=mismatched_count,json=mismatchedCount,proto3" json:"mismatched_count,omitempty"` // Coupons whose code doesn't match their index
+	MismatchedCodes  []string `protobuf:"bytes,4,rep,name=mismatched_codes,json=mismatchedCodes,proto3" json:"mismatched_codes,omitempty"`
+	DuplicateIndexes []int64  `protobuf:"varint,5,rep,packed,name=duplicate_indexes,json=duplicateIndexes,proto3" json:"duplicate_indexes,omitempty"` // Indexes shared by more than one coupon
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
 }
 
-func (x *Coupon) Reset() {
-	*x = Coupon{}
-	mi := &file_coupon_v1_coupon_proto_msgTypes[1]
+func (x *VerifyCampaignCodesResponse) Reset() {
+	*x = VerifyCampaignCodesResponse{}
+	mi := &file_coupon_v1_coupon_proto_msgTypes[55]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Coupon) String() string {
+func (x *VerifyCampaignCodesResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Coupon) ProtoMessage() {}
+func (*VerifyCampaignCodesResponse) ProtoMessage() {}
 
-func (x *Coupon) ProtoReflect() protoreflect.Message {
-	mi := &file_coupon_v1_coupon_proto_msgTypes[1]
+func (x *VerifyCampaignCodesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_coupon_v1_coupon_proto_msgTypes[55]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -125,49 +3877,72 @@ func (x *Coupon) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Coupon.ProtoReflect.Descriptor instead.
-func (*Coupon) Descriptor() ([]byte, []int) {
-	return file_coupon_v1_coupon_proto_rawDescGZIP(), []int{1}
+// Deprecated: Use VerifyCampaignCodesResponse.ProtoReflect.Descriptor instead.
+func (*VerifyCampaignCodesResponse) Descriptor() ([]byte, []int) {
+	return file_coupon_v1_coupon_proto_rawDescGZIP(), []int{55}
 }
 
-func (x *Coupon) GetCode() string {
+func (x *VerifyCampaignCodesResponse) GetCheckedCount() int64 {
 	if x != nil {
-		return x.Code
+		return x.CheckedCount
 	}
-	return ""
+	return 0
 }
 
-func (x *Coupon) GetCampaignId() int64 {
+func (x *VerifyCampaignCodesResponse) GetUnindexedCount() int64 {
 	if x != nil {
-		return x.CampaignId
+		return x.UnindexedCount
 	}
 	return 0
 }
 
-// CreateCampaignRequest
-type CreateCampaignRequest struct {
-	state            protoimpl.MessageState `protogen:"open.v1"`
-	AvailableCoupons int32                  `protobuf:"varint,1,opt,name=available_coupons,json=availableCoupons,proto3" json:"available_coupons,omitempty"` // Number of available coupons
-	StartDate        *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=start_date,json=startDate,proto3" json:"start_date,omitempty"`                       // Specific start date and time
-	unknownFields    protoimpl.UnknownFields
-	sizeCache        protoimpl.SizeCache
+func (x *VerifyCampaignCodesResponse) GetMismatchedCount() int64 {
+	if x != nil {
+		return x.MismatchedCount
+	}
+	return 0
 }
 
-func (x *CreateCampaignRequest) Reset() {
-	*x = CreateCampaignRequest{}
-	mi := &file_coupon_v1_coupon_proto_msgTypes[2]
+func (x *VerifyCampaignCodesResponse) GetMismatchedCodes() []string {
+	if x != nil {
+		return x.MismatchedCodes
+	}
+	return nil
+}
+
+func (x *VerifyCampaignCodesResponse) GetDuplicateIndexes() []int64 {
+	if x != nil {
+		return x.DuplicateIndexes
+	}
+	return nil
+}
+
+// SoldOutInfo is attached as an error detail to ResourceExhausted responses
+// from IssueCoupon when the campaign has no coupons left
+type SoldOutInfo struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CampaignId    int64                  `protobuf:"varint,1,opt,name=campaign_id,json=campaignId,proto3" json:"campaign_id,omitempty"`
+	Remaining     int32                  `protobuf:"varint,2,opt,name=remaining,proto3" json:"remaining,omitempty"` // Coupons left; non-zero when the budget ran out first
+	Reason        SoldOutReason          `protobuf:"varint,3,opt,name=reason,proto3,enum=coupon.v1.SoldOutReason" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SoldOutInfo) Reset() {
+	*x = SoldOutInfo{}
+	mi := &file_coupon_v1_coupon_proto_msgTypes[56]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CreateCampaignRequest) String() string {
+func (x *SoldOutInfo) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateCampaignRequest) ProtoMessage() {}
+func (*SoldOutInfo) ProtoMessage() {}
 
-func (x *CreateCampaignRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_coupon_v1_coupon_proto_msgTypes[2]
+func (x *SoldOutInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_coupon_v1_coupon_proto_msgTypes[56]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -178,48 +3953,56 @@ func (x *CreateCampaignRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateCampaignRequest.ProtoReflect.Descriptor instead.
-func (*CreateCampaignRequest) Descriptor() ([]byte, []int) {
-	return file_coupon_v1_coupon_proto_rawDescGZIP(), []int{2}
+// Deprecated: Use SoldOutInfo.ProtoReflect.Descriptor instead.
+func (*SoldOutInfo) Descriptor() ([]byte, []int) {
+	return file_coupon_v1_coupon_proto_rawDescGZIP(), []int{56}
 }
 
-func (x *CreateCampaignRequest) GetAvailableCoupons() int32 {
+func (x *SoldOutInfo) GetCampaignId() int64 {
 	if x != nil {
-		return x.AvailableCoupons
+		return x.CampaignId
 	}
 	return 0
 }
 
-func (x *CreateCampaignRequest) GetStartDate() *timestamppb.Timestamp {
+func (x *SoldOutInfo) GetRemaining() int32 {
 	if x != nil {
-		return x.StartDate
+		return x.Remaining
 	}
-	return nil
+	return 0
 }
 
-// CreateCampaignResponse
-type CreateCampaignResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Campaign      *Campaign              `protobuf:"bytes,1,opt,name=campaign,proto3" json:"campaign,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *SoldOutInfo) GetReason() SoldOutReason {
+	if x != nil {
+		return x.Reason
+	}
+	return SoldOutReason_SOLD_OUT_REASON_UNSPECIFIED
 }
 
-func (x *CreateCampaignResponse) Reset() {
-	*x = CreateCampaignResponse{}
-	mi := &file_coupon_v1_coupon_proto_msgTypes[3]
+// BadRequest is attached as an error detail to InvalidArgument responses that
+// report every invalid field of the request at once
+type BadRequest struct {
+	state           protoimpl.MessageState       `protogen:"open.v1"`
+	FieldViolations []*BadRequest_FieldViolation `protobuf:"bytes,1,rep,name=field_violations,json=fieldViolations,proto3" json:"field_violations,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *BadRequest) Reset() {
+	*x = BadRequest{}
+	mi := &file_coupon_v1_coupon_proto_msgTypes[57]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CreateCampaignResponse) String() string {
+func (x *BadRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateCampaignResponse) ProtoMessage() {}
+func (*BadRequest) ProtoMessage() {}
 
-func (x *CreateCampaignResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_coupon_v1_coupon_proto_msgTypes[3]
+func (x *BadRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_coupon_v1_coupon_proto_msgTypes[57]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -230,41 +4013,43 @@ func (x *CreateCampaignResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateCampaignResponse.ProtoReflect.Descriptor instead.
-func (*CreateCampaignResponse) Descriptor() ([]byte, []int) {
-	return file_coupon_v1_coupon_proto_rawDescGZIP(), []int{3}
+// Deprecated: Use BadRequest.ProtoReflect.Descriptor instead.
+func (*BadRequest) Descriptor() ([]byte, []int) {
+	return file_coupon_v1_coupon_proto_rawDescGZIP(), []int{57}
 }
 
-func (x *CreateCampaignResponse) GetCampaign() *Campaign {
+func (x *BadRequest) GetFieldViolations() []*BadRequest_FieldViolation {
 	if x != nil {
-		return x.Campaign
+		return x.FieldViolations
 	}
 	return nil
 }
 
-// GetCampaignRequest
-type GetCampaignRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	CampaignId    int64                  `protobuf:"varint,1,opt,name=campaign_id,json=campaignId,proto3" json:"campaign_id,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+// IssuanceWindowInfo is attached to FailedPrecondition errors of issuance
+// outside the campaign's daily window
+type IssuanceWindowInfo struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	CampaignId      int64                  `protobuf:"varint,1,opt,name=campaign_id,json=campaignId,proto3" json:"campaign_id,omitempty"`
+	NextWindowStart *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=next_window_start,json=nextWindowStart,proto3" json:"next_window_start,omitempty"` // When the window next opens
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
 }
 
-func (x *GetCampaignRequest) Reset() {
-	*x = GetCampaignRequest{}
-	mi := &file_coupon_v1_coupon_proto_msgTypes[4]
+func (x *IssuanceWindowInfo) Reset() {
+	*x = IssuanceWindowInfo{}
+	mi := &file_coupon_v1_coupon_proto_msgTypes[58]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetCampaignRequest) String() string {
+func (x *IssuanceWindowInfo) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetCampaignRequest) ProtoMessage() {}
+func (*IssuanceWindowInfo) ProtoMessage() {}
 
-func (x *GetCampaignRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_coupon_v1_coupon_proto_msgTypes[4]
+func (x *IssuanceWindowInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_coupon_v1_coupon_proto_msgTypes[58]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -275,41 +4060,49 @@ func (x *GetCampaignRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetCampaignRequest.ProtoReflect.Descriptor instead.
-func (*GetCampaignRequest) Descriptor() ([]byte, []int) {
-	return file_coupon_v1_coupon_proto_rawDescGZIP(), []int{4}
+// Deprecated: Use IssuanceWindowInfo.ProtoReflect.Descriptor instead.
+func (*IssuanceWindowInfo) Descriptor() ([]byte, []int) {
+	return file_coupon_v1_coupon_proto_rawDescGZIP(), []int{58}
 }
 
-func (x *GetCampaignRequest) GetCampaignId() int64 {
+func (x *IssuanceWindowInfo) GetCampaignId() int64 {
 	if x != nil {
 		return x.CampaignId
 	}
 	return 0
 }
 
-// GetCampaignResponse
-type GetCampaignResponse struct {
+func (x *IssuanceWindowInfo) GetNextWindowStart() *timestamppb.Timestamp {
+	if x != nil {
+		return x.NextWindowStart
+	}
+	return nil
+}
+
+// RetryInfo is attached as an error detail when the request may succeed if
+// retried after the given delay
+type RetryInfo struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Campaign      *Campaign              `protobuf:"bytes,1,opt,name=campaign,proto3" json:"campaign,omitempty"`
+	RetryDelay    *durationpb.Duration   `protobuf:"bytes,1,opt,name=retry_delay,json=retryDelay,proto3" json:"retry_delay,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetCampaignResponse) Reset() {
-	*x = GetCampaignResponse{}
-	mi := &file_coupon_v1_coupon_proto_msgTypes[5]
+func (x *RetryInfo) Reset() {
+	*x = RetryInfo{}
+	mi := &file_coupon_v1_coupon_proto_msgTypes[59]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetCampaignResponse) String() string {
+func (x *RetryInfo) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetCampaignResponse) ProtoMessage() {}
+func (*RetryInfo) ProtoMessage() {}
 
-func (x *GetCampaignResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_coupon_v1_coupon_proto_msgTypes[5]
+func (x *RetryInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_coupon_v1_coupon_proto_msgTypes[59]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -320,41 +4113,45 @@ func (x *GetCampaignResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetCampaignResponse.ProtoReflect.Descriptor instead.
-func (*GetCampaignResponse) Descriptor() ([]byte, []int) {
-	return file_coupon_v1_coupon_proto_rawDescGZIP(), []int{5}
+// Deprecated: Use RetryInfo.ProtoReflect.Descriptor instead.
+func (*RetryInfo) Descriptor() ([]byte, []int) {
+	return file_coupon_v1_coupon_proto_rawDescGZIP(), []int{59}
 }
 
-func (x *GetCampaignResponse) GetCampaign() *Campaign {
+func (x *RetryInfo) GetRetryDelay() *durationpb.Duration {
 	if x != nil {
-		return x.Campaign
+		return x.RetryDelay
 	}
 	return nil
 }
 
-// IssueCouponRequest
-type IssueCouponRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	CampaignId    int64                  `protobuf:"varint,1,opt,name=campaign_id,json=campaignId,proto3" json:"campaign_id,omitempty"`
+// ErrorInfo is attached as an error detail to every error returned by the
+// service's handlers, so clients can tell failures apart without parsing
+// messages
+type ErrorInfo struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Stable error code, e.g. "ERR_CAMPAIGN_NOT_FOUND" or "ERR_SOLD_OUT". New
+	// codes may be added; existing ones never change meaning.
+	Reason        string `protobuf:"bytes,1,opt,name=reason,proto3" json:"reason,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *IssueCouponRequest) Reset() {
-	*x = IssueCouponRequest{}
-	mi := &file_coupon_v1_coupon_proto_msgTypes[6]
+func (x *ErrorInfo) Reset() {
+	*x = ErrorInfo{}
+	mi := &file_coupon_v1_coupon_proto_msgTypes[60]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *IssueCouponRequest) String() string {
+func (x *ErrorInfo) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*IssueCouponRequest) ProtoMessage() {}
+func (*ErrorInfo) ProtoMessage() {}
 
-func (x *IssueCouponRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_coupon_v1_coupon_proto_msgTypes[6]
+func (x *ErrorInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_coupon_v1_coupon_proto_msgTypes[60]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -365,41 +4162,42 @@ func (x *IssueCouponRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use IssueCouponRequest.ProtoReflect.Descriptor instead.
-func (*IssueCouponRequest) Descriptor() ([]byte, []int) {
-	return file_coupon_v1_coupon_proto_rawDescGZIP(), []int{6}
+// Deprecated: Use ErrorInfo.ProtoReflect.Descriptor instead.
+func (*ErrorInfo) Descriptor() ([]byte, []int) {
+	return file_coupon_v1_coupon_proto_rawDescGZIP(), []int{60}
 }
 
-func (x *IssueCouponRequest) GetCampaignId() int64 {
+func (x *ErrorInfo) GetReason() string {
 	if x != nil {
-		return x.CampaignId
+		return x.Reason
 	}
-	return 0
+	return ""
 }
 
-// IssueCouponResponse
-type IssueCouponResponse struct {
+// FieldViolation describes one invalid request field
+type BadRequest_FieldViolation struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Coupon        *Coupon                `protobuf:"bytes,1,opt,name=coupon,proto3" json:"coupon,omitempty"`
+	Field         string                 `protobuf:"bytes,1,opt,name=field,proto3" json:"field,omitempty"`             // Request field name, e.g. "available_coupons"
+	Description   string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"` // Why the value is invalid
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *IssueCouponResponse) Reset() {
-	*x = IssueCouponResponse{}
-	mi := &file_coupon_v1_coupon_proto_msgTypes[7]
+func (x *BadRequest_FieldViolation) Reset() {
+	*x = BadRequest_FieldViolation{}
+	mi := &file_coupon_v1_coupon_proto_msgTypes[63]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *IssueCouponResponse) String() string {
+func (x *BadRequest_FieldViolation) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*IssueCouponResponse) ProtoMessage() {}
+func (*BadRequest_FieldViolation) ProtoMessage() {}
 
-func (x *IssueCouponResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_coupon_v1_coupon_proto_msgTypes[7]
+func (x *BadRequest_FieldViolation) ProtoReflect() protoreflect.Message {
+	mi := &file_coupon_v1_coupon_proto_msgTypes[63]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -410,53 +4208,396 @@ func (x *IssueCouponResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use IssueCouponResponse.ProtoReflect.Descriptor instead.
-func (*IssueCouponResponse) Descriptor() ([]byte, []int) {
-	return file_coupon_v1_coupon_proto_rawDescGZIP(), []int{7}
+// Deprecated: Use BadRequest_FieldViolation.ProtoReflect.Descriptor instead.
+func (*BadRequest_FieldViolation) Descriptor() ([]byte, []int) {
+	return file_coupon_v1_coupon_proto_rawDescGZIP(), []int{57, 0}
 }
 
-func (x *IssueCouponResponse) GetCoupon() *Coupon {
+func (x *BadRequest_FieldViolation) GetField() string {
 	if x != nil {
-		return x.Coupon
+		return x.Field
 	}
-	return nil
+	return ""
+}
+
+func (x *BadRequest_FieldViolation) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
 }
 
 var File_coupon_v1_coupon_proto protoreflect.FileDescriptor
 
 const file_coupon_v1_coupon_proto_rawDesc = "" +
 	"\n" +
-	"\x16coupon/v1/coupon.proto\x12\tcoupon.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\xb2\x01\n" +
+	"\x16coupon/v1/coupon.proto\x12\tcoupon.v1\x1a\x1egoogle/protobuf/duration.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\x8d\x05\n" +
 	"\bCampaign\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\x03R\x02id\x12+\n" +
 	"\x11available_coupons\x18\x02 \x01(\x05R\x10availableCoupons\x129\n" +
 	"\n" +
 	"start_date\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\tstartDate\x12.\n" +
-	"\x13issued_coupon_codes\x18\x04 \x03(\tR\x11issuedCouponCodes\"=\n" +
+	"\x13issued_coupon_codes\x18\x04 \x03(\tR\x11issuedCouponCodes\x126\n" +
+	"\vcode_format\x18\x05 \x01(\v2\x15.coupon.v1.CodeFormatR\n" +
+	"codeFormat\x12%\n" +
+	"\x0ediscount_value\x18\x06 \x01(\x03R\rdiscountValue\x12\x16\n" +
+	"\x06budget\x18\a \x01(\x03R\x06budget\x12!\n" +
+	"\fissued_value\x18\b \x01(\x03R\vissuedValue\x12$\n" +
+	"\x0eper_user_limit\x18\t \x01(\x05R\fperUserLimit\x129\n" +
+	"\n" +
+	"deleted_at\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\tdeletedAt\x12\"\n" +
+	"\rmax_issue_rps\x18\v \x01(\x01R\vmaxIssueRps\x12?\n" +
+	"\x0eissuance_order\x18\f \x01(\x0e2\x18.coupon.v1.IssuanceOrderR\rissuanceOrder\x12!\n" +
+	"\fshuffle_seed\x18\r \x01(\x03R\vshuffleSeed\x12\x12\n" +
+	"\x04slug\x18\x0e \x01(\tR\x04slug\x12B\n" +
+	"\x0fissuance_window\x18\x0f \x01(\v2\x19.coupon.v1.IssuanceWindowR\x0eissuanceWindow\"{\n" +
+	"\x0eIssuanceWindow\x12\x1d\n" +
+	"\n" +
+	"start_time\x18\x01 \x01(\tR\tstartTime\x12\x19\n" +
+	"\bend_time\x18\x02 \x01(\tR\aendTime\x12\x1b\n" +
+	"\ttime_zone\x18\x03 \x01(\tR\btimeZone\x12\x12\n" +
+	"\x04days\x18\x04 \x03(\x05R\x04days\"y\n" +
+	"\n" +
+	"CodeFormat\x12\x1a\n" +
+	"\balphabet\x18\x01 \x01(\tR\balphabet\x12\x16\n" +
+	"\x06length\x18\x02 \x01(\x05R\x06length\x12\x16\n" +
+	"\x06prefix\x18\x03 \x01(\tR\x06prefix\x12\x1f\n" +
+	"\vrouting_tag\x18\x04 \x01(\bR\n" +
+	"routingTag\"\xbd\x01\n" +
 	"\x06Coupon\x12\x12\n" +
 	"\x04code\x18\x01 \x01(\tR\x04code\x12\x1f\n" +
 	"\vcampaign_id\x18\x02 \x01(\x03R\n" +
-	"campaignId\"\x7f\n" +
+	"campaignId\x129\n" +
+	"\n" +
+	"expires_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\x12/\n" +
+	"\x06status\x18\x04 \x01(\x0e2\x17.coupon.v1.CouponStatusR\x06status\x12\x12\n" +
+	"\x04pool\x18\x05 \x01(\tR\x04pool\"\xa4\x06\n" +
 	"\x15CreateCampaignRequest\x12+\n" +
 	"\x11available_coupons\x18\x01 \x01(\x05R\x10availableCoupons\x129\n" +
 	"\n" +
-	"start_date\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\tstartDate\"I\n" +
+	"start_date\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\tstartDate\x12\x17\n" +
+	"\adry_run\x18\x03 \x01(\bR\x06dryRun\x12\x1f\n" +
+	"\vsample_size\x18\x04 \x01(\x05R\n" +
+	"sampleSize\x126\n" +
+	"\vcode_format\x18\x05 \x01(\v2\x15.coupon.v1.CodeFormatR\n" +
+	"codeFormat\x12%\n" +
+	"\x0ediscount_value\x18\x06 \x01(\x03R\rdiscountValue\x12\x16\n" +
+	"\x06budget\x18\a \x01(\x03R\x06budget\x12]\n" +
+	"\x0fcoupon_metadata\x18\b \x03(\v24.coupon.v1.CreateCampaignRequest.CouponMetadataEntryR\x0ecouponMetadata\x12$\n" +
+	"\x0eper_user_limit\x18\t \x01(\x05R\fperUserLimit\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\n" +
+	" \x01(\tR\trequestId\x12\"\n" +
+	"\rmax_issue_rps\x18\v \x01(\x01R\vmaxIssueRps\x12+\n" +
+	"\x05pools\x18\f \x03(\v2\x15.coupon.v1.CouponPoolR\x05pools\x12?\n" +
+	"\x0eissuance_order\x18\r \x01(\x0e2\x18.coupon.v1.IssuanceOrderR\rissuanceOrder\x12!\n" +
+	"\fshuffle_seed\x18\x0e \x01(\x03R\vshuffleSeed\x12\x12\n" +
+	"\x04slug\x18\x0f \x01(\tR\x04slug\x12B\n" +
+	"\x0fissuance_window\x18\x10 \x01(\v2\x19.coupon.v1.IssuanceWindowR\x0eissuanceWindow\x1aA\n" +
+	"\x13CouponMetadataEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\x8d\x01\n" +
+	"\n" +
+	"CouponPool\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x14\n" +
+	"\x05count\x18\x02 \x01(\x05R\x05count\x12\x1a\n" +
+	"\bpriority\x18\x03 \x01(\x05R\bpriority\x129\n" +
+	"\n" +
+	"expires_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\"\x88\x01\n" +
 	"\x16CreateCampaignResponse\x12/\n" +
-	"\bcampaign\x18\x01 \x01(\v2\x13.coupon.v1.CampaignR\bcampaign\"5\n" +
-	"\x12GetCampaignRequest\x12\x1f\n" +
-	"\vcampaign_id\x18\x01 \x01(\x03R\n" +
-	"campaignId\"F\n" +
+	"\bcampaign\x18\x01 \x01(\v2\x13.coupon.v1.CampaignR\bcampaign\x12!\n" +
+	"\fsample_codes\x18\x02 \x03(\tR\vsampleCodes\x12\x1a\n" +
+	"\breplayed\x18\x03 \x01(\bR\breplayed\"\xb5\x01\n" +
+	"\x16CreateCampaignProgress\x12\x1c\n" +
+	"\tgenerated\x18\x01 \x01(\x05R\tgenerated\x12\x1a\n" +
+	"\binserted\x18\x02 \x01(\x05R\binserted\x12\x14\n" +
+	"\x05total\x18\x03 \x01(\x05R\x05total\x12/\n" +
+	"\bcampaign\x18\x04 \x01(\v2\x13.coupon.v1.CampaignR\bcampaign\x12\x1a\n" +
+	"\breplayed\x18\x05 \x01(\bR\breplayed\"]\n" +
+	"\x1bBatchCreateCampaignsRequest\x12>\n" +
+	"\tcampaigns\x18\x01 \x03(\v2 .coupon.v1.CreateCampaignRequestR\tcampaigns\"A\n" +
+	"\x1cBatchCreateCampaignsResponse\x12!\n" +
+	"\fcampaign_ids\x18\x01 \x03(\x03R\vcampaignIds\"\xa3\x01\n" +
+	"\x12GetCampaignRequest\x12!\n" +
+	"\vcampaign_id\x18\x01 \x01(\x03H\x00R\n" +
+	"campaignId\x12\x14\n" +
+	"\x04slug\x18\x03 \x01(\tH\x00R\x04slug\x12'\n" +
+	"\x0finclude_deleted\x18\x02 \x01(\bR\x0eincludeDeleted\x12\x1f\n" +
+	"\vsample_size\x18\x04 \x01(\x05R\n" +
+	"sampleSizeB\n" +
+	"\n" +
+	"\bcampaign\"v\n" +
 	"\x13GetCampaignResponse\x12/\n" +
-	"\bcampaign\x18\x01 \x01(\v2\x13.coupon.v1.CampaignR\bcampaign\"5\n" +
-	"\x12IssueCouponRequest\x12\x1f\n" +
-	"\vcampaign_id\x18\x01 \x01(\x03R\n" +
-	"campaignId\"@\n" +
+	"\bcampaign\x18\x01 \x01(\v2\x13.coupon.v1.CampaignR\bcampaign\x12.\n" +
+	"\x05state\x18\x02 \x01(\x0e2\x18.coupon.v1.CampaignStateR\x05state\"\xd6\x01\n" +
+	"\x12IssueCouponRequest\x12!\n" +
+	"\vcampaign_id\x18\x01 \x01(\x03H\x00R\n" +
+	"campaignId\x12\x14\n" +
+	"\x04slug\x18\x05 \x01(\tH\x00R\x04slug\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12'\n" +
+	"\x0fidempotency_key\x18\x03 \x01(\tR\x0eidempotencyKey\x12\x12\n" +
+	"\x04pool\x18\x04 \x01(\tR\x04pool\x12%\n" +
+	"\x0ereport_outcome\x18\x06 \x01(\bR\rreportOutcomeB\n" +
+	"\n" +
+	"\bcampaign\"\x8a\x02\n" +
 	"\x13IssueCouponResponse\x12)\n" +
-	"\x06coupon\x18\x01 \x01(\v2\x11.coupon.v1.CouponR\x06coupon2\x82\x02\n" +
+	"\x06coupon\x18\x01 \x01(\v2\x11.coupon.v1.CouponR\x06coupon\x12\x1a\n" +
+	"\breplayed\x18\x02 \x01(\bR\breplayed\x121\n" +
+	"\aoutcome\x18\x03 \x01(\x0e2\x17.coupon.v1.IssueOutcomeR\aoutcome\x121\n" +
+	"\bsold_out\x18\x04 \x01(\v2\x16.coupon.v1.SoldOutInfoR\asoldOut\x12F\n" +
+	"\x0fissuance_window\x18\x05 \x01(\v2\x1d.coupon.v1.IssuanceWindowInfoR\x0eissuanceWindow\"i\n" +
+	"\x11IssueBatchRequest\x12\x1f\n" +
+	"\vcampaign_id\x18\x01 \x01(\x03R\n" +
+	"campaignId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x1a\n" +
+	"\bquantity\x18\x03 \x01(\x05R\bquantity\"\x8f\x01\n" +
+	"\x12IssueBatchResponse\x12+\n" +
+	"\acoupons\x18\x01 \x03(\v2\x11.coupon.v1.CouponR\acoupons\x12'\n" +
+	"\x0frequested_count\x18\x02 \x01(\x05R\x0erequestedCount\x12#\n" +
+	"\rgranted_count\x18\x03 \x01(\x05R\fgrantedCount\"\xe2\x02\n" +
+	"\rCampaignStats\x12\x1f\n" +
+	"\vcampaign_id\x18\x01 \x01(\x03R\n" +
+	"campaignId\x12\x1f\n" +
+	"\vtotal_count\x18\x02 \x01(\x05R\n" +
+	"totalCount\x12'\n" +
+	"\x0favailable_count\x18\x03 \x01(\x05R\x0eavailableCount\x12!\n" +
+	"\fissued_count\x18\x04 \x01(\x05R\vissuedCount\x122\n" +
+	"\x15recently_issued_count\x18\x05 \x01(\x05R\x13recentlyIssuedCount\x12>\n" +
+	"\rrecent_window\x18\x06 \x01(\v2\x19.google.protobuf.DurationR\frecentWindow\x12*\n" +
+	"\x05pools\x18\a \x03(\v2\x14.coupon.v1.PoolStatsR\x05pools\x12#\n" +
+	"\rrevoked_count\x18\b \x01(\x05R\frevokedCount\"\xb1\x01\n" +
+	"\tPoolStats\x12\x12\n" +
+	"\x04pool\x18\x01 \x01(\tR\x04pool\x12\x1f\n" +
+	"\vtotal_count\x18\x02 \x01(\x05R\n" +
+	"totalCount\x12'\n" +
+	"\x0favailable_count\x18\x03 \x01(\x05R\x0eavailableCount\x12!\n" +
+	"\fissued_count\x18\x04 \x01(\x05R\vissuedCount\x12#\n" +
+	"\rrevoked_count\x18\x05 \x01(\x05R\frevokedCount\"z\n" +
+	"\x17GetCampaignStatsRequest\x12\x1f\n" +
+	"\vcampaign_id\x18\x01 \x01(\x03R\n" +
+	"campaignId\x12>\n" +
+	"\rrecent_window\x18\x02 \x01(\v2\x19.google.protobuf.DurationR\frecentWindow\"J\n" +
+	"\x18GetCampaignStatsResponse\x12.\n" +
+	"\x05stats\x18\x01 \x01(\v2\x18.coupon.v1.CampaignStatsR\x05stats\"6\n" +
+	"\x13GetRemainingRequest\x12\x1f\n" +
+	"\vcampaign_id\x18\x01 \x01(\x03R\n" +
+	"campaignId\"?\n" +
+	"\x14GetRemainingResponse\x12'\n" +
+	"\x0favailable_count\x18\x01 \x01(\x05R\x0eavailableCount\"\x8c\x01\n" +
+	"\x18RegenerateCouponsRequest\x12\x1f\n" +
+	"\vcampaign_id\x18\x01 \x01(\x03R\n" +
+	"campaignId\x126\n" +
+	"\vcode_format\x18\x02 \x01(\v2\x15.coupon.v1.CodeFormatR\n" +
+	"codeFormat\x12\x17\n" +
+	"\adry_run\x18\x03 \x01(\bR\x06dryRun\"\x9c\x01\n" +
+	"\x19RegenerateCouponsResponse\x12+\n" +
+	"\x11regenerated_count\x18\x01 \x01(\x05R\x10regeneratedCount\x12/\n" +
+	"\bcampaign\x18\x02 \x01(\v2\x13.coupon.v1.CampaignR\bcampaign\x12!\n" +
+	"\fsample_codes\x18\x03 \x03(\tR\vsampleCodes\"I\n" +
+	"\vPageRequest\x12\x1b\n" +
+	"\tpage_size\x18\x01 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x02 \x01(\tR\tpageToken\"6\n" +
+	"\fPageResponse\x12&\n" +
+	"\x0fnext_page_token\x18\x01 \x01(\tR\rnextPageToken\"{\n" +
+	"\x14SearchCouponsRequest\x12\x1f\n" +
+	"\vcampaign_id\x18\x01 \x01(\x03R\n" +
+	"campaignId\x12\x16\n" +
+	"\x06prefix\x18\x02 \x01(\tR\x06prefix\x12*\n" +
+	"\x04page\x18\x05 \x01(\v2\x16.coupon.v1.PageRequestR\x04page\"\xad\x03\n" +
+	"\x12CouponSearchResult\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\x127\n" +
+	"\tissued_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\bissuedAt\x12G\n" +
+	"\bmetadata\x18\x04 \x03(\v2+.coupon.v1.CouponSearchResult.MetadataEntryR\bmetadata\x12\x1f\n" +
+	"\vcampaign_id\x18\x05 \x01(\x03R\n" +
+	"campaignId\x129\n" +
+	"\n" +
+	"expires_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\x12<\n" +
+	"\rcoupon_status\x18\a \x01(\x0e2\x17.coupon.v1.CouponStatusR\fcouponStatus\x12\x12\n" +
+	"\x04pool\x18\b \x01(\tR\x04pool\x1a;\n" +
+	"\rMetadataEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"}\n" +
+	"\x15SearchCouponsResponse\x127\n" +
+	"\acoupons\x18\x01 \x03(\v2\x1d.coupon.v1.CouponSearchResultR\acoupons\x12+\n" +
+	"\x04page\x18\x03 \x01(\v2\x17.coupon.v1.PageResponseR\x04page\"k\n" +
+	"\x14ListCampaignsRequest\x12*\n" +
+	"\x04page\x18\x01 \x01(\v2\x16.coupon.v1.PageRequestR\x04page\x12'\n" +
+	"\x0finclude_deleted\x18\x02 \x01(\bR\x0eincludeDeleted\"w\n" +
+	"\x15ListCampaignsResponse\x121\n" +
+	"\tcampaigns\x18\x01 \x03(\v2\x13.coupon.v1.CampaignR\tcampaigns\x12+\n" +
+	"\x04page\x18\x02 \x01(\v2\x17.coupon.v1.PageResponseR\x04page\"\x81\x02\n" +
+	"\x12ListCouponsRequest\x12\x1f\n" +
+	"\vcampaign_id\x18\x01 \x01(\x03R\n" +
+	"campaignId\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\x12*\n" +
+	"\x04page\x18\x03 \x01(\v2\x16.coupon.v1.PageRequestR\x04page\x12!\n" +
+	"\fmetadata_key\x18\x04 \x01(\tR\vmetadataKey\x12%\n" +
+	"\x0emetadata_value\x18\x05 \x01(\tR\rmetadataValue\x12<\n" +
+	"\rcoupon_status\x18\x06 \x01(\x0e2\x17.coupon.v1.CouponStatusR\fcouponStatus\"G\n" +
+	"\x10GetCouponRequest\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\x12\x1f\n" +
+	"\vcampaign_id\x18\x02 \x01(\x03R\n" +
+	"campaignId\"J\n" +
+	"\x11GetCouponResponse\x125\n" +
+	"\x06coupon\x18\x01 \x01(\v2\x1d.coupon.v1.CouponSearchResultR\x06coupon\"L\n" +
+	"\x15ValidateCouponRequest\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\x12\x1f\n" +
+	"\vcampaign_id\x18\x02 \x01(\x03R\n" +
+	"campaignId\"\xa6\x01\n" +
+	"\x16ValidateCouponResponse\x125\n" +
+	"\x06coupon\x18\x01 \x01(\v2\x1d.coupon.v1.CouponSearchResultR\x06coupon\x125\n" +
+	"\bcampaign\x18\x02 \x01(\v2\x19.coupon.v1.CouponCampaignR\bcampaign\x12\x1e\n" +
+	"\n" +
+	"redeemable\x18\x03 \x01(\bR\n" +
+	"redeemable\"\x9c\x01\n" +
+	"\x0eCouponCampaign\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12%\n" +
+	"\x0ediscount_value\x18\x02 \x01(\x03R\rdiscountValue\x129\n" +
+	"\n" +
+	"start_date\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\tstartDate\x12\x18\n" +
+	"\astarted\x18\x04 \x01(\bR\astarted\"N\n" +
+	"\x17GetCouponPayloadRequest\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\x12\x1f\n" +
+	"\vcampaign_id\x18\x02 \x01(\x03R\n" +
+	"campaignId\"\xa0\x01\n" +
+	"\x18GetCouponPayloadResponse\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\x12\x1f\n" +
+	"\vcampaign_id\x18\x02 \x01(\x03R\n" +
+	"campaignId\x129\n" +
+	"\n" +
+	"expires_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\x12\x14\n" +
+	"\x05token\x18\x04 \x01(\tR\x05token\"{\n" +
+	"\x13ListCouponsResponse\x127\n" +
+	"\acoupons\x18\x01 \x03(\v2\x1d.coupon.v1.CouponSearchResultR\acoupons\x12+\n" +
+	"\x04page\x18\x02 \x01(\v2\x17.coupon.v1.PageResponseR\x04page\"N\n" +
+	"\x15DeleteCampaignRequest\x12\x1f\n" +
+	"\vcampaign_id\x18\x01 \x01(\x03R\n" +
+	"campaignId\x12\x14\n" +
+	"\x05force\x18\x02 \x01(\bR\x05force\"S\n" +
+	"\x16DeleteCampaignResponse\x129\n" +
+	"\n" +
+	"deleted_at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\tdeletedAt\"9\n" +
+	"\x16RestoreCampaignRequest\x12\x1f\n" +
+	"\vcampaign_id\x18\x01 \x01(\x03R\n" +
+	"campaignId\"J\n" +
+	"\x17RestoreCampaignResponse\x12/\n" +
+	"\bcampaign\x18\x01 \x01(\v2\x13.coupon.v1.CampaignR\bcampaign\"\x8a\x01\n" +
+	"\x16TransferCouponsRequest\x12,\n" +
+	"\x12source_campaign_id\x18\x01 \x01(\x03R\x10sourceCampaignId\x12,\n" +
+	"\x12target_campaign_id\x18\x02 \x01(\x03R\x10targetCampaignId\x12\x14\n" +
+	"\x05codes\x18\x03 \x03(\tR\x05codes\"F\n" +
+	"\x17TransferCouponsResponse\x12+\n" +
+	"\x11transferred_count\x18\x01 \x01(\x05R\x10transferredCount\"?\n" +
+	"\x1cRevokeCampaignCouponsRequest\x12\x1f\n" +
+	"\vcampaign_id\x18\x01 \x01(\x03R\n" +
+	"campaignId\"D\n" +
+	"\x1dRevokeCampaignCouponsResponse\x12#\n" +
+	"\rrevoked_count\x18\x01 \x01(\x05R\frevokedCount\"\\\n" +
+	"\x15UpdateCampaignRequest\x12\x1f\n" +
+	"\vcampaign_id\x18\x01 \x01(\x03R\n" +
+	"campaignId\x12\"\n" +
+	"\rmax_issue_rps\x18\x02 \x01(\x01R\vmaxIssueRps\"I\n" +
+	"\x16UpdateCampaignResponse\x12/\n" +
+	"\bcampaign\x18\x01 \x01(\v2\x13.coupon.v1.CampaignR\bcampaign\"o\n" +
+	"\x14DrainCampaignRequest\x12\x1f\n" +
+	"\vcampaign_id\x18\x01 \x01(\x03R\n" +
+	"campaignId\x12\x1c\n" +
+	"\trecipient\x18\x02 \x01(\tR\trecipient\x12\x18\n" +
+	"\aconfirm\x18\x03 \x01(\bR\aconfirm\"P\n" +
+	"\x15DrainCampaignResponse\x12\x14\n" +
+	"\x05codes\x18\x01 \x03(\tR\x05codes\x12!\n" +
+	"\fissued_count\x18\x02 \x01(\x05R\vissuedCount\"T\n" +
+	"\x1bPeekAvailableCouponsRequest\x12\x1f\n" +
+	"\vcampaign_id\x18\x01 \x01(\x03R\n" +
+	"campaignId\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\"4\n" +
+	"\x1cPeekAvailableCouponsResponse\x12\x14\n" +
+	"\x05codes\x18\x01 \x03(\tR\x05codes\"=\n" +
+	"\x1aVerifyCampaignCodesRequest\x12\x1f\n" +
+	"\vcampaign_id\x18\x01 \x01(\x03R\n" +
+	"campaignId\"\xee\x01\n" +
+	"\x1bVerifyCampaignCodesResponse\x12#\n" +
+	"\rchecked_count\x18\x01 \x01(\x03R\fcheckedCount\x12'\n" +
+	"\x0funindexed_count\x18\x02 \x01(\x03R\x0eunindexedCount\x12)\n" +
+	"\x10mismatched_count\x18\x03 \x01(\x03R\x0fmismatchedCount\x12)\n" +
+	"\x10mismatched_codes\x18\x04 \x03(\tR\x0fmismatchedCodes\x12+\n" +
+	"\x11duplicate_indexes\x18\x05 \x03(\x03R\x10duplicateIndexes\"~\n" +
+	"\vSoldOutInfo\x12\x1f\n" +
+	"\vcampaign_id\x18\x01 \x01(\x03R\n" +
+	"campaignId\x12\x1c\n" +
+	"\tremaining\x18\x02 \x01(\x05R\tremaining\x120\n" +
+	"\x06reason\x18\x03 \x01(\x0e2\x18.coupon.v1.SoldOutReasonR\x06reason\"\xa7\x01\n" +
+	"\n" +
+	"BadRequest\x12O\n" +
+	"\x10field_violations\x18\x01 \x03(\v2$.coupon.v1.BadRequest.FieldViolationR\x0ffieldViolations\x1aH\n" +
+	"\x0eFieldViolation\x12\x14\n" +
+	"\x05field\x18\x01 \x01(\tR\x05field\x12 \n" +
+	"\vdescription\x18\x02 \x01(\tR\vdescription\"}\n" +
+	"\x12IssuanceWindowInfo\x12\x1f\n" +
+	"\vcampaign_id\x18\x01 \x01(\x03R\n" +
+	"campaignId\x12F\n" +
+	"\x11next_window_start\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x0fnextWindowStart\"G\n" +
+	"\tRetryInfo\x12:\n" +
+	"\vretry_delay\x18\x01 \x01(\v2\x19.google.protobuf.DurationR\n" +
+	"retryDelay\"#\n" +
+	"\tErrorInfo\x12\x16\n" +
+	"\x06reason\x18\x01 \x01(\tR\x06reason*\xa4\x01\n" +
+	"\rIssuanceOrder\x12\x1e\n" +
+	"\x1aISSUANCE_ORDER_UNSPECIFIED\x10\x00\x12\x1a\n" +
+	"\x16ISSUANCE_ORDER_CREATED\x10\x01\x12\x1b\n" +
+	"\x17ISSUANCE_ORDER_SHUFFLED\x10\x02\x12\x1d\n" +
+	"\x19ISSUANCE_ORDER_EXPIRES_AT\x10\x03\x12\x1b\n" +
+	"\x17ISSUANCE_ORDER_PRIORITY\x10\x04*\xd2\x01\n" +
+	"\fCouponStatus\x12\x1d\n" +
+	"\x19COUPON_STATUS_UNSPECIFIED\x10\x00\x12\x1b\n" +
+	"\x17COUPON_STATUS_AVAILABLE\x10\x01\x12\x18\n" +
+	"\x14COUPON_STATUS_ISSUED\x10\x02\x12\x1a\n" +
+	"\x16COUPON_STATUS_RESERVED\x10\x03\x12\x1a\n" +
+	"\x16COUPON_STATUS_REDEEMED\x10\x04\x12\x19\n" +
+	"\x15COUPON_STATUS_EXPIRED\x10\x05\x12\x19\n" +
+	"\x15COUPON_STATUS_REVOKED\x10\x06*\x81\x01\n" +
+	"\rCampaignState\x12\x1e\n" +
+	"\x1aCAMPAIGN_STATE_UNSPECIFIED\x10\x00\x12\x1b\n" +
+	"\x17CAMPAIGN_STATE_UPCOMING\x10\x01\x12\x19\n" +
+	"\x15CAMPAIGN_STATE_ACTIVE\x10\x02\x12\x18\n" +
+	"\x14CAMPAIGN_STATE_ENDED\x10\x03*\x82\x01\n" +
+	"\fIssueOutcome\x12\x1d\n" +
+	"\x19ISSUE_OUTCOME_UNSPECIFIED\x10\x00\x12\x18\n" +
+	"\x14ISSUE_OUTCOME_ISSUED\x10\x01\x12\x1a\n" +
+	"\x16ISSUE_OUTCOME_SOLD_OUT\x10\x02\x12\x1d\n" +
+	"\x19ISSUE_OUTCOME_NOT_STARTED\x10\x03*v\n" +
+	"\rSoldOutReason\x12\x1f\n" +
+	"\x1bSOLD_OUT_REASON_UNSPECIFIED\x10\x00\x12\x1e\n" +
+	"\x1aSOLD_OUT_REASON_NO_COUPONS\x10\x01\x12$\n" +
+	" SOLD_OUT_REASON_BUDGET_EXHAUSTED\x10\x022\x86\x10\n" +
 	"\rCouponService\x12U\n" +
-	"\x0eCreateCampaign\x12 .coupon.v1.CreateCampaignRequest\x1a!.coupon.v1.CreateCampaignResponse\x12L\n" +
+	"\x0eCreateCampaign\x12 .coupon.v1.CreateCampaignRequest\x1a!.coupon.v1.CreateCampaignResponse\x12g\n" +
+	"\x14BatchCreateCampaigns\x12&.coupon.v1.BatchCreateCampaignsRequest\x1a'.coupon.v1.BatchCreateCampaignsResponse\x12]\n" +
+	"\x14CreateCampaignStream\x12 .coupon.v1.CreateCampaignRequest\x1a!.coupon.v1.CreateCampaignProgress0\x01\x12L\n" +
 	"\vGetCampaign\x12\x1d.coupon.v1.GetCampaignRequest\x1a\x1e.coupon.v1.GetCampaignResponse\x12L\n" +
-	"\vIssueCoupon\x12\x1d.coupon.v1.IssueCouponRequest\x1a\x1e.coupon.v1.IssueCouponResponseB\x95\x01\n" +
+	"\vIssueCoupon\x12\x1d.coupon.v1.IssueCouponRequest\x1a\x1e.coupon.v1.IssueCouponResponse\x12I\n" +
+	"\n" +
+	"IssueBatch\x12\x1c.coupon.v1.IssueBatchRequest\x1a\x1d.coupon.v1.IssueBatchResponse\x12[\n" +
+	"\x10GetCampaignStats\x12\".coupon.v1.GetCampaignStatsRequest\x1a#.coupon.v1.GetCampaignStatsResponse\x12O\n" +
+	"\fGetRemaining\x12\x1e.coupon.v1.GetRemainingRequest\x1a\x1f.coupon.v1.GetRemainingResponse\x12^\n" +
+	"\x11RegenerateCoupons\x12#.coupon.v1.RegenerateCouponsRequest\x1a$.coupon.v1.RegenerateCouponsResponse\x12R\n" +
+	"\rSearchCoupons\x12\x1f.coupon.v1.SearchCouponsRequest\x1a .coupon.v1.SearchCouponsResponse\x12F\n" +
+	"\tGetCoupon\x12\x1b.coupon.v1.GetCouponRequest\x1a\x1c.coupon.v1.GetCouponResponse\x12U\n" +
+	"\x0eValidateCoupon\x12 .coupon.v1.ValidateCouponRequest\x1a!.coupon.v1.ValidateCouponResponse\x12[\n" +
+	"\x10GetCouponPayload\x12\".coupon.v1.GetCouponPayloadRequest\x1a#.coupon.v1.GetCouponPayloadResponse\x12R\n" +
+	"\rListCampaigns\x12\x1f.coupon.v1.ListCampaignsRequest\x1a .coupon.v1.ListCampaignsResponse\x12L\n" +
+	"\vListCoupons\x12\x1d.coupon.v1.ListCouponsRequest\x1a\x1e.coupon.v1.ListCouponsResponse\x12U\n" +
+	"\x0eDeleteCampaign\x12 .coupon.v1.DeleteCampaignRequest\x1a!.coupon.v1.DeleteCampaignResponse\x12U\n" +
+	"\x0eUpdateCampaign\x12 .coupon.v1.UpdateCampaignRequest\x1a!.coupon.v1.UpdateCampaignResponse\x12X\n" +
+	"\x0fRestoreCampaign\x12!.coupon.v1.RestoreCampaignRequest\x1a\".coupon.v1.RestoreCampaignResponse\x12X\n" +
+	"\x0fTransferCoupons\x12!.coupon.v1.TransferCouponsRequest\x1a\".coupon.v1.TransferCouponsResponse\x12j\n" +
+	"\x15RevokeCampaignCoupons\x12'.coupon.v1.RevokeCampaignCouponsRequest\x1a(.coupon.v1.RevokeCampaignCouponsResponse\x12R\n" +
+	"\rDrainCampaign\x12\x1f.coupon.v1.DrainCampaignRequest\x1a .coupon.v1.DrainCampaignResponse\x12g\n" +
+	"\x14PeekAvailableCoupons\x12&.coupon.v1.PeekAvailableCouponsRequest\x1a'.coupon.v1.PeekAvailableCouponsResponse\x12d\n" +
+	"\x13VerifyCampaignCodes\x12%.coupon.v1.VerifyCampaignCodesRequest\x1a&.coupon.v1.VerifyCampaignCodesResponseB\x95\x01\n" +
 	"\rcom.coupon.v1B\vCouponProtoP\x01Z2github.com/kkkkikiki/coupon/gen/coupon/v1;couponv1\xa2\x02\x03CXX\xaa\x02\tCoupon.V1\xca\x02\tCoupon\\V1\xe2\x02\x15Coupon\\V1\\GPBMetadata\xea\x02\n" +
 	"Coupon::V1b\x06proto3"
 
@@ -472,35 +4613,189 @@ func file_coupon_v1_coupon_proto_rawDescGZIP() []byte {
 	return file_coupon_v1_coupon_proto_rawDescData
 }
 
-var file_coupon_v1_coupon_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
+var file_coupon_v1_coupon_proto_enumTypes = make([]protoimpl.EnumInfo, 5)
+var file_coupon_v1_coupon_proto_msgTypes = make([]protoimpl.MessageInfo, 64)
 var file_coupon_v1_coupon_proto_goTypes = []any{
-	(*Campaign)(nil),               // 0: coupon.v1.Campaign
-	(*Coupon)(nil),                 // 1: coupon.v1.Coupon
-	(*CreateCampaignRequest)(nil),  // 2: coupon.v1.CreateCampaignRequest
-	(*CreateCampaignResponse)(nil), // 3: coupon.v1.CreateCampaignResponse
-	(*GetCampaignRequest)(nil),     // 4: coupon.v1.GetCampaignRequest
-	(*GetCampaignResponse)(nil),    // 5: coupon.v1.GetCampaignResponse
-	(*IssueCouponRequest)(nil),     // 6: coupon.v1.IssueCouponRequest
-	(*IssueCouponResponse)(nil),    // 7: coupon.v1.IssueCouponResponse
-	(*timestamppb.Timestamp)(nil),  // 8: google.protobuf.Timestamp
+	(IssuanceOrder)(0),                    // 0: coupon.v1.IssuanceOrder
+	(CouponStatus)(0),                     // 1: coupon.v1.CouponStatus
+	(CampaignState)(0),                    // 2: coupon.v1.CampaignState
+	(IssueOutcome)(0),                     // 3: coupon.v1.IssueOutcome
+	(SoldOutReason)(0),                    // 4: coupon.v1.SoldOutReason
+	(*Campaign)(nil),                      // 5: coupon.v1.Campaign
+	(*IssuanceWindow)(nil),                // 6: coupon.v1.IssuanceWindow
+	(*CodeFormat)(nil),                    // 7: coupon.v1.CodeFormat
+	(*Coupon)(nil),                        // 8: coupon.v1.Coupon
+	(*CreateCampaignRequest)(nil),         // 9: coupon.v1.CreateCampaignRequest
+	(*CouponPool)(nil),                    // 10: coupon.v1.CouponPool
+	(*CreateCampaignResponse)(nil),        // 11: coupon.v1.CreateCampaignResponse
+	(*CreateCampaignProgress)(nil),        // 12: coupon.v1.CreateCampaignProgress
+	(*BatchCreateCampaignsRequest)(nil),   // 13: coupon.v1.BatchCreateCampaignsRequest
+	(*BatchCreateCampaignsResponse)(nil),  // 14: coupon.v1.BatchCreateCampaignsResponse
+	(*GetCampaignRequest)(nil),            // 15: coupon.v1.GetCampaignRequest
+	(*GetCampaignResponse)(nil),           // 16: coupon.v1.GetCampaignResponse
+	(*IssueCouponRequest)(nil),            // 17: coupon.v1.IssueCouponRequest
+	(*IssueCouponResponse)(nil),           // 18: coupon.v1.IssueCouponResponse
+	(*IssueBatchRequest)(nil),             // 19: coupon.v1.IssueBatchRequest
+	(*IssueBatchResponse)(nil),            // 20: coupon.v1.IssueBatchResponse
+	(*CampaignStats)(nil),                 // 21: coupon.v1.CampaignStats
+	(*PoolStats)(nil),                     // 22: coupon.v1.PoolStats
+	(*GetCampaignStatsRequest)(nil),       // 23: coupon.v1.GetCampaignStatsRequest
+	(*GetCampaignStatsResponse)(nil),      // 24: coupon.v1.GetCampaignStatsResponse
+	(*GetRemainingRequest)(nil),           // 25: coupon.v1.GetRemainingRequest
+	(*GetRemainingResponse)(nil),          // 26: coupon.v1.GetRemainingResponse
+	(*RegenerateCouponsRequest)(nil),      // 27: coupon.v1.RegenerateCouponsRequest
+	(*RegenerateCouponsResponse)(nil),     // 28: coupon.v1.RegenerateCouponsResponse
+	(*PageRequest)(nil),                   // 29: coupon.v1.PageRequest
+	(*PageResponse)(nil),                  // 30: coupon.v1.PageResponse
+	(*SearchCouponsRequest)(nil),          // 31: coupon.v1.SearchCouponsRequest
+	(*CouponSearchResult)(nil),            // 32: coupon.v1.CouponSearchResult
+	(*SearchCouponsResponse)(nil),         // 33: coupon.v1.SearchCouponsResponse
+	(*ListCampaignsRequest)(nil),          // 34: coupon.v1.ListCampaignsRequest
+	(*ListCampaignsResponse)(nil),         // 35: coupon.v1.ListCampaignsResponse
+	(*ListCouponsRequest)(nil),            // 36: coupon.v1.ListCouponsRequest
+	(*GetCouponRequest)(nil),              // 37: coupon.v1.GetCouponRequest
+	(*GetCouponResponse)(nil),             // 38: coupon.v1.GetCouponResponse
+	(*ValidateCouponRequest)(nil),         // 39: coupon.v1.ValidateCouponRequest
+	(*ValidateCouponResponse)(nil),        // 40: coupon.v1.ValidateCouponResponse
+	(*CouponCampaign)(nil),                // 41: coupon.v1.CouponCampaign
+	(*GetCouponPayloadRequest)(nil),       // 42: coupon.v1.GetCouponPayloadRequest
+	(*GetCouponPayloadResponse)(nil),      // 43: coupon.v1.GetCouponPayloadResponse
+	(*ListCouponsResponse)(nil),           // 44: coupon.v1.ListCouponsResponse
+	(*DeleteCampaignRequest)(nil),         // 45: coupon.v1.DeleteCampaignRequest
+	(*DeleteCampaignResponse)(nil),        // 46: coupon.v1.DeleteCampaignResponse
+	(*RestoreCampaignRequest)(nil),        // 47: coupon.v1.RestoreCampaignRequest
+	(*RestoreCampaignResponse)(nil),       // 48: coupon.v1.RestoreCampaignResponse
+	(*TransferCouponsRequest)(nil),        // 49: coupon.v1.TransferCouponsRequest
+	(*TransferCouponsResponse)(nil),       // 50: coupon.v1.TransferCouponsResponse
+	(*RevokeCampaignCouponsRequest)(nil),  // 51: coupon.v1.RevokeCampaignCouponsRequest
+	(*RevokeCampaignCouponsResponse)(nil), // 52: coupon.v1.RevokeCampaignCouponsResponse
+	(*UpdateCampaignRequest)(nil),         // 53: coupon.v1.UpdateCampaignRequest
+	(*UpdateCampaignResponse)(nil),        // 54: coupon.v1.UpdateCampaignResponse
+	(*DrainCampaignRequest)(nil),          // 55: coupon.v1.DrainCampaignRequest
+	(*DrainCampaignResponse)(nil),         // 56: coupon.v1.DrainCampaignResponse
+	(*PeekAvailableCouponsRequest)(nil),   // 57: coupon.v1.PeekAvailableCouponsRequest
+	(*PeekAvailableCouponsResponse)(nil),  // 58: coupon.v1.PeekAvailableCouponsResponse
+	(*VerifyCampaignCodesRequest)(nil),    // 59: coupon.v1.VerifyCampaignCodesRequest
+	(*VerifyCampaignCodesResponse)(nil),   // 60: coupon.v1.VerifyCampaignCodesResponse
+	(*SoldOutInfo)(nil),                   // 61: coupon.v1.SoldOutInfo
+	(*BadRequest)(nil),                    // 62: coupon.v1.BadRequest
+	(*IssuanceWindowInfo)(nil),            // 63: coupon.v1.IssuanceWindowInfo
+	(*RetryInfo)(nil),                     // 64: coupon.v1.RetryInfo
+	(*ErrorInfo)(nil),                     // 65: coupon.v1.ErrorInfo
+	nil,                                   // 66: coupon.v1.CreateCampaignRequest.CouponMetadataEntry
+	nil,                                   // 67: coupon.v1.CouponSearchResult.MetadataEntry
+	(*BadRequest_FieldViolation)(nil),     // 68: coupon.v1.BadRequest.FieldViolation
+	(*timestamppb.Timestamp)(nil),         // 69: google.protobuf.Timestamp
+	(*durationpb.Duration)(nil),           // 70: google.protobuf.Duration
 }
 var file_coupon_v1_coupon_proto_depIdxs = []int32{
-	8, // 0: coupon.v1.Campaign.start_date:type_name -> google.protobuf.Timestamp
-	8, // 1: coupon.v1.CreateCampaignRequest.start_date:type_name -> google.protobuf.Timestamp
-	0, // 2: coupon.v1.CreateCampaignResponse.campaign:type_name -> coupon.v1.Campaign
-	0, // 3: coupon.v1.GetCampaignResponse.campaign:type_name -> coupon.v1.Campaign
-	1, // 4: coupon.v1.IssueCouponResponse.coupon:type_name -> coupon.v1.Coupon
-	2, // 5: coupon.v1.CouponService.CreateCampaign:input_type -> coupon.v1.CreateCampaignRequest
-	4, // 6: coupon.v1.CouponService.GetCampaign:input_type -> coupon.v1.GetCampaignRequest
-	6, // 7: coupon.v1.CouponService.IssueCoupon:input_type -> coupon.v1.IssueCouponRequest
-	3, // 8: coupon.v1.CouponService.CreateCampaign:output_type -> coupon.v1.CreateCampaignResponse
-	5, // 9: coupon.v1.CouponService.GetCampaign:output_type -> coupon.v1.GetCampaignResponse
-	7, // 10: coupon.v1.CouponService.IssueCoupon:output_type -> coupon.v1.IssueCouponResponse
-	8, // [8:11] is the sub-list for method output_type
-	5, // [5:8] is the sub-list for method input_type
-	5, // [5:5] is the sub-list for extension type_name
-	5, // [5:5] is the sub-list for extension extendee
-	0, // [0:5] is the sub-list for field type_name
+	69, // 0: coupon.v1.Campaign.start_date:type_name -> google.protobuf.Timestamp
+	7,  // 1: coupon.v1.Campaign.code_format:type_name -> coupon.v1.CodeFormat
+	69, // 2: coupon.v1.Campaign.deleted_at:type_name -> google.protobuf.Timestamp
+	0,  // 3: coupon.v1.Campaign.issuance_order:type_name -> coupon.v1.IssuanceOrder
+	6,  // 4: coupon.v1.Campaign.issuance_window:type_name -> coupon.v1.IssuanceWindow
+	69, // 5: coupon.v1.Coupon.expires_at:type_name -> google.protobuf.Timestamp
+	1,  // 6: coupon.v1.Coupon.status:type_name -> coupon.v1.CouponStatus
+	69, // 7: coupon.v1.CreateCampaignRequest.start_date:type_name -> google.protobuf.Timestamp
+	7,  // 8: coupon.v1.CreateCampaignRequest.code_format:type_name -> coupon.v1.CodeFormat
+	66, // 9: coupon.v1.CreateCampaignRequest.coupon_metadata:type_name -> coupon.v1.CreateCampaignRequest.CouponMetadataEntry
+	10, // 10: coupon.v1.CreateCampaignRequest.pools:type_name -> coupon.v1.CouponPool
+	0,  // 11: coupon.v1.CreateCampaignRequest.issuance_order:type_name -> coupon.v1.IssuanceOrder
+	6,  // 12: coupon.v1.CreateCampaignRequest.issuance_window:type_name -> coupon.v1.IssuanceWindow
+	69, // 13: coupon.v1.CouponPool.expires_at:type_name -> google.protobuf.Timestamp
+	5,  // 14: coupon.v1.CreateCampaignResponse.campaign:type_name -> coupon.v1.Campaign
+	5,  // 15: coupon.v1.CreateCampaignProgress.campaign:type_name -> coupon.v1.Campaign
+	9,  // 16: coupon.v1.BatchCreateCampaignsRequest.campaigns:type_name -> coupon.v1.CreateCampaignRequest
+	5,  // 17: coupon.v1.GetCampaignResponse.campaign:type_name -> coupon.v1.Campaign
+	2,  // 18: coupon.v1.GetCampaignResponse.state:type_name -> coupon.v1.CampaignState
+	8,  // 19: coupon.v1.IssueCouponResponse.coupon:type_name -> coupon.v1.Coupon
+	3,  // 20: coupon.v1.IssueCouponResponse.outcome:type_name -> coupon.v1.IssueOutcome
+	61, // 21: coupon.v1.IssueCouponResponse.sold_out:type_name -> coupon.v1.SoldOutInfo
+	63, // 22: coupon.v1.IssueCouponResponse.issuance_window:type_name -> coupon.v1.IssuanceWindowInfo
+	8,  // 23: coupon.v1.IssueBatchResponse.coupons:type_name -> coupon.v1.Coupon
+	70, // 24: coupon.v1.CampaignStats.recent_window:type_name -> google.protobuf.Duration
+	22, // 25: coupon.v1.CampaignStats.pools:type_name -> coupon.v1.PoolStats
+	70, // 26: coupon.v1.GetCampaignStatsRequest.recent_window:type_name -> google.protobuf.Duration
+	21, // 27: coupon.v1.GetCampaignStatsResponse.stats:type_name -> coupon.v1.CampaignStats
+	7,  // 28: coupon.v1.RegenerateCouponsRequest.code_format:type_name -> coupon.v1.CodeFormat
+	5,  // 29: coupon.v1.RegenerateCouponsResponse.campaign:type_name -> coupon.v1.Campaign
+	29, // 30: coupon.v1.SearchCouponsRequest.page:type_name -> coupon.v1.PageRequest
+	69, // 31: coupon.v1.CouponSearchResult.issued_at:type_name -> google.protobuf.Timestamp
+	67, // 32: coupon.v1.CouponSearchResult.metadata:type_name -> coupon.v1.CouponSearchResult.MetadataEntry
+	69, // 33: coupon.v1.CouponSearchResult.expires_at:type_name -> google.protobuf.Timestamp
+	1,  // 34: coupon.v1.CouponSearchResult.coupon_status:type_name -> coupon.v1.CouponStatus
+	32, // 35: coupon.v1.SearchCouponsResponse.coupons:type_name -> coupon.v1.CouponSearchResult
+	30, // 36: coupon.v1.SearchCouponsResponse.page:type_name -> coupon.v1.PageResponse
+	29, // 37: coupon.v1.ListCampaignsRequest.page:type_name -> coupon.v1.PageRequest
+	5,  // 38: coupon.v1.ListCampaignsResponse.campaigns:type_name -> coupon.v1.Campaign
+	30, // 39: coupon.v1.ListCampaignsResponse.page:type_name -> coupon.v1.PageResponse
+	29, // 40: coupon.v1.ListCouponsRequest.page:type_name -> coupon.v1.PageRequest
+	1,  // 41: coupon.v1.ListCouponsRequest.coupon_status:type_name -> coupon.v1.CouponStatus
+	32, // 42: coupon.v1.GetCouponResponse.coupon:type_name -> coupon.v1.CouponSearchResult
+	32, // 43: coupon.v1.ValidateCouponResponse.coupon:type_name -> coupon.v1.CouponSearchResult
+	41, // 44: coupon.v1.ValidateCouponResponse.campaign:type_name -> coupon.v1.CouponCampaign
+	69, // 45: coupon.v1.CouponCampaign.start_date:type_name -> google.protobuf.Timestamp
+	69, // 46: coupon.v1.GetCouponPayloadResponse.expires_at:type_name -> google.protobuf.Timestamp
+	32, // 47: coupon.v1.ListCouponsResponse.coupons:type_name -> coupon.v1.CouponSearchResult
+	30, // 48: coupon.v1.ListCouponsResponse.page:type_name -> coupon.v1.PageResponse
+	69, // 49: coupon.v1.DeleteCampaignResponse.deleted_at:type_name -> google.protobuf.Timestamp
+	5,  // 50: coupon.v1.RestoreCampaignResponse.campaign:type_name -> coupon.v1.Campaign
+	5,  // 51: coupon.v1.UpdateCampaignResponse.campaign:type_name -> coupon.v1.Campaign
+	4,  // 52: coupon.v1.SoldOutInfo.reason:type_name -> coupon.v1.SoldOutReason
+	68, // 53: coupon.v1.BadRequest.field_violations:type_name -> coupon.v1.BadRequest.FieldViolation
+	69, // 54: coupon.v1.IssuanceWindowInfo.next_window_start:type_name -> google.protobuf.Timestamp
+	70, // 55: coupon.v1.RetryInfo.retry_delay:type_name -> google.protobuf.Duration
+	9,  // 56: coupon.v1.CouponService.CreateCampaign:input_type -> coupon.v1.CreateCampaignRequest
+	13, // 57: coupon.v1.CouponService.BatchCreateCampaigns:input_type -> coupon.v1.BatchCreateCampaignsRequest
+	9,  // 58: coupon.v1.CouponService.CreateCampaignStream:input_type -> coupon.v1.CreateCampaignRequest
+	15, // 59: coupon.v1.CouponService.GetCampaign:input_type -> coupon.v1.GetCampaignRequest
+	17, // 60: coupon.v1.CouponService.IssueCoupon:input_type -> coupon.v1.IssueCouponRequest
+	19, // 61: coupon.v1.CouponService.IssueBatch:input_type -> coupon.v1.IssueBatchRequest
+	23, // 62: coupon.v1.CouponService.GetCampaignStats:input_type -> coupon.v1.GetCampaignStatsRequest
+	25, // 63: coupon.v1.CouponService.GetRemaining:input_type -> coupon.v1.GetRemainingRequest
+	27, // 64: coupon.v1.CouponService.RegenerateCoupons:input_type -> coupon.v1.RegenerateCouponsRequest
+	31, // 65: coupon.v1.CouponService.SearchCoupons:input_type -> coupon.v1.SearchCouponsRequest
+	37, // 66: coupon.v1.CouponService.GetCoupon:input_type -> coupon.v1.GetCouponRequest
+	39, // 67: coupon.v1.CouponService.ValidateCoupon:input_type -> coupon.v1.ValidateCouponRequest
+	42, // 68: coupon.v1.CouponService.GetCouponPayload:input_type -> coupon.v1.GetCouponPayloadRequest
+	34, // 69: coupon.v1.CouponService.ListCampaigns:input_type -> coupon.v1.ListCampaignsRequest
+	36, // 70: coupon.v1.CouponService.ListCoupons:input_type -> coupon.v1.ListCouponsRequest
+	45, // 71: coupon.v1.CouponService.DeleteCampaign:input_type -> coupon.v1.DeleteCampaignRequest
+	53, // 72: coupon.v1.CouponService.UpdateCampaign:input_type -> coupon.v1.UpdateCampaignRequest
+	47, // 73: coupon.v1.CouponService.RestoreCampaign:input_type -> coupon.v1.RestoreCampaignRequest
+	49, // 74: coupon.v1.CouponService.TransferCoupons:input_type -> coupon.v1.TransferCouponsRequest
+	51, // 75: coupon.v1.CouponService.RevokeCampaignCoupons:input_type -> coupon.v1.RevokeCampaignCouponsRequest
+	55, // 76: coupon.v1.CouponService.DrainCampaign:input_type -> coupon.v1.DrainCampaignRequest
+	57, // 77: coupon.v1.CouponService.PeekAvailableCoupons:input_type -> coupon.v1.PeekAvailableCouponsRequest
+	59, // 78: coupon.v1.CouponService.VerifyCampaignCodes:input_type -> coupon.v1.VerifyCampaignCodesRequest
+	11, // 79: coupon.v1.CouponService.CreateCampaign:output_type -> coupon.v1.CreateCampaignResponse
+	14, // 80: coupon.v1.CouponService.BatchCreateCampaigns:output_type -> coupon.v1.BatchCreateCampaignsResponse
+	12, // 81: coupon.v1.CouponService.CreateCampaignStream:output_type -> coupon.v1.CreateCampaignProgress
+	16, // 82: coupon.v1.CouponService.GetCampaign:output_type -> coupon.v1.GetCampaignResponse
+	18, // 83: coupon.v1.CouponService.IssueCoupon:output_type -> coupon.v1.IssueCouponResponse
+	20, // 84: coupon.v1.CouponService.IssueBatch:output_type -> coupon.v1.IssueBatchResponse
+	24, // 85: coupon.v1.CouponService.GetCampaignStats:output_type -> coupon.v1.GetCampaignStatsResponse
+	26, // 86: coupon.v1.CouponService.GetRemaining:output_type -> coupon.v1.GetRemainingResponse
+	28, // 87: coupon.v1.CouponService.RegenerateCoupons:output_type -> coupon.v1.RegenerateCouponsResponse
+	33, // 88: coupon.v1.CouponService.SearchCoupons:output_type -> coupon.v1.SearchCouponsResponse
+	38, // 89: coupon.v1.CouponService.GetCoupon:output_type -> coupon.v1.GetCouponResponse
+	40, // 90: coupon.v1.CouponService.ValidateCoupon:output_type -> coupon.v1.ValidateCouponResponse
+	43, // 91: coupon.v1.CouponService.GetCouponPayload:output_type -> coupon.v1.GetCouponPayloadResponse
+	35, // 92: coupon.v1.CouponService.ListCampaigns:output_type -> coupon.v1.ListCampaignsResponse
+	44, // 93: coupon.v1.CouponService.ListCoupons:output_type -> coupon.v1.ListCouponsResponse
+	46, // 94: coupon.v1.CouponService.DeleteCampaign:output_type -> coupon.v1.DeleteCampaignResponse
+	54, // 95: coupon.v1.CouponService.UpdateCampaign:output_type -> coupon.v1.UpdateCampaignResponse
+	48, // 96: coupon.v1.CouponService.RestoreCampaign:output_type -> coupon.v1.RestoreCampaignResponse
+	50, // 97: coupon.v1.CouponService.TransferCoupons:output_type -> coupon.v1.TransferCouponsResponse
+	52, // 98: coupon.v1.CouponService.RevokeCampaignCoupons:output_type -> coupon.v1.RevokeCampaignCouponsResponse
+	56, // 99: coupon.v1.CouponService.DrainCampaign:output_type -> coupon.v1.DrainCampaignResponse
+	58, // 100: coupon.v1.CouponService.PeekAvailableCoupons:output_type -> coupon.v1.PeekAvailableCouponsResponse
+	60, // 101: coupon.v1.CouponService.VerifyCampaignCodes:output_type -> coupon.v1.VerifyCampaignCodesResponse
+	79, // [79:102] is the sub-list for method output_type
+	56, // [56:79] is the sub-list for method input_type
+	56, // [56:56] is the sub-list for extension type_name
+	56, // [56:56] is the sub-list for extension extendee
+	0,  // [0:56] is the sub-list for field type_name
 }
 
 func init() { file_coupon_v1_coupon_proto_init() }
@@ -508,18 +4803,27 @@ func file_coupon_v1_coupon_proto_init() {
 	if File_coupon_v1_coupon_proto != nil {
 		return
 	}
+	file_coupon_v1_coupon_proto_msgTypes[10].OneofWrappers = []any{
+		(*GetCampaignRequest_CampaignId)(nil),
+		(*GetCampaignRequest_Slug)(nil),
+	}
+	file_coupon_v1_coupon_proto_msgTypes[12].OneofWrappers = []any{
+		(*IssueCouponRequest_CampaignId)(nil),
+		(*IssueCouponRequest_Slug)(nil),
+	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_coupon_v1_coupon_proto_rawDesc), len(file_coupon_v1_coupon_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   8,
+			NumEnums:      5,
+			NumMessages:   64,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_coupon_v1_coupon_proto_goTypes,
 		DependencyIndexes: file_coupon_v1_coupon_proto_depIdxs,
+		EnumInfos:         file_coupon_v1_coupon_proto_enumTypes,
 		MessageInfos:      file_coupon_v1_coupon_proto_msgTypes,
 	}.Build()
 	File_coupon_v1_coupon_proto = out.File
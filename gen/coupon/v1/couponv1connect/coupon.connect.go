@@ -36,22 +36,137 @@ const (
 	// CouponServiceCreateCampaignProcedure is the fully-qualified name of the CouponService's
 	// CreateCampaign RPC.
 	CouponServiceCreateCampaignProcedure = "/coupon.v1.CouponService/CreateCampaign"
+	// CouponServiceBatchCreateCampaignsProcedure is the fully-qualified name of the CouponService's
+	// BatchCreateCampaigns RPC.
+	CouponServiceBatchCreateCampaignsProcedure = "/coupon.v1.CouponService/BatchCreateCampaigns"
+	// CouponServiceCreateCampaignStreamProcedure is the fully-qualified name of the CouponService's
+	// CreateCampaignStream RPC.
+	CouponServiceCreateCampaignStreamProcedure = "/coupon.v1.CouponService/CreateCampaignStream"
 	// CouponServiceGetCampaignProcedure is the fully-qualified name of the CouponService's GetCampaign
 	// RPC.
 	CouponServiceGetCampaignProcedure = "/coupon.v1.CouponService/GetCampaign"
 	// CouponServiceIssueCouponProcedure is the fully-qualified name of the CouponService's IssueCoupon
 	// RPC.
 	CouponServiceIssueCouponProcedure = "/coupon.v1.CouponService/IssueCoupon"
+	// CouponServiceIssueBatchProcedure is the fully-qualified name of the CouponService's IssueBatch
+	// RPC.
+	CouponServiceIssueBatchProcedure = "/coupon.v1.CouponService/IssueBatch"
+	// CouponServiceGetCampaignStatsProcedure is the fully-qualified name of the CouponService's
+	// GetCampaignStats RPC.
+	CouponServiceGetCampaignStatsProcedure = "/coupon.v1.CouponService/GetCampaignStats"
+	// CouponServiceGetRemainingProcedure is the fully-qualified name of the CouponService's
+	// GetRemaining RPC.
+	CouponServiceGetRemainingProcedure = "/coupon.v1.CouponService/GetRemaining"
+	// CouponServiceRegenerateCouponsProcedure is the fully-qualified name of the CouponService's
+	// RegenerateCoupons RPC.
+	CouponServiceRegenerateCouponsProcedure = "/coupon.v1.CouponService/RegenerateCoupons"
+	// CouponServiceSearchCouponsProcedure is the fully-qualified name of the CouponService's
+	// SearchCoupons RPC.
+	CouponServiceSearchCouponsProcedure = "/coupon.v1.CouponService/SearchCoupons"
+	// CouponServiceGetCouponProcedure is the fully-qualified name of the CouponService's GetCoupon RPC.
+	CouponServiceGetCouponProcedure = "/coupon.v1.CouponService/GetCoupon"
+	// CouponServiceValidateCouponProcedure is the fully-qualified name of the CouponService's
+	// ValidateCoupon RPC.
+	CouponServiceValidateCouponProcedure = "/coupon.v1.CouponService/ValidateCoupon"
+	// CouponServiceGetCouponPayloadProcedure is the fully-qualified name of the CouponService's
+	// GetCouponPayload RPC.
+	CouponServiceGetCouponPayloadProcedure = "/coupon.v1.CouponService/GetCouponPayload"
+	// CouponServiceListCampaignsProcedure is the fully-qualified name of the CouponService's
+	// ListCampaigns RPC.
+	CouponServiceListCampaignsProcedure = "/coupon.v1.CouponService/ListCampaigns"
+	// CouponServiceListCouponsProcedure is the fully-qualified name of the CouponService's ListCoupons
+	// RPC.
+	CouponServiceListCouponsProcedure = "/coupon.v1.CouponService/ListCoupons"
+	// CouponServiceDeleteCampaignProcedure is the fully-qualified name of the CouponService's
+	// DeleteCampaign RPC.
+	CouponServiceDeleteCampaignProcedure = "/coupon.v1.CouponService/DeleteCampaign"
+	// CouponServiceUpdateCampaignProcedure is the fully-qualified name of the CouponService's
+	// UpdateCampaign RPC.
+	CouponServiceUpdateCampaignProcedure = "/coupon.v1.CouponService/UpdateCampaign"
+	// CouponServiceRestoreCampaignProcedure is the fully-qualified name of the CouponService's
+	// RestoreCampaign RPC.
+	CouponServiceRestoreCampaignProcedure = "/coupon.v1.CouponService/RestoreCampaign"
+	// CouponServiceTransferCouponsProcedure is the fully-qualified name of the CouponService's
+	// TransferCoupons RPC.
+	CouponServiceTransferCouponsProcedure = "/coupon.v1.CouponService/TransferCoupons"
+	// CouponServiceRevokeCampaignCouponsProcedure is the fully-qualified name of the CouponService's
+	// RevokeCampaignCoupons RPC.
+	CouponServiceRevokeCampaignCouponsProcedure = "/coupon.v1.CouponService/RevokeCampaignCoupons"
+	// CouponServiceDrainCampaignProcedure is the fully-qualified name of the CouponService's
+	// DrainCampaign RPC.
+	CouponServiceDrainCampaignProcedure = "/coupon.v1.CouponService/DrainCampaign"
+	// CouponServicePeekAvailableCouponsProcedure is the fully-qualified name of the CouponService's
+	// PeekAvailableCoupons RPC.
+	CouponServicePeekAvailableCouponsProcedure = "/coupon.v1.CouponService/PeekAvailableCoupons"
+	// CouponServiceVerifyCampaignCodesProcedure is the fully-qualified name of the CouponService's
+	// VerifyCampaignCodes RPC.
+	CouponServiceVerifyCampaignCodesProcedure = "/coupon.v1.CouponService/VerifyCampaignCodes"
 )
 
 // CouponServiceClient is a client for the coupon.v1.CouponService service.
 type CouponServiceClient interface {
 	// CreateCampaign creates a new coupon campaign
 	CreateCampaign(context.Context, *connect.Request[v1.CreateCampaignRequest]) (*connect.Response[v1.CreateCampaignResponse], error)
+	// BatchCreateCampaigns creates several campaigns in one transaction; when
+	// any of them fails, none is created
+	BatchCreateCampaigns(context.Context, *connect.Request[v1.BatchCreateCampaignsRequest]) (*connect.Response[v1.BatchCreateCampaignsResponse], error)
+	// CreateCampaignStream creates a campaign like CreateCampaign, reporting
+	// the progress of code generation and insertion before the created campaign
+	CreateCampaignStream(context.Context, *connect.Request[v1.CreateCampaignRequest]) (*connect.ServerStreamForClient[v1.CreateCampaignProgress], error)
 	// GetCampaign gets campaign information including all issued coupon codes
 	GetCampaign(context.Context, *connect.Request[v1.GetCampaignRequest]) (*connect.Response[v1.GetCampaignResponse], error)
 	// IssueCoupon requests coupon issuance on specific campaign
 	IssueCoupon(context.Context, *connect.Request[v1.IssueCouponRequest]) (*connect.Response[v1.IssueCouponResponse], error)
+	// IssueBatch issues up to quantity coupons to one user in a single
+	// transaction, respecting the campaign's per-user limit across the batch
+	IssueBatch(context.Context, *connect.Request[v1.IssueBatchRequest]) (*connect.Response[v1.IssueBatchResponse], error)
+	// GetCampaignStats returns coupon counts for a campaign
+	GetCampaignStats(context.Context, *connect.Request[v1.GetCampaignStatsRequest]) (*connect.Response[v1.GetCampaignStatsResponse], error)
+	// GetRemaining returns only the number of coupons left in a campaign. It is
+	// cheap enough to be polled by live countdowns.
+	GetRemaining(context.Context, *connect.Request[v1.GetRemainingRequest]) (*connect.Response[v1.GetRemainingResponse], error)
+	// RegenerateCoupons (admin) replaces all unissued coupon codes of a campaign
+	// with codes generated under an updated code format. Issued coupons keep
+	// their codes, which stay valid.
+	RegenerateCoupons(context.Context, *connect.Request[v1.RegenerateCouponsRequest]) (*connect.Response[v1.RegenerateCouponsResponse], error)
+	// SearchCoupons finds coupons of a campaign whose code starts with a prefix
+	SearchCoupons(context.Context, *connect.Request[v1.SearchCouponsRequest]) (*connect.Response[v1.SearchCouponsResponse], error)
+	// GetCoupon gets a single coupon by its code
+	GetCoupon(context.Context, *connect.Request[v1.GetCouponRequest]) (*connect.Response[v1.GetCouponResponse], error)
+	// ValidateCoupon gets a coupon together with the campaign fields needed
+	// for a redemption check, in one call
+	ValidateCoupon(context.Context, *connect.Request[v1.ValidateCouponRequest]) (*connect.Response[v1.ValidateCouponResponse], error)
+	// GetCouponPayload returns a signed token for rendering an issued coupon as
+	// a QR code or barcode that scanners can verify offline
+	GetCouponPayload(context.Context, *connect.Request[v1.GetCouponPayloadRequest]) (*connect.Response[v1.GetCouponPayloadResponse], error)
+	// ListCampaigns lists campaigns ordered by ID
+	ListCampaigns(context.Context, *connect.Request[v1.ListCampaignsRequest]) (*connect.Response[v1.ListCampaignsResponse], error)
+	// ListCoupons lists the coupons of a campaign ordered by code
+	ListCoupons(context.Context, *connect.Request[v1.ListCouponsRequest]) (*connect.Response[v1.ListCouponsResponse], error)
+	// DeleteCampaign (admin) soft-deletes a campaign, hiding it from reads and
+	// stopping issuance. Rows are kept and can be restored.
+	DeleteCampaign(context.Context, *connect.Request[v1.DeleteCampaignRequest]) (*connect.Response[v1.DeleteCampaignResponse], error)
+	// UpdateCampaign (admin) changes the settings of a live campaign. Changes
+	// take effect without a restart.
+	UpdateCampaign(context.Context, *connect.Request[v1.UpdateCampaignRequest]) (*connect.Response[v1.UpdateCampaignResponse], error)
+	// RestoreCampaign (admin) undoes a DeleteCampaign
+	RestoreCampaign(context.Context, *connect.Request[v1.RestoreCampaignRequest]) (*connect.Response[v1.RestoreCampaignResponse], error)
+	// TransferCoupons (admin) moves unissued coupons from one campaign to another
+	TransferCoupons(context.Context, *connect.Request[v1.TransferCouponsRequest]) (*connect.Response[v1.TransferCouponsResponse], error)
+	// RevokeCampaignCoupons (admin) revokes every issued coupon of a campaign,
+	// e.g. when the campaign turns out to be fraudulent
+	RevokeCampaignCoupons(context.Context, *connect.Request[v1.RevokeCampaignCouponsRequest]) (*connect.Response[v1.RevokeCampaignCouponsResponse], error)
+	// DrainCampaign (admin) issues every remaining coupon of a campaign to one
+	// recipient, e.g. a distribution partner at the end of a promotion
+	DrainCampaign(context.Context, *connect.Request[v1.DrainCampaignRequest]) (*connect.Response[v1.DrainCampaignResponse], error)
+	// PeekAvailableCoupons (admin) lists available coupon codes of a campaign
+	// for internal tooling. It is read-only: the coupons stay available and may
+	// be issued to someone else at any time.
+	PeekAvailableCoupons(context.Context, *connect.Request[v1.PeekAvailableCouponsRequest]) (*connect.Response[v1.PeekAvailableCouponsResponse], error)
+	// VerifyCampaignCodes (admin) audits a campaign's coupons: every code must
+	// be regenerated from its coupon index, and no index may be used twice.
+	// Coupons are scanned in batches, so large campaigns take a while.
+	VerifyCampaignCodes(context.Context, *connect.Request[v1.VerifyCampaignCodesRequest]) (*connect.Response[v1.VerifyCampaignCodesResponse], error)
 }
 
 // NewCouponServiceClient constructs a client for the coupon.v1.CouponService service. By default,
@@ -71,6 +186,18 @@ func NewCouponServiceClient(httpClient connect.HTTPClient, baseURL string, opts
 			connect.WithSchema(couponServiceMethods.ByName("CreateCampaign")),
 			connect.WithClientOptions(opts...),
 		),
+		batchCreateCampaigns: connect.NewClient[v1.BatchCreateCampaignsRequest, v1.BatchCreateCampaignsResponse](
+			httpClient,
+			baseURL+CouponServiceBatchCreateCampaignsProcedure,
+			connect.WithSchema(couponServiceMethods.ByName("BatchCreateCampaigns")),
+			connect.WithClientOptions(opts...),
+		),
+		createCampaignStream: connect.NewClient[v1.CreateCampaignRequest, v1.CreateCampaignProgress](
+			httpClient,
+			baseURL+CouponServiceCreateCampaignStreamProcedure,
+			connect.WithSchema(couponServiceMethods.ByName("CreateCampaignStream")),
+			connect.WithClientOptions(opts...),
+		),
 		getCampaign: connect.NewClient[v1.GetCampaignRequest, v1.GetCampaignResponse](
 			httpClient,
 			baseURL+CouponServiceGetCampaignProcedure,
@@ -83,14 +210,142 @@ func NewCouponServiceClient(httpClient connect.HTTPClient, baseURL string, opts
 			connect.WithSchema(couponServiceMethods.ByName("IssueCoupon")),
 			connect.WithClientOptions(opts...),
 		),
+		issueBatch: connect.NewClient[v1.IssueBatchRequest, v1.IssueBatchResponse](
+			httpClient,
+			baseURL+CouponServiceIssueBatchProcedure,
+			connect.WithSchema(couponServiceMethods.ByName("IssueBatch")),
+			connect.WithClientOptions(opts...),
+		),
+		getCampaignStats: connect.NewClient[v1.GetCampaignStatsRequest, v1.GetCampaignStatsResponse](
+			httpClient,
+			baseURL+CouponServiceGetCampaignStatsProcedure,
+			connect.WithSchema(couponServiceMethods.ByName("GetCampaignStats")),
+			connect.WithClientOptions(opts...),
+		),
+		getRemaining: connect.NewClient[v1.GetRemainingRequest, v1.GetRemainingResponse](
+			httpClient,
+			baseURL+CouponServiceGetRemainingProcedure,
+			connect.WithSchema(couponServiceMethods.ByName("GetRemaining")),
+			connect.WithClientOptions(opts...),
+		),
+		regenerateCoupons: connect.NewClient[v1.RegenerateCouponsRequest, v1.RegenerateCouponsResponse](
+			httpClient,
+			baseURL+CouponServiceRegenerateCouponsProcedure,
+			connect.WithSchema(couponServiceMethods.ByName("RegenerateCoupons")),
+			connect.WithClientOptions(opts...),
+		),
+		searchCoupons: connect.NewClient[v1.SearchCouponsRequest, v1.SearchCouponsResponse](
+			httpClient,
+			baseURL+CouponServiceSearchCouponsProcedure,
+			connect.WithSchema(couponServiceMethods.ByName("SearchCoupons")),
+			connect.WithClientOptions(opts...),
+		),
+		getCoupon: connect.NewClient[v1.GetCouponRequest, v1.GetCouponResponse](
+			httpClient,
+			baseURL+CouponServiceGetCouponProcedure,
+			connect.WithSchema(couponServiceMethods.ByName("GetCoupon")),
+			connect.WithClientOptions(opts...),
+		),
+		validateCoupon: connect.NewClient[v1.ValidateCouponRequest, v1.ValidateCouponResponse](
+			httpClient,
+			baseURL+CouponServiceValidateCouponProcedure,
+			connect.WithSchema(couponServiceMethods.ByName("ValidateCoupon")),
+			connect.WithClientOptions(opts...),
+		),
+		getCouponPayload: connect.NewClient[v1.GetCouponPayloadRequest, v1.GetCouponPayloadResponse](
+			httpClient,
+			baseURL+CouponServiceGetCouponPayloadProcedure,
+			connect.WithSchema(couponServiceMethods.ByName("GetCouponPayload")),
+			connect.WithClientOptions(opts...),
+		),
+		listCampaigns: connect.NewClient[v1.ListCampaignsRequest, v1.ListCampaignsResponse](
+			httpClient,
+			baseURL+CouponServiceListCampaignsProcedure,
+			connect.WithSchema(couponServiceMethods.ByName("ListCampaigns")),
+			connect.WithClientOptions(opts...),
+		),
+		listCoupons: connect.NewClient[v1.ListCouponsRequest, v1.ListCouponsResponse](
+			httpClient,
+			baseURL+CouponServiceListCouponsProcedure,
+			connect.WithSchema(couponServiceMethods.ByName("ListCoupons")),
+			connect.WithClientOptions(opts...),
+		),
+		deleteCampaign: connect.NewClient[v1.DeleteCampaignRequest, v1.DeleteCampaignResponse](
+			httpClient,
+			baseURL+CouponServiceDeleteCampaignProcedure,
+			connect.WithSchema(couponServiceMethods.ByName("DeleteCampaign")),
+			connect.WithClientOptions(opts...),
+		),
+		updateCampaign: connect.NewClient[v1.UpdateCampaignRequest, v1.UpdateCampaignResponse](
+			httpClient,
+			baseURL+CouponServiceUpdateCampaignProcedure,
+			connect.WithSchema(couponServiceMethods.ByName("UpdateCampaign")),
+			connect.WithClientOptions(opts...),
+		),
+		restoreCampaign: connect.NewClient[v1.RestoreCampaignRequest, v1.RestoreCampaignResponse](
+			httpClient,
+			baseURL+CouponServiceRestoreCampaignProcedure,
+			connect.WithSchema(couponServiceMethods.ByName("RestoreCampaign")),
+			connect.WithClientOptions(opts...),
+		),
+		transferCoupons: connect.NewClient[v1.TransferCouponsRequest, v1.TransferCouponsResponse](
+			httpClient,
+			baseURL+CouponServiceTransferCouponsProcedure,
+			connect.WithSchema(couponServiceMethods.ByName("TransferCoupons")),
+			connect.WithClientOptions(opts...),
+		),
+		revokeCampaignCoupons: connect.NewClient[v1.RevokeCampaignCouponsRequest, v1.RevokeCampaignCouponsResponse](
+			httpClient,
+			baseURL+CouponServiceRevokeCampaignCouponsProcedure,
+			connect.WithSchema(couponServiceMethods.ByName("RevokeCampaignCoupons")),
+			connect.WithClientOptions(opts...),
+		),
+		drainCampaign: connect.NewClient[v1.DrainCampaignRequest, v1.DrainCampaignResponse](
+			httpClient,
+			baseURL+CouponServiceDrainCampaignProcedure,
+			connect.WithSchema(couponServiceMethods.ByName("DrainCampaign")),
+			connect.WithClientOptions(opts...),
+		),
+		peekAvailableCoupons: connect.NewClient[v1.PeekAvailableCouponsRequest, v1.PeekAvailableCouponsResponse](
+			httpClient,
+			baseURL+CouponServicePeekAvailableCouponsProcedure,
+			connect.WithSchema(couponServiceMethods.ByName("PeekAvailableCoupons")),
+			connect.WithClientOptions(opts...),
+		),
+		verifyCampaignCodes: connect.NewClient[v1.VerifyCampaignCodesRequest, v1.VerifyCampaignCodesResponse](
+			httpClient,
+			baseURL+CouponServiceVerifyCampaignCodesProcedure,
+			connect.WithSchema(couponServiceMethods.ByName("VerifyCampaignCodes")),
+			connect.WithClientOptions(opts...),
+		),
 	}
 }
 
 // couponServiceClient implements CouponServiceClient.
 type couponServiceClient struct {
-	createCampaign *connect.Client[v1.CreateCampaignRequest, v1.CreateCampaignResponse]
-	getCampaign    *connect.Client[v1.GetCampaignRequest, v1.GetCampaignResponse]
-	issueCoupon    *connect.Client[v1.IssueCouponRequest, v1.IssueCouponResponse]
+	createCampaign        *connect.Client[v1.CreateCampaignRequest, v1.CreateCampaignResponse]
+	batchCreateCampaigns  *connect.Client[v1.BatchCreateCampaignsRequest, v1.BatchCreateCampaignsResponse]
+	createCampaignStream  *connect.Client[v1.CreateCampaignRequest, v1.CreateCampaignProgress]
+	getCampaign           *connect.Client[v1.GetCampaignRequest, v1.GetCampaignResponse]
+	issueCoupon           *connect.Client[v1.IssueCouponRequest, v1.IssueCouponResponse]
+	issueBatch            *connect.Client[v1.IssueBatchRequest, v1.IssueBatchResponse]
+	getCampaignStats      *connect.Client[v1.GetCampaignStatsRequest, v1.GetCampaignStatsResponse]
+	getRemaining          *connect.Client[v1.GetRemainingRequest, v1.GetRemainingResponse]
+	regenerateCoupons     *connect.Client[v1.RegenerateCouponsRequest, v1.RegenerateCouponsResponse]
+	searchCoupons         *connect.Client[v1.SearchCouponsRequest, v1.SearchCouponsResponse]
+	getCoupon             *connect.Client[v1.GetCouponRequest, v1.GetCouponResponse]
+	validateCoupon        *connect.Client[v1.ValidateCouponRequest, v1.ValidateCouponResponse]
+	getCouponPayload      *connect.Client[v1.GetCouponPayloadRequest, v1.GetCouponPayloadResponse]
+	listCampaigns         *connect.Client[v1.ListCampaignsRequest, v1.ListCampaignsResponse]
+	listCoupons           *connect.Client[v1.ListCouponsRequest, v1.ListCouponsResponse]
+	deleteCampaign        *connect.Client[v1.DeleteCampaignRequest, v1.DeleteCampaignResponse]
+	updateCampaign        *connect.Client[v1.UpdateCampaignRequest, v1.UpdateCampaignResponse]
+	restoreCampaign       *connect.Client[v1.RestoreCampaignRequest, v1.RestoreCampaignResponse]
+	transferCoupons       *connect.Client[v1.TransferCouponsRequest, v1.TransferCouponsResponse]
+	revokeCampaignCoupons *connect.Client[v1.RevokeCampaignCouponsRequest, v1.RevokeCampaignCouponsResponse]
+	drainCampaign         *connect.Client[v1.DrainCampaignRequest, v1.DrainCampaignResponse]
+	peekAvailableCoupons  *connect.Client[v1.PeekAvailableCouponsRequest, v1.PeekAvailableCouponsResponse]
+	verifyCampaignCodes   *connect.Client[v1.VerifyCampaignCodesRequest, v1.VerifyCampaignCodesResponse]
 }
 
 // CreateCampaign calls coupon.v1.CouponService.CreateCampaign.
@@ -98,6 +353,16 @@ func (c *couponServiceClient) CreateCampaign(ctx context.Context, req *connect.R
 	return c.createCampaign.CallUnary(ctx, req)
 }
 
+// BatchCreateCampaigns calls coupon.v1.CouponService.BatchCreateCampaigns.
+func (c *couponServiceClient) BatchCreateCampaigns(ctx context.Context, req *connect.Request[v1.BatchCreateCampaignsRequest]) (*connect.Response[v1.BatchCreateCampaignsResponse], error) {
+	return c.batchCreateCampaigns.CallUnary(ctx, req)
+}
+
+// CreateCampaignStream calls coupon.v1.CouponService.CreateCampaignStream.
+func (c *couponServiceClient) CreateCampaignStream(ctx context.Context, req *connect.Request[v1.CreateCampaignRequest]) (*connect.ServerStreamForClient[v1.CreateCampaignProgress], error) {
+	return c.createCampaignStream.CallServerStream(ctx, req)
+}
+
 // GetCampaign calls coupon.v1.CouponService.GetCampaign.
 func (c *couponServiceClient) GetCampaign(ctx context.Context, req *connect.Request[v1.GetCampaignRequest]) (*connect.Response[v1.GetCampaignResponse], error) {
 	return c.getCampaign.CallUnary(ctx, req)
@@ -108,14 +373,160 @@ func (c *couponServiceClient) IssueCoupon(ctx context.Context, req *connect.Requ
 	return c.issueCoupon.CallUnary(ctx, req)
 }
 
+// IssueBatch calls coupon.v1.CouponService.IssueBatch.
+func (c *couponServiceClient) IssueBatch(ctx context.Context, req *connect.Request[v1.IssueBatchRequest]) (*connect.Response[v1.IssueBatchResponse], error) {
+	return c.issueBatch.CallUnary(ctx, req)
+}
+
+// GetCampaignStats calls coupon.v1.CouponService.GetCampaignStats.
+func (c *couponServiceClient) GetCampaignStats(ctx context.Context, req *connect.Request[v1.GetCampaignStatsRequest]) (*connect.Response[v1.GetCampaignStatsResponse], error) {
+	return c.getCampaignStats.CallUnary(ctx, req)
+}
+
+// GetRemaining calls coupon.v1.CouponService.GetRemaining.
+func (c *couponServiceClient) GetRemaining(ctx context.Context, req *connect.Request[v1.GetRemainingRequest]) (*connect.Response[v1.GetRemainingResponse], error) {
+	return c.getRemaining.CallUnary(ctx, req)
+}
+
+// RegenerateCoupons calls coupon.v1.CouponService.RegenerateCoupons.
+func (c *couponServiceClient) RegenerateCoupons(ctx context.Context, req *connect.Request[v1.RegenerateCouponsRequest]) (*connect.Response[v1.RegenerateCouponsResponse], error) {
+	return c.regenerateCoupons.CallUnary(ctx, req)
+}
+
+// SearchCoupons calls coupon.v1.CouponService.SearchCoupons.
+func (c *couponServiceClient) SearchCoupons(ctx context.Context, req *connect.Request[v1.SearchCouponsRequest]) (*connect.Response[v1.SearchCouponsResponse], error) {
+	return c.searchCoupons.CallUnary(ctx, req)
+}
+
+// GetCoupon calls coupon.v1.CouponService.GetCoupon.
+func (c *couponServiceClient) GetCoupon(ctx context.Context, req *connect.Request[v1.GetCouponRequest]) (*connect.Response[v1.GetCouponResponse], error) {
+	return c.getCoupon.CallUnary(ctx, req)
+}
+
+// ValidateCoupon calls coupon.v1.CouponService.ValidateCoupon.
+func (c *couponServiceClient) ValidateCoupon(ctx context.Context, req *connect.Request[v1.ValidateCouponRequest]) (*connect.Response[v1.ValidateCouponResponse], error) {
+	return c.validateCoupon.CallUnary(ctx, req)
+}
+
+// GetCouponPayload calls coupon.v1.CouponService.GetCouponPayload.
+func (c *couponServiceClient) GetCouponPayload(ctx context.Context, req *connect.Request[v1.GetCouponPayloadRequest]) (*connect.Response[v1.GetCouponPayloadResponse], error) {
+	return c.getCouponPayload.CallUnary(ctx, req)
+}
+
+// ListCampaigns calls coupon.v1.CouponService.ListCampaigns.
+func (c *couponServiceClient) ListCampaigns(ctx context.Context, req *connect.Request[v1.ListCampaignsRequest]) (*connect.Response[v1.ListCampaignsResponse], error) {
+	return c.listCampaigns.CallUnary(ctx, req)
+}
+
+// ListCoupons calls coupon.v1.CouponService.ListCoupons.
+func (c *couponServiceClient) ListCoupons(ctx context.Context, req *connect.Request[v1.ListCouponsRequest]) (*connect.Response[v1.ListCouponsResponse], error) {
+	return c.listCoupons.CallUnary(ctx, req)
+}
+
+// DeleteCampaign calls coupon.v1.CouponService.DeleteCampaign.
+func (c *couponServiceClient) DeleteCampaign(ctx context.Context, req *connect.Request[v1.DeleteCampaignRequest]) (*connect.Response[v1.DeleteCampaignResponse], error) {
+	return c.deleteCampaign.CallUnary(ctx, req)
+}
+
+// UpdateCampaign calls coupon.v1.CouponService.UpdateCampaign.
+func (c *couponServiceClient) UpdateCampaign(ctx context.Context, req *connect.Request[v1.UpdateCampaignRequest]) (*connect.Response[v1.UpdateCampaignResponse], error) {
+	return c.updateCampaign.CallUnary(ctx, req)
+}
+
+// RestoreCampaign calls coupon.v1.CouponService.RestoreCampaign.
+func (c *couponServiceClient) RestoreCampaign(ctx context.Context, req *connect.Request[v1.RestoreCampaignRequest]) (*connect.Response[v1.RestoreCampaignResponse], error) {
+	return c.restoreCampaign.CallUnary(ctx, req)
+}
+
+// TransferCoupons calls coupon.v1.CouponService.TransferCoupons.
+func (c *couponServiceClient) TransferCoupons(ctx context.Context, req *connect.Request[v1.TransferCouponsRequest]) (*connect.Response[v1.TransferCouponsResponse], error) {
+	return c.transferCoupons.CallUnary(ctx, req)
+}
+
+// RevokeCampaignCoupons calls coupon.v1.CouponService.RevokeCampaignCoupons.
+func (c *couponServiceClient) RevokeCampaignCoupons(ctx context.Context, req *connect.Request[v1.RevokeCampaignCouponsRequest]) (*connect.Response[v1.RevokeCampaignCouponsResponse], error) {
+	return c.revokeCampaignCoupons.CallUnary(ctx, req)
+}
+
+// DrainCampaign calls coupon.v1.CouponService.DrainCampaign.
+func (c *couponServiceClient) DrainCampaign(ctx context.Context, req *connect.Request[v1.DrainCampaignRequest]) (*connect.Response[v1.DrainCampaignResponse], error) {
+	return c.drainCampaign.CallUnary(ctx, req)
+}
+
+// PeekAvailableCoupons calls coupon.v1.CouponService.PeekAvailableCoupons.
+func (c *couponServiceClient) PeekAvailableCoupons(ctx context.Context, req *connect.Request[v1.PeekAvailableCouponsRequest]) (*connect.Response[v1.PeekAvailableCouponsResponse], error) {
+	return c.peekAvailableCoupons.CallUnary(ctx, req)
+}
+
+// VerifyCampaignCodes calls coupon.v1.CouponService.VerifyCampaignCodes.
+func (c *couponServiceClient) VerifyCampaignCodes(ctx context.Context, req *connect.Request[v1.VerifyCampaignCodesRequest]) (*connect.Response[v1.VerifyCampaignCodesResponse], error) {
+	return c.verifyCampaignCodes.CallUnary(ctx, req)
+}
+
 // CouponServiceHandler is an implementation of the coupon.v1.CouponService service.
 type CouponServiceHandler interface {
 	// CreateCampaign creates a new coupon campaign
 	CreateCampaign(context.Context, *connect.Request[v1.CreateCampaignRequest]) (*connect.Response[v1.CreateCampaignResponse], error)
+	// BatchCreateCampaigns creates several campaigns in one transaction; when
+	// any of them fails, none is created
+	BatchCreateCampaigns(context.Context, *connect.Request[v1.BatchCreateCampaignsRequest]) (*connect.Response[v1.BatchCreateCampaignsResponse], error)
+	// CreateCampaignStream creates a campaign like CreateCampaign, reporting
+	// the progress of code generation and insertion before the created campaign
+	CreateCampaignStream(context.Context, *connect.Request[v1.CreateCampaignRequest], *connect.ServerStream[v1.CreateCampaignProgress]) error
 	// GetCampaign gets campaign information including all issued coupon codes
 	GetCampaign(context.Context, *connect.Request[v1.GetCampaignRequest]) (*connect.Response[v1.GetCampaignResponse], error)
 	// IssueCoupon requests coupon issuance on specific campaign
 	IssueCoupon(context.Context, *connect.Request[v1.IssueCouponRequest]) (*connect.Response[v1.IssueCouponResponse], error)
+	// IssueBatch issues up to quantity coupons to one user in a single
+	// transaction, respecting the campaign's per-user limit across the batch
+	IssueBatch(context.Context, *connect.Request[v1.IssueBatchRequest]) (*connect.Response[v1.IssueBatchResponse], error)
+	// GetCampaignStats returns coupon counts for a campaign
+	GetCampaignStats(context.Context, *connect.Request[v1.GetCampaignStatsRequest]) (*connect.Response[v1.GetCampaignStatsResponse], error)
+	// GetRemaining returns only the number of coupons left in a campaign. It is
+	// cheap enough to be polled by live countdowns.
+	GetRemaining(context.Context, *connect.Request[v1.GetRemainingRequest]) (*connect.Response[v1.GetRemainingResponse], error)
+	// RegenerateCoupons (admin) replaces all unissued coupon codes of a campaign
+	// with codes generated under an updated code format. Issued coupons keep
+	// their codes, which stay valid.
+	RegenerateCoupons(context.Context, *connect.Request[v1.RegenerateCouponsRequest]) (*connect.Response[v1.RegenerateCouponsResponse], error)
+	// SearchCoupons finds coupons of a campaign whose code starts with a prefix
+	SearchCoupons(context.Context, *connect.Request[v1.SearchCouponsRequest]) (*connect.Response[v1.SearchCouponsResponse], error)
+	// GetCoupon gets a single coupon by its code
+	GetCoupon(context.Context, *connect.Request[v1.GetCouponRequest]) (*connect.Response[v1.GetCouponResponse], error)
+	// ValidateCoupon gets a coupon together with the campaign fields needed
+	// for a redemption check, in one call
+	ValidateCoupon(context.Context, *connect.Request[v1.ValidateCouponRequest]) (*connect.Response[v1.ValidateCouponResponse], error)
+	// GetCouponPayload returns a signed token for rendering an issued coupon as
+	// a QR code or barcode that scanners can verify offline
+	GetCouponPayload(context.Context, *connect.Request[v1.GetCouponPayloadRequest]) (*connect.Response[v1.GetCouponPayloadResponse], error)
+	// ListCampaigns lists campaigns ordered by ID
+	ListCampaigns(context.Context, *connect.Request[v1.ListCampaignsRequest]) (*connect.Response[v1.ListCampaignsResponse], error)
+	// ListCoupons lists the coupons of a campaign ordered by code
+	ListCoupons(context.Context, *connect.Request[v1.ListCouponsRequest]) (*connect.Response[v1.ListCouponsResponse], error)
+	// DeleteCampaign (admin) soft-deletes a campaign, hiding it from reads and
+	// stopping issuance. Rows are kept and can be restored.
+	DeleteCampaign(context.Context, *connect.Request[v1.DeleteCampaignRequest]) (*connect.Response[v1.DeleteCampaignResponse], error)
+	// UpdateCampaign (admin) changes the settings of a live campaign. Changes
+	// take effect without a restart.
+	UpdateCampaign(context.Context, *connect.Request[v1.UpdateCampaignRequest]) (*connect.Response[v1.UpdateCampaignResponse], error)
+	// RestoreCampaign (admin) undoes a DeleteCampaign
+	RestoreCampaign(context.Context, *connect.Request[v1.RestoreCampaignRequest]) (*connect.Response[v1.RestoreCampaignResponse], error)
+	// TransferCoupons (admin) moves unissued coupons from one campaign to another
+	TransferCoupons(context.Context, *connect.Request[v1.TransferCouponsRequest]) (*connect.Response[v1.TransferCouponsResponse], error)
+	// RevokeCampaignCoupons (admin) revokes every issued coupon of a campaign,
+	// e.g. when the campaign turns out to be fraudulent
+	RevokeCampaignCoupons(context.Context, *connect.Request[v1.RevokeCampaignCouponsRequest]) (*connect.Response[v1.RevokeCampaignCouponsResponse], error)
+	// DrainCampaign (admin) issues every remaining coupon of a campaign to one
+	// recipient, e.g. a distribution partner at the end of a promotion
+	DrainCampaign(context.Context, *connect.Request[v1.DrainCampaignRequest]) (*connect.Response[v1.DrainCampaignResponse], error)
+	// PeekAvailableCoupons (admin) lists available coupon codes of a campaign
+	// for internal tooling. It is read-only: the coupons stay available and may
+	// be issued to someone else at any time.
+	PeekAvailableCoupons(context.Context, *connect.Request[v1.PeekAvailableCouponsRequest]) (*connect.Response[v1.PeekAvailableCouponsResponse], error)
+	// VerifyCampaignCodes (admin) audits a campaign's coupons: every code must
+	// be regenerated from its coupon index, and no index may be used twice.
+	// Coupons are scanned in batches, so large campaigns take a while.
+	VerifyCampaignCodes(context.Context, *connect.Request[v1.VerifyCampaignCodesRequest]) (*connect.Response[v1.VerifyCampaignCodesResponse], error)
 }
 
 // NewCouponServiceHandler builds an HTTP handler from the service implementation. It returns the
@@ -131,6 +542,18 @@ func NewCouponServiceHandler(svc CouponServiceHandler, opts ...connect.HandlerOp
 		connect.WithSchema(couponServiceMethods.ByName("CreateCampaign")),
 		connect.WithHandlerOptions(opts...),
 	)
+	couponServiceBatchCreateCampaignsHandler := connect.NewUnaryHandler(
+		CouponServiceBatchCreateCampaignsProcedure,
+		svc.BatchCreateCampaigns,
+		connect.WithSchema(couponServiceMethods.ByName("BatchCreateCampaigns")),
+		connect.WithHandlerOptions(opts...),
+	)
+	couponServiceCreateCampaignStreamHandler := connect.NewServerStreamHandler(
+		CouponServiceCreateCampaignStreamProcedure,
+		svc.CreateCampaignStream,
+		connect.WithSchema(couponServiceMethods.ByName("CreateCampaignStream")),
+		connect.WithHandlerOptions(opts...),
+	)
 	couponServiceGetCampaignHandler := connect.NewUnaryHandler(
 		CouponServiceGetCampaignProcedure,
 		svc.GetCampaign,
@@ -143,14 +566,162 @@ func NewCouponServiceHandler(svc CouponServiceHandler, opts ...connect.HandlerOp
 		connect.WithSchema(couponServiceMethods.ByName("IssueCoupon")),
 		connect.WithHandlerOptions(opts...),
 	)
+	couponServiceIssueBatchHandler := connect.NewUnaryHandler(
+		CouponServiceIssueBatchProcedure,
+		svc.IssueBatch,
+		connect.WithSchema(couponServiceMethods.ByName("IssueBatch")),
+		connect.WithHandlerOptions(opts...),
+	)
+	couponServiceGetCampaignStatsHandler := connect.NewUnaryHandler(
+		CouponServiceGetCampaignStatsProcedure,
+		svc.GetCampaignStats,
+		connect.WithSchema(couponServiceMethods.ByName("GetCampaignStats")),
+		connect.WithHandlerOptions(opts...),
+	)
+	couponServiceGetRemainingHandler := connect.NewUnaryHandler(
+		CouponServiceGetRemainingProcedure,
+		svc.GetRemaining,
+		connect.WithSchema(couponServiceMethods.ByName("GetRemaining")),
+		connect.WithHandlerOptions(opts...),
+	)
+	couponServiceRegenerateCouponsHandler := connect.NewUnaryHandler(
+		CouponServiceRegenerateCouponsProcedure,
+		svc.RegenerateCoupons,
+		connect.WithSchema(couponServiceMethods.ByName("RegenerateCoupons")),
+		connect.WithHandlerOptions(opts...),
+	)
+	couponServiceSearchCouponsHandler := connect.NewUnaryHandler(
+		CouponServiceSearchCouponsProcedure,
+		svc.SearchCoupons,
+		connect.WithSchema(couponServiceMethods.ByName("SearchCoupons")),
+		connect.WithHandlerOptions(opts...),
+	)
+	couponServiceGetCouponHandler := connect.NewUnaryHandler(
+		CouponServiceGetCouponProcedure,
+		svc.GetCoupon,
+		connect.WithSchema(couponServiceMethods.ByName("GetCoupon")),
+		connect.WithHandlerOptions(opts...),
+	)
+	couponServiceValidateCouponHandler := connect.NewUnaryHandler(
+		CouponServiceValidateCouponProcedure,
+		svc.ValidateCoupon,
+		connect.WithSchema(couponServiceMethods.ByName("ValidateCoupon")),
+		connect.WithHandlerOptions(opts...),
+	)
+	couponServiceGetCouponPayloadHandler := connect.NewUnaryHandler(
+		CouponServiceGetCouponPayloadProcedure,
+		svc.GetCouponPayload,
+		connect.WithSchema(couponServiceMethods.ByName("GetCouponPayload")),
+		connect.WithHandlerOptions(opts...),
+	)
+	couponServiceListCampaignsHandler := connect.NewUnaryHandler(
+		CouponServiceListCampaignsProcedure,
+		svc.ListCampaigns,
+		connect.WithSchema(couponServiceMethods.ByName("ListCampaigns")),
+		connect.WithHandlerOptions(opts...),
+	)
+	couponServiceListCouponsHandler := connect.NewUnaryHandler(
+		CouponServiceListCouponsProcedure,
+		svc.ListCoupons,
+		connect.WithSchema(couponServiceMethods.ByName("ListCoupons")),
+		connect.WithHandlerOptions(opts...),
+	)
+	couponServiceDeleteCampaignHandler := connect.NewUnaryHandler(
+		CouponServiceDeleteCampaignProcedure,
+		svc.DeleteCampaign,
+		connect.WithSchema(couponServiceMethods.ByName("DeleteCampaign")),
+		connect.WithHandlerOptions(opts...),
+	)
+	couponServiceUpdateCampaignHandler := connect.NewUnaryHandler(
+		CouponServiceUpdateCampaignProcedure,
+		svc.UpdateCampaign,
+		connect.WithSchema(couponServiceMethods.ByName("UpdateCampaign")),
+		connect.WithHandlerOptions(opts...),
+	)
+	couponServiceRestoreCampaignHandler := connect.NewUnaryHandler(
+		CouponServiceRestoreCampaignProcedure,
+		svc.RestoreCampaign,
+		connect.WithSchema(couponServiceMethods.ByName("RestoreCampaign")),
+		connect.WithHandlerOptions(opts...),
+	)
+	couponServiceTransferCouponsHandler := connect.NewUnaryHandler(
+		CouponServiceTransferCouponsProcedure,
+		svc.TransferCoupons,
+		connect.WithSchema(couponServiceMethods.ByName("TransferCoupons")),
+		connect.WithHandlerOptions(opts...),
+	)
+	couponServiceRevokeCampaignCouponsHandler := connect.NewUnaryHandler(
+		CouponServiceRevokeCampaignCouponsProcedure,
+		svc.RevokeCampaignCoupons,
+		connect.WithSchema(couponServiceMethods.ByName("RevokeCampaignCoupons")),
+		connect.WithHandlerOptions(opts...),
+	)
+	couponServiceDrainCampaignHandler := connect.NewUnaryHandler(
+		CouponServiceDrainCampaignProcedure,
+		svc.DrainCampaign,
+		connect.WithSchema(couponServiceMethods.ByName("DrainCampaign")),
+		connect.WithHandlerOptions(opts...),
+	)
+	couponServicePeekAvailableCouponsHandler := connect.NewUnaryHandler(
+		CouponServicePeekAvailableCouponsProcedure,
+		svc.PeekAvailableCoupons,
+		connect.WithSchema(couponServiceMethods.ByName("PeekAvailableCoupons")),
+		connect.WithHandlerOptions(opts...),
+	)
+	couponServiceVerifyCampaignCodesHandler := connect.NewUnaryHandler(
+		CouponServiceVerifyCampaignCodesProcedure,
+		svc.VerifyCampaignCodes,
+		connect.WithSchema(couponServiceMethods.ByName("VerifyCampaignCodes")),
+		connect.WithHandlerOptions(opts...),
+	)
 	return "/coupon.v1.CouponService/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		switch r.URL.Path {
 		case CouponServiceCreateCampaignProcedure:
 			couponServiceCreateCampaignHandler.ServeHTTP(w, r)
+		case CouponServiceBatchCreateCampaignsProcedure:
+			couponServiceBatchCreateCampaignsHandler.ServeHTTP(w, r)
+		case CouponServiceCreateCampaignStreamProcedure:
+			couponServiceCreateCampaignStreamHandler.ServeHTTP(w, r)
 		case CouponServiceGetCampaignProcedure:
 			couponServiceGetCampaignHandler.ServeHTTP(w, r)
 		case CouponServiceIssueCouponProcedure:
 			couponServiceIssueCouponHandler.ServeHTTP(w, r)
+		case CouponServiceIssueBatchProcedure:
+			couponServiceIssueBatchHandler.ServeHTTP(w, r)
+		case CouponServiceGetCampaignStatsProcedure:
+			couponServiceGetCampaignStatsHandler.ServeHTTP(w, r)
+		case CouponServiceGetRemainingProcedure:
+			couponServiceGetRemainingHandler.ServeHTTP(w, r)
+		case CouponServiceRegenerateCouponsProcedure:
+			couponServiceRegenerateCouponsHandler.ServeHTTP(w, r)
+		case CouponServiceSearchCouponsProcedure:
+			couponServiceSearchCouponsHandler.ServeHTTP(w, r)
+		case CouponServiceGetCouponProcedure:
+			couponServiceGetCouponHandler.ServeHTTP(w, r)
+		case CouponServiceValidateCouponProcedure:
+			couponServiceValidateCouponHandler.ServeHTTP(w, r)
+		case CouponServiceGetCouponPayloadProcedure:
+			couponServiceGetCouponPayloadHandler.ServeHTTP(w, r)
+		case CouponServiceListCampaignsProcedure:
+			couponServiceListCampaignsHandler.ServeHTTP(w, r)
+		case CouponServiceListCouponsProcedure:
+			couponServiceListCouponsHandler.ServeHTTP(w, r)
+		case CouponServiceDeleteCampaignProcedure:
+			couponServiceDeleteCampaignHandler.ServeHTTP(w, r)
+		case CouponServiceUpdateCampaignProcedure:
+			couponServiceUpdateCampaignHandler.ServeHTTP(w, r)
+		case CouponServiceRestoreCampaignProcedure:
+			couponServiceRestoreCampaignHandler.ServeHTTP(w, r)
+		case CouponServiceTransferCouponsProcedure:
+			couponServiceTransferCouponsHandler.ServeHTTP(w, r)
+		case CouponServiceRevokeCampaignCouponsProcedure:
+			couponServiceRevokeCampaignCouponsHandler.ServeHTTP(w, r)
+		case CouponServiceDrainCampaignProcedure:
+			couponServiceDrainCampaignHandler.ServeHTTP(w, r)
+		case CouponServicePeekAvailableCouponsProcedure:
+			couponServicePeekAvailableCouponsHandler.ServeHTTP(w, r)
+		case CouponServiceVerifyCampaignCodesProcedure:
+			couponServiceVerifyCampaignCodesHandler.ServeHTTP(w, r)
 		default:
 			http.NotFound(w, r)
 		}
@@ -164,6 +735,14 @@ func (UnimplementedCouponServiceHandler) CreateCampaign(context.Context, *connec
 	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("coupon.v1.CouponService.CreateCampaign is not implemented"))
 }
 
+func (UnimplementedCouponServiceHandler) BatchCreateCampaigns(context.Context, *connect.Request[v1.BatchCreateCampaignsRequest]) (*connect.Response[v1.BatchCreateCampaignsResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("coupon.v1.CouponService.BatchCreateCampaigns is not implemented"))
+}
+
+func (UnimplementedCouponServiceHandler) CreateCampaignStream(context.Context, *connect.Request[v1.CreateCampaignRequest], *connect.ServerStream[v1.CreateCampaignProgress]) error {
+	return connect.NewError(connect.CodeUnimplemented, errors.New("coupon.v1.CouponService.CreateCampaignStream is not implemented"))
+}
+
 func (UnimplementedCouponServiceHandler) GetCampaign(context.Context, *connect.Request[v1.GetCampaignRequest]) (*connect.Response[v1.GetCampaignResponse], error) {
 	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("coupon.v1.CouponService.GetCampaign is not implemented"))
 }
@@ -171,3 +750,75 @@ func (UnimplementedCouponServiceHandler) GetCampaign(context.Context, *connect.R
 func (UnimplementedCouponServiceHandler) IssueCoupon(context.Context, *connect.Request[v1.IssueCouponRequest]) (*connect.Response[v1.IssueCouponResponse], error) {
 	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("coupon.v1.CouponService.IssueCoupon is not implemented"))
 }
+
+func (UnimplementedCouponServiceHandler) IssueBatch(context.Context, *connect.Request[v1.IssueBatchRequest]) (*connect.Response[v1.IssueBatchResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("coupon.v1.CouponService.IssueBatch is not implemented"))
+}
+
+func (UnimplementedCouponServiceHandler) GetCampaignStats(context.Context, *connect.Request[v1.GetCampaignStatsRequest]) (*connect.Response[v1.GetCampaignStatsResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("coupon.v1.CouponService.GetCampaignStats is not implemented"))
+}
+
+func (UnimplementedCouponServiceHandler) GetRemaining(context.Context, *connect.Request[v1.GetRemainingRequest]) (*connect.Response[v1.GetRemainingResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("coupon.v1.CouponService.GetRemaining is not implemented"))
+}
+
+func (UnimplementedCouponServiceHandler) RegenerateCoupons(context.Context, *connect.Request[v1.RegenerateCouponsRequest]) (*connect.Response[v1.RegenerateCouponsResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("coupon.v1.CouponService.RegenerateCoupons is not implemented"))
+}
+
+func (UnimplementedCouponServiceHandler) SearchCoupons(context.Context, *connect.Request[v1.SearchCouponsRequest]) (*connect.Response[v1.SearchCouponsResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("coupon.v1.CouponService.SearchCoupons is not implemented"))
+}
+
+func (UnimplementedCouponServiceHandler) GetCoupon(context.Context, *connect.Request[v1.GetCouponRequest]) (*connect.Response[v1.GetCouponResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("coupon.v1.CouponService.GetCoupon is not implemented"))
+}
+
+func (UnimplementedCouponServiceHandler) ValidateCoupon(context.Context, *connect.Request[v1.ValidateCouponRequest]) (*connect.Response[v1.ValidateCouponResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("coupon.v1.CouponService.ValidateCoupon is not implemented"))
+}
+
+func (UnimplementedCouponServiceHandler) GetCouponPayload(context.Context, *connect.Request[v1.GetCouponPayloadRequest]) (*connect.Response[v1.GetCouponPayloadResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("coupon.v1.CouponService.GetCouponPayload is not implemented"))
+}
+
+func (UnimplementedCouponServiceHandler) ListCampaigns(context.Context, *connect.Request[v1.ListCampaignsRequest]) (*connect.Response[v1.ListCampaignsResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("coupon.v1.CouponService.ListCampaigns is not implemented"))
+}
+
+func (UnimplementedCouponServiceHandler) ListCoupons(context.Context, *connect.Request[v1.ListCouponsRequest]) (*connect.Response[v1.ListCouponsResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("coupon.v1.CouponService.ListCoupons is not implemented"))
+}
+
+func (UnimplementedCouponServiceHandler) DeleteCampaign(context.Context, *connect.Request[v1.DeleteCampaignRequest]) (*connect.Response[v1.DeleteCampaignResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("coupon.v1.CouponService.DeleteCampaign is not implemented"))
+}
+
+func (UnimplementedCouponServiceHandler) UpdateCampaign(context.Context, *connect.Request[v1.UpdateCampaignRequest]) (*connect.Response[v1.UpdateCampaignResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("coupon.v1.CouponService.UpdateCampaign is not implemented"))
+}
+
+func (UnimplementedCouponServiceHandler) RestoreCampaign(context.Context, *connect.Request[v1.RestoreCampaignRequest]) (*connect.Response[v1.RestoreCampaignResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("coupon.v1.CouponService.RestoreCampaign is not implemented"))
+}
+
+func (UnimplementedCouponServiceHandler) TransferCoupons(context.Context, *connect.Request[v1.TransferCouponsRequest]) (*connect.Response[v1.TransferCouponsResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("coupon.v1.CouponService.TransferCoupons is not implemented"))
+}
+
+func (UnimplementedCouponServiceHandler) RevokeCampaignCoupons(context.Context, *connect.Request[v1.RevokeCampaignCouponsRequest]) (*connect.Response[v1.RevokeCampaignCouponsResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("coupon.v1.CouponService.RevokeCampaignCoupons is not implemented"))
+}
+
+func (UnimplementedCouponServiceHandler) DrainCampaign(context.Context, *connect.Request[v1.DrainCampaignRequest]) (*connect.Response[v1.DrainCampaignResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("coupon.v1.CouponService.DrainCampaign is not implemented"))
+}
+
+func (UnimplementedCouponServiceHandler) PeekAvailableCoupons(context.Context, *connect.Request[v1.PeekAvailableCouponsRequest]) (*connect.Response[v1.PeekAvailableCouponsResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("coupon.v1.CouponService.PeekAvailableCoupons is not implemented"))
+}
+
+func (UnimplementedCouponServiceHandler) VerifyCampaignCodes(context.Context, *connect.Request[v1.VerifyCampaignCodesRequest]) (*connect.Response[v1.VerifyCampaignCodesResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("coupon.v1.CouponService.VerifyCampaignCodes is not implemented"))
+}
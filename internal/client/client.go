@@ -0,0 +1,145 @@
+// Package client builds CouponService clients with the transport settings
+// the service is tuned for, so the command line tools share one code path.
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"connectrpc.com/connect"
+	"golang.org/x/net/http2"
+
+	"github.com/kkkkikiki/coupon/gen/coupon/v1/couponv1connect"
+)
+
+const (
+	// DefaultBaseURL is the address of a local server
+	DefaultBaseURL = "http://localhost"
+	// DefaultTimeout bounds each call, including reading the response
+	DefaultTimeout = 30 * time.Second
+	// defaultMaxIdleConns is the HTTP/1.1 connection pool size
+	defaultMaxIdleConns = 100
+)
+
+// Options configure NewClient. The zero value talks the Connect protocol
+// with protobuf to a local server.
+type Options struct {
+	BaseURL  string // Default DefaultBaseURL
+	Protocol string // "connect" (default), "grpc" or "grpcweb"
+	Codec    string // "proto" (default) or "json"
+
+	// Timeout bounds each call; default DefaultTimeout
+	Timeout time.Duration
+	// MaxIdleConns sizes the HTTP/1.1 connection pool; unused with gRPC,
+	// which multiplexes calls over HTTP/2. Default 100.
+	MaxIdleConns int
+
+	Interceptors []connect.Interceptor
+}
+
+// Client is a CouponService client together with the HTTP client it sends
+// requests with
+type Client struct {
+	couponv1connect.CouponServiceClient
+
+	HTTPClient *http.Client
+
+	baseURL  string
+	protocol []connect.ClientOption
+}
+
+// NewClient creates a client from opts
+func NewClient(opts Options) (*Client, error) {
+	if opts.BaseURL == "" {
+		opts.BaseURL = DefaultBaseURL
+	}
+	if opts.Protocol == "" {
+		opts.Protocol = "connect"
+	}
+	if opts.Codec == "" {
+		opts.Codec = "proto"
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultTimeout
+	}
+	if opts.MaxIdleConns <= 0 {
+		opts.MaxIdleConns = defaultMaxIdleConns
+	}
+
+	protocol, err := protocolOptions(opts.Protocol, opts.Codec)
+	if err != nil {
+		return nil, err
+	}
+	c := &Client{
+		HTTPClient: &http.Client{
+			Transport: newTransport(opts.Protocol, opts.MaxIdleConns),
+			Timeout:   opts.Timeout,
+		},
+		baseURL:  opts.BaseURL,
+		protocol: protocol,
+	}
+	return c.WithInterceptors(opts.Interceptors...), nil
+}
+
+// WithInterceptors returns a client sharing c's connections whose calls run
+// through interceptors instead of c's
+func (c *Client) WithInterceptors(interceptors ...connect.Interceptor) *Client {
+	opts := c.protocol
+	if len(interceptors) > 0 {
+		opts = append(opts[:len(opts):len(opts)], connect.WithInterceptors(interceptors...))
+	}
+	return &Client{
+		CouponServiceClient: couponv1connect.NewCouponServiceClient(c.HTTPClient, c.baseURL, opts...),
+		HTTPClient:          c.HTTPClient,
+		baseURL:             c.baseURL,
+		protocol:            c.protocol,
+	}
+}
+
+// protocolOptions returns the client options selecting the wire protocol
+// ("connect", "grpc" or "grpcweb") and codec ("proto" or "json")
+func protocolOptions(protocol, codec string) ([]connect.ClientOption, error) {
+	var opts []connect.ClientOption
+	switch protocol {
+	case "connect":
+	case "grpc":
+		opts = append(opts, connect.WithGRPC())
+	case "grpcweb":
+		opts = append(opts, connect.WithGRPCWeb())
+	default:
+		return nil, fmt.Errorf("protocol must be connect, grpc or grpcweb, got %q", protocol)
+	}
+
+	switch codec {
+	case "proto":
+	case "json":
+		opts = append(opts, connect.WithProtoJSON())
+	default:
+		return nil, fmt.Errorf("codec must be proto or json, got %q", codec)
+	}
+	return opts, nil
+}
+
+// newTransport returns the HTTP transport for protocol. gRPC needs HTTP/2,
+// which the server speaks in cleartext (h2c); the other protocols keep using
+// a pool of HTTP/1.1 connections.
+func newTransport(protocol string, maxIdleConns int) http.RoundTripper {
+	if protocol == "grpc" {
+		return &http2.Transport{
+			AllowHTTP: true,
+			DialTLSContext: func(ctx context.Context, network, addr string, _ *tls.Config) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, network, addr)
+			},
+		}
+	}
+	return &http.Transport{
+		MaxIdleConns:        maxIdleConns,
+		MaxIdleConnsPerHost: maxIdleConns,
+		IdleConnTimeout:     90 * time.Second,
+	}
+}
@@ -0,0 +1,104 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/google/uuid"
+
+	couponv1 "github.com/kkkkikiki/coupon/gen/coupon/v1"
+)
+
+const (
+	// retryBaseDelay is the backoff before the first retry
+	retryBaseDelay = 50 * time.Millisecond
+	// retryMaxDelay caps the backoff between two attempts
+	retryMaxDelay = 2 * time.Second
+)
+
+// IssueAndRetry issues a coupon, retrying transient failures up to retries
+// times with Backoff. Without an idempotency key in msg, one is generated so
+// a retry after a lost response can't issue a second coupon.
+func (c *Client) IssueAndRetry(ctx context.Context, msg *couponv1.IssueCouponRequest, retries int) (*connect.Response[couponv1.IssueCouponResponse], error) {
+	if retries > 0 && msg.IdempotencyKey == "" {
+		msg.IdempotencyKey = uuid.NewString()
+	}
+
+	for attempt := 0; ; attempt++ {
+		resp, err := c.IssueCoupon(ctx, connect.NewRequest(msg))
+		if err == nil || attempt >= retries || !Retryable(err) {
+			return resp, err
+		}
+
+		timer := time.NewTimer(Backoff(attempt, RetryDelay(err)))
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, err
+		}
+	}
+}
+
+// Retryable reports whether a failed call may succeed when repeated.
+// Sold-out campaigns never recover, so only rate limits, identified by their
+// RetryInfo hint, are retried among ResourceExhausted errors.
+func Retryable(err error) bool {
+	switch connect.CodeOf(err) {
+	case connect.CodeUnavailable, connect.CodeDeadlineExceeded, connect.CodeAborted:
+		return true
+	case connect.CodeResourceExhausted:
+		return RetryDelay(err) > 0
+	default:
+		return false
+	}
+}
+
+// Backoff returns the wait before retry number attempt (0-based): full
+// jitter over an exponentially growing window, but never shorter than the
+// server's retry hint
+func Backoff(attempt int, hint time.Duration) time.Duration {
+	window := min(retryBaseDelay<<attempt, retryMaxDelay)
+	return max(rand.N(window)+1, hint)
+}
+
+// RetryDelay extracts the RetryInfo hint from a rate-limit error.
+// Errors without a hint (e.g. sold out) return 0.
+func RetryDelay(err error) time.Duration {
+	var connectErr *connect.Error
+	if !errors.As(err, &connectErr) {
+		return 0
+	}
+	for _, detail := range connectErr.Details() {
+		msg, err := detail.Value()
+		if err != nil {
+			continue
+		}
+		if info, ok := msg.(*couponv1.RetryInfo); ok {
+			return info.GetRetryDelay().AsDuration()
+		}
+	}
+	return 0
+}
+
+// IsSoldOut reports whether err says the campaign has no coupons left to
+// issue, as opposed to a rate limit that clears up over time
+func IsSoldOut(err error) bool {
+	var connectErr *connect.Error
+	if !errors.As(err, &connectErr) || connectErr.Code() != connect.CodeResourceExhausted {
+		return false
+	}
+	for _, detail := range connectErr.Details() {
+		msg, err := detail.Value()
+		if err != nil {
+			continue
+		}
+		if _, ok := msg.(*couponv1.SoldOutInfo); ok {
+			return true
+		}
+	}
+	return false
+}
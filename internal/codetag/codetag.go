@@ -0,0 +1,110 @@
+// Package codetag embeds an obfuscated campaign ID in coupon codes, so a code
+// can be routed to its campaign's shard or partition without a lookup.
+//
+// The tag is the last TagLength characters of a code, always drawn from the
+// Crockford base32 alphabet whatever the campaign's code format, so it can be
+// decoded without knowing the campaign. It holds the 32-bit campaign ID and an
+// 8-bit check value, scrambled by a fixed Feistel permutation so consecutive
+// campaigns don't get similar-looking tags. The permutation is unkeyed: it
+// hides the ID from casual inspection but is not a secret.
+package codetag
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"strings"
+)
+
+const (
+	// TagLength is the number of characters the tag takes at the end of a
+	// code
+	TagLength = 8
+
+	// alphabet is Crockford's base32 alphabet, without I, L, O and U
+	alphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+	// halfBits is the width of each Feistel half; the tag holds 2*halfBits
+	// bits
+	halfBits = 20
+	halfMask = 1<<halfBits - 1
+	rounds   = 4
+)
+
+// Tag returns the tag embedding campaignID. Campaign IDs must be between 1
+// and 2^32-1.
+func Tag(campaignID int64) (string, error) {
+	if campaignID < 1 || campaignID > math.MaxUint32 {
+		return "", fmt.Errorf("campaign ID %d can't be embedded in a code tag", campaignID)
+	}
+
+	v := permute(uint64(campaignID)<<8 | uint64(check(campaignID)))
+	var tag [TagLength]byte
+	for i := TagLength - 1; i >= 0; i-- {
+		tag[i] = alphabet[v&31]
+		v >>= 5
+	}
+	return string(tag[:]), nil
+}
+
+// DecodeCampaign returns the campaign ID embedded in the tag at the end of
+// code. ok is false when the code doesn't end in a valid tag; about 1 in 256
+// codes without a tag that end in TagLength base32 characters decode anyway,
+// so callers must still check the coupon exists in the decoded campaign.
+func DecodeCampaign(code string) (campaignID int64, ok bool) {
+	if len(code) < TagLength {
+		return 0, false
+	}
+	var v uint64
+	for _, c := range []byte(code[len(code)-TagLength:]) {
+		digit := strings.IndexByte(alphabet, c)
+		if digit < 0 {
+			return 0, false
+		}
+		v = v<<5 | uint64(digit)
+	}
+
+	v = unpermute(v)
+	campaignID = int64(v >> 8)
+	if campaignID < 1 || byte(v) != check(campaignID) {
+		return 0, false
+	}
+	return campaignID, true
+}
+
+// check returns the check value stored next to a campaign ID
+func check(campaignID int64) byte {
+	var buf [9]byte
+	buf[0] = 'c'
+	binary.BigEndian.PutUint64(buf[1:], uint64(campaignID))
+	sum := sha256.Sum256(buf[:])
+	return sum[0]
+}
+
+// round is the Feistel round function
+func round(r int, half uint64) uint64 {
+	var buf [5]byte
+	buf[0] = byte(r)
+	binary.BigEndian.PutUint32(buf[1:], uint32(half))
+	sum := sha256.Sum256(buf[:])
+	return uint64(binary.BigEndian.Uint32(sum[:4])) & halfMask
+}
+
+// permute scrambles a 40-bit value
+func permute(v uint64) uint64 {
+	left, right := v>>halfBits, v&halfMask
+	for r := 0; r < rounds; r++ {
+		left, right = right, left^round(r, right)
+	}
+	return left<<halfBits | right
+}
+
+// unpermute inverts permute
+func unpermute(v uint64) uint64 {
+	left, right := v>>halfBits, v&halfMask
+	for r := rounds - 1; r >= 0; r-- {
+		left, right = right^round(r, left), left
+	}
+	return left<<halfBits | right
+}
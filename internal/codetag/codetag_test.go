@@ -0,0 +1,121 @@
+package codetag
+
+import (
+	"math"
+	"math/rand/v2"
+	"strings"
+	"testing"
+)
+
+// encode renders a 40-bit value as a tag, like Tag does after permuting
+func encode(v uint64) string {
+	var tag [TagLength]byte
+	for i := TagLength - 1; i >= 0; i-- {
+		tag[i] = alphabet[v&31]
+		v >>= 5
+	}
+	return string(tag[:])
+}
+
+func TestTagRoundTrip(t *testing.T) {
+	ids := []int64{1, 2, 255, 256, 1 << 24, math.MaxUint32 - 1, math.MaxUint32}
+	for id := int64(3); id <= 10000; id++ {
+		ids = append(ids, id)
+	}
+	rng := rand.New(rand.NewPCG(1, 2))
+	for range 10000 {
+		ids = append(ids, rng.Int64N(math.MaxUint32)+1)
+	}
+
+	seen := make(map[string]int64, len(ids))
+	for _, id := range ids {
+		tag, err := Tag(id)
+		if err != nil {
+			t.Fatalf("Tag(%d): %v", id, err)
+		}
+		if len(tag) != TagLength || strings.Trim(tag, alphabet) != "" {
+			t.Fatalf("Tag(%d) = %q, want %d base32 characters", id, tag, TagLength)
+		}
+		if other, ok := seen[tag]; ok && other != id {
+			t.Fatalf("campaigns %d and %d share tag %q", other, id, tag)
+		}
+		seen[tag] = id
+
+		// The tag decodes whatever precedes it
+		for _, code := range []string{tag, "가나다" + tag, "PROMO-" + tag} {
+			if got, ok := DecodeCampaign(code); !ok || got != id {
+				t.Fatalf("DecodeCampaign(%q) = %d, %v; want %d", code, got, ok, id)
+			}
+		}
+	}
+}
+
+func TestTagRejectsOutOfRangeIDs(t *testing.T) {
+	for _, id := range []int64{0, -1, math.MaxUint32 + 1, math.MaxInt64} {
+		if tag, err := Tag(id); err == nil {
+			t.Errorf("Tag(%d) = %q, want an error", id, tag)
+		}
+	}
+}
+
+func TestDecodeCampaignRejectsBadCheck(t *testing.T) {
+	for _, id := range []int64{1, 42, 123456, math.MaxUint32} {
+		good := uint64(id)<<8 | uint64(check(id))
+		if got, ok := DecodeCampaign(encode(permute(good))); !ok || got != id {
+			t.Fatalf("DecodeCampaign of a well-formed tag = %d, %v; want %d", got, ok, id)
+		}
+		for bit := range 8 {
+			corrupted := good ^ 1<<bit
+			if got, ok := DecodeCampaign(encode(permute(corrupted))); ok {
+				t.Errorf("campaign %d with check bit %d flipped decoded as %d", id, bit, got)
+			}
+		}
+	}
+
+	// Campaign 0 can't be tagged, whatever its check value
+	zero := uint64(check(0))
+	if got, ok := DecodeCampaign(encode(permute(zero))); ok {
+		t.Errorf("campaign 0 decoded as %d", got)
+	}
+}
+
+func TestDecodeCampaignRejectsMalformedCodes(t *testing.T) {
+	tag, err := Tag(42)
+	if err != nil {
+		t.Fatalf("Tag: %v", err)
+	}
+	for _, code := range []string{
+		"",
+		tag[1:],                          // too short
+		strings.ToLower(tag),             // lowercase isn't in the alphabet
+		tag[:TagLength-1] + "I",          // nor is I
+		tag[:TagLength-1] + "가",          // nor Hangul
+		strings.Repeat("0", TagLength-1), // too short again
+	} {
+		if got, ok := DecodeCampaign(code); ok {
+			t.Errorf("DecodeCampaign(%q) = %d, want no campaign", code, got)
+		}
+	}
+}
+
+func TestDecodeCampaignFalsePositiveRate(t *testing.T) {
+	// Untagged codes that happen to end in base32 characters decode when
+	// their check byte matches by chance, about 1 in 256
+	const codes = 200000
+	rng := rand.New(rand.NewPCG(3, 4))
+	var decoded int
+	var code [TagLength]byte
+	for range codes {
+		for i := range code {
+			code[i] = alphabet[rng.IntN(len(alphabet))]
+		}
+		if _, ok := DecodeCampaign(string(code[:])); ok {
+			decoded++
+		}
+	}
+
+	rate := float64(decoded) / codes
+	if rate < 0.5/256 || rate > 1.5/256 {
+		t.Errorf("%d of %d untagged codes decoded (%.5f), want about 1/256", decoded, codes, rate)
+	}
+}
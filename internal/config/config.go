@@ -3,6 +3,9 @@ package config
 import (
 	"context"
 	"fmt"
+	"net/url"
+	"os"
+	"strings"
 
 	"github.com/sethvargo/go-envconfig"
 )
@@ -15,6 +18,12 @@ type Config struct {
 	// Database configuration
 	Database DatabaseConfig `env:",prefix=DB_"`
 
+	// DatabaseURL is a full PostgreSQL connection URL as provided by most
+	// hosting platforms. When set it is used verbatim instead of the DB_*
+	// connection fields, which must then be left unset; DB_* pool settings
+	// still apply.
+	DatabaseURL string `env:"DATABASE_URL" secret:"true"`
+
 	// Application configuration
 	App AppConfig `env:",prefix=APP_"`
 }
@@ -25,6 +34,15 @@ type ServerConfig struct {
 	Host         string `env:"HOST,default=0.0.0.0"`
 	ReadTimeout  int    `env:"READ_TIMEOUT,default=30"`  // seconds
 	WriteTimeout int    `env:"WRITE_TIMEOUT,default=30"` // seconds
+
+	// ShutdownTimeout bounds graceful shutdown; match it to the orchestrator's
+	// termination grace period
+	ShutdownTimeout int `env:"SHUTDOWN_TIMEOUT,default=30"` // seconds
+
+	// Per-message size limits for RPCs so no single request or response can
+	// exhaust memory
+	MaxReadBytes int `env:"MAX_READ_BYTES,default=4194304"`  // 4MiB
+	MaxSendBytes int `env:"MAX_SEND_BYTES,default=33554432"` // 32MiB, fits GetCampaign for large campaigns
 }
 
 // DatabaseConfig holds PostgreSQL configuration
@@ -32,18 +50,168 @@ type DatabaseConfig struct {
 	Host     string `env:"HOST,default=localhost"`
 	Port     string `env:"PORT,default=5432"`
 	User     string `env:"USER,default=postgres"`
-	Password string `env:"PASSWORD,default=postgres"`
+	Password string `env:"PASSWORD,default=postgres" secret:"true"`
 	Name     string `env:"NAME,default=coupon_system"`
 	SSLMode  string `env:"SSL_MODE,default=disable"`
 	MaxConns int    `env:"MAX_CONNS,default=25"`
 	MinConns int    `env:"MIN_CONNS,default=5"`
+
+	ConnMaxLifetime int `env:"CONN_MAX_LIFETIME,default=3600"` // seconds, 0 keeps connections forever
+	ConnMaxIdleTime int `env:"CONN_MAX_IDLE_TIME,default=0"`   // seconds, 0 keeps idle connections forever
+
+	// SlowQueryThreshold logs repository calls slower than this many
+	// milliseconds as JSON warnings (0 disables it)
+	SlowQueryThreshold int `env:"SLOW_QUERY_THRESHOLD,default=0"`
+
+	// Background connectivity check feeding /readyz (HEALTH_CHECK_INTERVAL=0 disables it)
+	HealthCheckInterval  int  `env:"HEALTH_CHECK_INTERVAL,default=5"`      // seconds between pings
+	HealthCheckResetPool bool `env:"HEALTH_CHECK_RESET_POOL,default=true"` // drop idle connections after a failed ping
+
+	// Circuit breaker around DB access (BREAKER_FAILURES=0 disables it)
+	BreakerFailures    int `env:"BREAKER_FAILURES,default=5"`      // consecutive failures before opening
+	BreakerOpenTimeout int `env:"BREAKER_OPEN_TIMEOUT,default=10"` // seconds to stay open before probing
+	BreakerHalfOpenMax int `env:"BREAKER_HALF_OPEN_MAX,default=1"` // probe requests allowed while half-open
+
+	// url is the DATABASE_URL in use, if any
+	url string
 }
 
+// connectionEnvs are the DB_* variables replaced by DATABASE_URL
+var connectionEnvs = []string{"DB_HOST", "DB_PORT", "DB_USER", "DB_PASSWORD", "DB_NAME", "DB_SSL_MODE"}
+
 // AppConfig holds application-specific configuration
 type AppConfig struct {
 	Environment string `env:"ENVIRONMENT,default=development"`
 	LogLevel    string `env:"LOG_LEVEL,default=info"`
 	Debug       bool   `env:"DEBUG,default=false"`
+
+	// PlanSampleRate is the fraction of coupon reservations whose query plan
+	// is logged at debug level. Only applied when Debug is set, since each
+	// sample runs an extra EXPLAIN.
+	PlanSampleRate float64 `env:"PLAN_SAMPLE_RATE,default=0.01"`
+
+	// MetricsEnabled controls whether Prometheus metrics are recorded and
+	// the /metrics endpoint is served
+	MetricsEnabled bool `env:"METRICS_ENABLED,default=true"`
+
+	// IssueConcurrencyRatio sizes the global cap on concurrent issuance calls
+	// as a multiple of DB_MAX_CONNS; excess calls fail fast with Unavailable.
+	// 0 disables the cap.
+	IssueConcurrencyRatio float64 `env:"ISSUE_CONCURRENCY_RATIO,default=2"`
+
+	// CreateConcurrency caps the campaign creations (which generate and
+	// insert every coupon up front) running at once; excess calls fail fast
+	// with ResourceExhausted. 0 disables the cap.
+	CreateConcurrency int64 `env:"CREATE_CONCURRENCY,default=2"`
+
+	// ObservabilityRequired makes a failed metrics setup abort startup.
+	// When false, the service logs a warning and runs without metrics.
+	ObservabilityRequired bool `env:"OBSERVABILITY_REQUIRED,default=false"`
+
+	// ActivationWebhookURL receives a POST with a "campaign.started" event
+	// when a campaign reaches its start date. Empty disables the webhook.
+	ActivationWebhookURL string `env:"ACTIVATION_WEBHOOK_URL"`
+
+	// IdempotencyKeyTTL is how long, in seconds, an IssueCoupon idempotency
+	// key replays its coupon (default 24h). Older keys fail with
+	// FailedPrecondition and are pruned in the background. 0 keeps keys
+	// forever.
+	IdempotencyKeyTTL int `env:"IDEMPOTENCY_KEY_TTL,default=86400"`
+
+	// DefaultCouponTTL is how long, in seconds, an issued coupon stays valid
+	// when it has no explicit expiry. 0 means coupons never expire.
+	DefaultCouponTTL int `env:"DEFAULT_COUPON_TTL,default=0"`
+
+	// ArchiveAfter is how long, in seconds, after its last issuance a
+	// campaign whose coupons are all redeemed, expired or revoked has them
+	// moved from coupons to archived_coupons in the background. 0 disables
+	// archival.
+	ArchiveAfter int `env:"ARCHIVE_AFTER,default=0"`
+
+	// ExportCodes controls how coupon codes appear in the issuance export:
+	// "hash" (SHA-256 only), "omit" or "raw" (code and hash)
+	ExportCodes string `env:"EXPORT_CODES,default=hash"`
+
+	// CodeStorage is how coupon codes are stored: "raw" (default) or
+	// "hashed", which stores only an HMAC of each code keyed by CodeSecret
+	// so a database leak doesn't expose live coupons. Enable hashed storage
+	// on an empty database; it can't read raw codes stored before.
+	CodeStorage string `env:"CODE_STORAGE,default=raw"`
+
+	// CodeSecret keys code generation and code hashes in hashed storage.
+	// Required with CODE_STORAGE=hashed and must never change afterwards.
+	CodeSecret string `env:"CODE_SECRET" secret:"true"`
+
+	// MinCodeEntropyBits is the minimum number of bits of work needed to
+	// guess a valid code of a campaign by trying random codes, given its code
+	// format and coupon count. Formats below it are rejected (0 disables the
+	// check).
+	MinCodeEntropyBits float64 `env:"MIN_CODE_ENTROPY_BITS,default=20"`
+
+	// CodeNamespace is the scope in which coupon codes are unique: "global"
+	// (default) or "campaign". It must match the coupons primary key, see
+	// scripts/code_namespace_campaign.sql. In campaign mode coupon lookups
+	// by code require a campaign ID.
+	CodeNamespace string `env:"CODE_NAMESPACE,default=global"`
+
+	// NativeHistograms exposes issuance latency as a Prometheus native
+	// histogram (requires Prometheus 2.40+ with native histograms enabled)
+	NativeHistograms bool `env:"NATIVE_HISTOGRAMS,default=false"`
+
+	// AdminAPIKey must be sent in X-Admin-Key to call admin RPCs and
+	// endpoints. Empty disables admin authentication (development only).
+	AdminAPIKey string `env:"ADMIN_API_KEY" secret:"true"`
+
+	// CouponSigningSecret keys the HMAC-SHA256 signature of coupon payload
+	// tokens verified offline by scanners. Empty disables GetCouponPayload.
+	CouponSigningSecret string `env:"COUPON_SIGNING_SECRET" secret:"true"`
+
+	// MaxPageSize caps page_size of list RPCs; larger values are clamped
+	MaxPageSize int `env:"MAX_PAGE_SIZE,default=100"`
+
+	// Largest batches a single request may ask for; larger requests are
+	// rejected with InvalidArgument before reaching the handler
+	MaxIssueBatchQuantity int32 `env:"MAX_ISSUE_BATCH_QUANTITY,default=100"`
+	MaxTransferCodes      int   `env:"MAX_TRANSFER_CODES,default=10000"`
+	MaxBatchCampaigns     int   `env:"MAX_BATCH_CAMPAIGNS,default=100"`
+	MaxPeekLimit          int32 `env:"MAX_PEEK_LIMIT,default=100"`
+	// MaxRequestCoupons caps the coupons one CreateCampaign or
+	// CreateCampaignStream request generates, and their total over the
+	// campaigns of a BatchCreateCampaigns request
+	MaxRequestCoupons int64 `env:"MAX_REQUEST_COUPONS,default=1000000"`
+
+	// UserCooldown is the minimum number of seconds between two coupons
+	// issued to the same user across all campaigns (0 disables it)
+	UserCooldown int `env:"USER_COOLDOWN,default=0"`
+
+	// PerCampaignRPS limits IssueCoupon requests per campaign per second for
+	// campaigns without their own max_issue_rps (0 disables it);
+	// PerCampaignBurst is the token bucket size
+	PerCampaignRPS   float64 `env:"PER_CAMPAIGN_RPS,default=0"`
+	PerCampaignBurst int     `env:"PER_CAMPAIGN_BURST,default=1"`
+
+	// LookupRPS limits coupon lookups by code (ValidateCoupon, GetCoupon,
+	// GetCouponPayload) per client per second to stop code brute forcing (0
+	// disables it); LookupBurst is the token bucket size. When a campaign
+	// sees more than LookupFailureThreshold lookups of unknown codes within a
+	// minute, its lookups cost 4 tokens until the minute is over (0 disables
+	// spike detection).
+	LookupRPS              float64 `env:"LOOKUP_RPS,default=5"`
+	LookupBurst            int     `env:"LOOKUP_BURST,default=20"`
+	LookupFailureThreshold int     `env:"LOOKUP_FAILURE_THRESHOLD,default=100"`
+
+	// ClientIPHeader names a header carrying the client IP, set by a trusted
+	// proxy (e.g. X-Real-IP). Empty identifies clients by connection address.
+	ClientIPHeader string `env:"CLIENT_IP_HEADER"`
+
+	// RemainingCacheTTL is how long GetRemaining serves a cached count, in
+	// milliseconds (0 disables caching)
+	RemainingCacheTTL int `env:"REMAINING_CACHE_TTL,default=1000"`
+
+	// RemainingMetricCampaigns is the number of most recently started
+	// campaigns whose remaining count is exported per campaign on scrape
+	// (0 disables the metric)
+	RemainingMetricCampaigns int `env:"REMAINING_METRIC_CAMPAIGNS,default=20"`
 }
 
 // Load loads configuration from environment variables
@@ -52,11 +220,90 @@ func Load(ctx context.Context) (*Config, error) {
 	if err := envconfig.Process(ctx, &cfg); err != nil {
 		return nil, fmt.Errorf("failed to process environment config: %w", err)
 	}
+	switch cfg.App.ExportCodes {
+	case "hash", "omit", "raw":
+	default:
+		return nil, fmt.Errorf("APP_EXPORT_CODES must be hash, omit or raw, got %q", cfg.App.ExportCodes)
+	}
+	switch cfg.App.CodeNamespace {
+	case "global", "campaign":
+	default:
+		return nil, fmt.Errorf("APP_CODE_NAMESPACE must be global or campaign, got %q", cfg.App.CodeNamespace)
+	}
+	switch cfg.App.CodeStorage {
+	case "raw":
+	case "hashed":
+		if cfg.App.CodeSecret == "" {
+			return nil, fmt.Errorf("APP_CODE_SECRET is required when APP_CODE_STORAGE=hashed")
+		}
+	default:
+		return nil, fmt.Errorf("APP_CODE_STORAGE must be raw or hashed, got %q", cfg.App.CodeStorage)
+	}
+	if cfg.App.MinCodeEntropyBits < 0 {
+		return nil, fmt.Errorf("APP_MIN_CODE_ENTROPY_BITS must not be negative")
+	}
+	if cfg.App.CreateConcurrency < 0 {
+		return nil, fmt.Errorf("APP_CREATE_CONCURRENCY must not be negative")
+	}
+	if cfg.App.PlanSampleRate < 0 || cfg.App.PlanSampleRate > 1 {
+		return nil, fmt.Errorf("APP_PLAN_SAMPLE_RATE must be between 0 and 1")
+	}
+	if cfg.App.ArchiveAfter < 0 {
+		return nil, fmt.Errorf("APP_ARCHIVE_AFTER must not be negative")
+	}
+	if cfg.App.MaxPageSize < 1 {
+		return nil, fmt.Errorf("APP_MAX_PAGE_SIZE must be at least 1")
+	}
+	if cfg.App.MaxIssueBatchQuantity < 1 || cfg.App.MaxTransferCodes < 1 || cfg.App.MaxBatchCampaigns < 1 || cfg.App.MaxPeekLimit < 1 || cfg.App.MaxRequestCoupons < 1 {
+		return nil, fmt.Errorf("APP_MAX_ISSUE_BATCH_QUANTITY, APP_MAX_TRANSFER_CODES, APP_MAX_BATCH_CAMPAIGNS, APP_MAX_PEEK_LIMIT and APP_MAX_REQUEST_COUPONS must be at least 1")
+	}
+	if cfg.DatabaseURL != "" {
+		for _, name := range connectionEnvs {
+			if _, ok := os.LookupEnv(name); ok {
+				return nil, fmt.Errorf("DATABASE_URL and %s are mutually exclusive", name)
+			}
+		}
+		if err := cfg.Database.useURL(cfg.DatabaseURL); err != nil {
+			return nil, err
+		}
+	}
 	return &cfg, nil
 }
 
-// GetDatabaseURL returns the PostgreSQL connection URL
+// useURL makes the config connect with a DATABASE_URL, filling the
+// connection fields from it so they describe the database actually used
+func (c *DatabaseConfig) useURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("invalid DATABASE_URL: %w", err)
+	}
+	if u.Scheme != "postgres" && u.Scheme != "postgresql" {
+		return fmt.Errorf("DATABASE_URL must use the postgres:// or postgresql:// scheme")
+	}
+
+	c.url = rawURL
+	c.Host = u.Hostname()
+	c.Port = u.Port()
+	if c.Port == "" {
+		c.Port = "5432"
+	}
+	c.User = u.User.Username()
+	c.Password, _ = u.User.Password()
+	c.Name = strings.TrimPrefix(u.Path, "/")
+	// lib/pq defaults to sslmode=require when the URL doesn't say
+	c.SSLMode = u.Query().Get("sslmode")
+	if c.SSLMode == "" {
+		c.SSLMode = "require"
+	}
+	return nil
+}
+
+// GetDatabaseURL returns the PostgreSQL connection URL: the DATABASE_URL
+// when set, otherwise a DSN built from the connection fields
 func (c *DatabaseConfig) GetDatabaseURL() string {
+	if c.url != "" {
+		return c.url
+	}
 	return fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
 		c.Host, c.Port, c.User, c.Password, c.Name, c.SSLMode)
 }
@@ -75,3 +322,12 @@ func (c *AppConfig) IsDevelopment() bool {
 func (c *AppConfig) IsProduction() bool {
 	return c.Environment == "production"
 }
+
+// MaxConcurrentIssues returns the global issuance concurrency cap derived
+// from the DB pool size, or 0 when the cap is disabled
+func (c *Config) MaxConcurrentIssues() int64 {
+	if c.App.IssueConcurrencyRatio <= 0 {
+		return 0
+	}
+	return max(int64(float64(c.Database.MaxConns)*c.App.IssueConcurrencyRatio), 1)
+}
@@ -0,0 +1,52 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+)
+
+// redactedValue replaces secret values in redacted output
+const redactedValue = "[REDACTED]"
+
+// secretNameHints mark env names that are treated as secrets even without an
+// explicit `secret:"true"` tag, so new credentials are redacted by default
+var secretNameHints = []string{"PASSWORD", "SECRET", "KEY", "TOKEN", "CREDENTIAL"}
+
+// Redacted returns a copy of the config that is safe to expose, with every
+// secret field replaced by a placeholder. A field is secret when it is tagged
+// `secret:"true"` or its env name looks like a credential.
+func (c *Config) Redacted() *Config {
+	redacted := *c
+	redactStruct(reflect.ValueOf(&redacted).Elem())
+	return &redacted
+}
+
+func redactStruct(v reflect.Value) {
+	t := v.Type()
+	for i := 0; i < v.NumField(); i++ {
+		field, value := t.Field(i), v.Field(i)
+
+		switch value.Kind() {
+		case reflect.Struct:
+			redactStruct(value)
+		case reflect.String:
+			if value.String() != "" && isSecretField(field) {
+				value.SetString(redactedValue)
+			}
+		}
+	}
+}
+
+func isSecretField(field reflect.StructField) bool {
+	if field.Tag.Get("secret") == "true" {
+		return true
+	}
+
+	name := strings.ToUpper(strings.Split(field.Tag.Get("env"), ",")[0])
+	for _, hint := range secretNameHints {
+		if strings.Contains(name, hint) {
+			return true
+		}
+	}
+	return false
+}
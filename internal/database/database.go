@@ -28,7 +28,8 @@ func NewDB(ctx context.Context, cfg *config.Config) (*DB, error) {
 	// Configure connection pool
 	postgres.SetMaxOpenConns(cfg.Database.MaxConns)
 	postgres.SetMaxIdleConns(cfg.Database.MinConns)
-	postgres.SetConnMaxLifetime(time.Hour)
+	postgres.SetConnMaxLifetime(time.Duration(cfg.Database.ConnMaxLifetime) * time.Second)
+	postgres.SetConnMaxIdleTime(time.Duration(cfg.Database.ConnMaxIdleTime) * time.Second)
 
 	// Test PostgreSQL connection
 	if err := postgres.PingContext(ctx); err != nil {
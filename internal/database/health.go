@@ -0,0 +1,105 @@
+package database
+
+import (
+	"context"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/kkkkikiki/coupon/internal/metrics"
+)
+
+// healthCheckTimeout bounds a single background ping
+const healthCheckTimeout = 2 * time.Second
+
+// HealthChecker periodically pings PostgreSQL and tracks whether the
+// database is reachable
+type HealthChecker struct {
+	db        *DB
+	interval  time.Duration
+	resetPool bool
+	idleConns int
+
+	healthy atomic.Bool
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+// NewHealthChecker creates a health checker for db. The database is assumed
+// healthy until the first failed ping.
+func NewHealthChecker(db *DB, interval time.Duration, resetPool bool, idleConns int) *HealthChecker {
+	h := &HealthChecker{
+		db:        db,
+		interval:  interval,
+		resetPool: resetPool,
+		idleConns: idleConns,
+	}
+	h.healthy.Store(true)
+	return h
+}
+
+// Start launches the background ping loop
+func (h *HealthChecker) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	h.cancel = cancel
+
+	h.wg.Add(1)
+	go func() {
+		defer h.wg.Done()
+
+		ticker := time.NewTicker(h.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				h.check(ctx)
+			}
+		}
+	}()
+}
+
+// Stop stops the ping loop and waits for it to exit
+func (h *HealthChecker) Stop() {
+	if h.cancel == nil {
+		return
+	}
+	h.cancel()
+	h.wg.Wait()
+}
+
+// Healthy reports whether the latest ping succeeded
+func (h *HealthChecker) Healthy() bool {
+	return h.healthy.Load()
+}
+
+func (h *HealthChecker) check(ctx context.Context) {
+	pingCtx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+
+	err := h.db.Postgres.PingContext(pingCtx)
+	if ctx.Err() != nil {
+		return
+	}
+	up := err == nil
+	metrics.RecordDBUp(up)
+
+	wasUp := h.healthy.Swap(up)
+	switch {
+	case wasUp && !up:
+		log.Printf("PostgreSQL health check failed: %v", err)
+		if h.resetPool {
+			// Dropping the idle pool makes the next requests dial fresh
+			// connections instead of failing on dead ones
+			h.db.Postgres.SetMaxIdleConns(0)
+		}
+	case !wasUp && up:
+		log.Println("PostgreSQL connection recovered")
+		if h.resetPool {
+			h.db.Postgres.SetMaxIdleConns(h.idleConns)
+		}
+	}
+}
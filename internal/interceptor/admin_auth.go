@@ -0,0 +1,67 @@
+package interceptor
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"net/http"
+
+	"connectrpc.com/connect"
+)
+
+// AdminKeyHeader is the header carrying the admin API key
+const AdminKeyHeader = "X-Admin-Key"
+
+type adminKey struct{}
+
+// IsAdmin reports whether the request in ctx carried a valid admin API key
+// (always true while admin authentication is disabled)
+func IsAdmin(ctx context.Context) bool {
+	admin, _ := ctx.Value(adminKey{}).(bool)
+	return admin
+}
+
+// NewAdminAuthInterceptor rejects calls to the given admin procedures unless
+// they carry the admin API key. An empty apiKey disables the check. For all
+// procedures, the outcome of the key check is available through IsAdmin so
+// handlers can gate admin-only fields.
+func NewAdminAuthInterceptor(apiKey string, procedures ...string) connect.UnaryInterceptorFunc {
+	admin := make(map[string]bool, len(procedures))
+	for _, procedure := range procedures {
+		admin[procedure] = true
+	}
+
+	return func(next connect.UnaryFunc) connect.UnaryFunc {
+		return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+			if req.Spec().IsClient {
+				return next(ctx, req)
+			}
+
+			valid := validAdminKey(apiKey, req.Header().Get(AdminKeyHeader))
+			if admin[req.Spec().Procedure] && !valid {
+				return nil, connect.NewError(connect.CodeUnauthenticated, errors.New("admin API key required"))
+			}
+			return next(context.WithValue(ctx, adminKey{}, valid), req)
+		}
+	}
+}
+
+// RequireAdmin wraps a plain HTTP handler with the same admin key check
+func RequireAdmin(apiKey string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !validAdminKey(apiKey, r.Header.Get(AdminKeyHeader)) {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"status":"error","message":"admin API key required"}`))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// validAdminKey compares keys in constant time; an unset apiKey accepts all
+func validAdminKey(apiKey, provided string) bool {
+	if apiKey == "" {
+		return true
+	}
+	return subtle.ConstantTimeCompare([]byte(apiKey), []byte(provided)) == 1
+}
@@ -0,0 +1,123 @@
+package interceptor
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+
+	"connectrpc.com/connect"
+	"golang.org/x/sync/semaphore"
+
+	"github.com/kkkkikiki/coupon/internal/metrics"
+)
+
+// ConcurrencyLimit caps the number of guarded calls running at once. Calls
+// beyond the limit are rejected immediately instead of queuing. One limit can
+// guard connect procedures through Interceptor and plain HTTP handlers through
+// Acquire, so every entry point shares the same cap. A limit below 1 disables
+// the check.
+type ConcurrencyLimit struct {
+	limit   int64
+	sem     *semaphore.Weighted
+	active  atomic.Int64
+	code    connect.Code
+	message string
+	record  func(int64)
+}
+
+// newConcurrencyLimit creates a limit rejecting excess calls with code and
+// message, reporting the number of admitted calls to record
+func newConcurrencyLimit(limit int64, code connect.Code, message string, record func(int64)) *ConcurrencyLimit {
+	return &ConcurrencyLimit{
+		limit:   limit,
+		sem:     semaphore.NewWeighted(max(limit, 1)),
+		code:    code,
+		message: message,
+		record:  record,
+	}
+}
+
+// NewIssueConcurrencyLimit caps the number of issuance calls running at once
+// across all campaigns. Calls beyond the limit are rejected immediately with
+// Unavailable instead of queuing on the DB pool until their deadline expires.
+// A limit below 1 disables the check.
+func NewIssueConcurrencyLimit(limit int64) *ConcurrencyLimit {
+	return newConcurrencyLimit(limit, connect.CodeUnavailable,
+		"too many concurrent issuance requests", metrics.RecordIssueConcurrency)
+}
+
+// NewCreateConcurrencyLimit caps the number of campaign creations running at
+// once. Each generates and inserts all of its coupons up front, so a few
+// large creations at once can exhaust memory; calls beyond the limit are
+// rejected immediately with ResourceExhausted. A limit below 1 disables the
+// check.
+func NewCreateConcurrencyLimit(limit int64) *ConcurrencyLimit {
+	return newConcurrencyLimit(limit, connect.CodeResourceExhausted,
+		"too many concurrent campaign creations", metrics.RecordCreateConcurrency)
+}
+
+// Acquire takes a slot, returning the function releasing it, or a connect
+// error when the limit is reached
+func (l *ConcurrencyLimit) Acquire() (func(), error) {
+	if l.limit < 1 {
+		return func() {}, nil
+	}
+	if !l.sem.TryAcquire(1) {
+		return nil, connect.NewError(l.code, errors.New(l.message))
+	}
+	l.record(l.active.Add(1))
+	return func() {
+		l.record(l.active.Add(-1))
+		l.sem.Release(1)
+	}, nil
+}
+
+// Interceptor applies the limit to the given unary and streaming procedures
+func (l *ConcurrencyLimit) Interceptor(procedures ...string) connect.Interceptor {
+	guarded := make(map[string]bool, len(procedures))
+	for _, procedure := range procedures {
+		guarded[procedure] = true
+	}
+	return &concurrencyInterceptor{limit: l, guarded: guarded}
+}
+
+type concurrencyInterceptor struct {
+	limit   *ConcurrencyLimit
+	guarded map[string]bool
+}
+
+func (i *concurrencyInterceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		if req.Spec().IsClient || !i.guarded[req.Spec().Procedure] {
+			return next(ctx, req)
+		}
+
+		release, err := i.limit.Acquire()
+		if err != nil {
+			return nil, err
+		}
+		defer release()
+
+		return next(ctx, req)
+	}
+}
+
+func (i *concurrencyInterceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return next
+}
+
+func (i *concurrencyInterceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		if !i.guarded[conn.Spec().Procedure] {
+			return next(ctx, conn)
+		}
+
+		release, err := i.limit.Acquire()
+		if err != nil {
+			return err
+		}
+		defer release()
+
+		return next(ctx, conn)
+	}
+}
@@ -0,0 +1,35 @@
+package interceptor
+
+import (
+	"net/http"
+	"sync/atomic"
+
+	"github.com/kkkkikiki/coupon/internal/metrics"
+)
+
+// InFlightTracker counts HTTP requests currently being served
+type InFlightTracker struct {
+	count atomic.Int64
+}
+
+// NewInFlightTracker creates a new in-flight request tracker
+func NewInFlightTracker() *InFlightTracker {
+	return &InFlightTracker{}
+}
+
+// Wrap returns a handler that counts requests while next serves them
+func (t *InFlightTracker) Wrap(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		metrics.RecordInFlightRequests(t.count.Add(1))
+		defer func() {
+			metrics.RecordInFlightRequests(t.count.Add(-1))
+		}()
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Count returns the number of requests currently in flight
+func (t *InFlightTracker) Count() int64 {
+	return t.count.Load()
+}
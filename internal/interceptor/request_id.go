@@ -0,0 +1,99 @@
+package interceptor
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/google/uuid"
+)
+
+// RequestIDHeader is the header carrying the request ID
+const RequestIDHeader = "X-Request-Id"
+
+type requestIDKey struct{}
+
+// WithRequestID returns a copy of ctx carrying the request ID
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestIDFromContext returns the request ID stored in ctx, if any
+func RequestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
+
+// NewRequestIDInterceptor propagates an X-Request-Id for every request,
+// unary and streaming. The incoming header is reused when present, otherwise
+// a UUID is generated. The ID is stored in the context, logged with
+// failures, and echoed back in the response headers (or error metadata).
+func NewRequestIDInterceptor() connect.Interceptor {
+	return &requestIDInterceptor{}
+}
+
+type requestIDInterceptor struct{}
+
+func (i *requestIDInterceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		if req.Spec().IsClient {
+			return next(ctx, req)
+		}
+
+		requestID := requestIDOf(req.Header())
+		ctx = WithRequestID(ctx, requestID)
+
+		start := time.Now()
+		res, err := next(ctx, req)
+		if err != nil {
+			return nil, failedRequest(requestID, req.Spec().Procedure, start, err)
+		}
+
+		res.Header().Set(RequestIDHeader, requestID)
+		return res, nil
+	}
+}
+
+func (i *requestIDInterceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return next
+}
+
+func (i *requestIDInterceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		requestID := requestIDOf(conn.RequestHeader())
+		ctx = WithRequestID(ctx, requestID)
+		// Response headers go out with the first message, so set it up front
+		conn.ResponseHeader().Set(RequestIDHeader, requestID)
+
+		start := time.Now()
+		if err := next(ctx, conn); err != nil {
+			return failedRequest(requestID, conn.Spec().Procedure, start, err)
+		}
+		return nil
+	}
+}
+
+// requestIDOf returns the request ID sent in header, or a new one
+func requestIDOf(header http.Header) string {
+	if requestID := header.Get(RequestIDHeader); requestID != "" {
+		return requestID
+	}
+	return uuid.NewString()
+}
+
+// failedRequest logs a request that failed with err after starting at start,
+// and returns err as a connect error carrying the request ID
+func failedRequest(requestID, procedure string, start time.Time, err error) *connect.Error {
+	log.Printf("request failed request_id=%s procedure=%s code=%s duration=%s error=%q",
+		requestID, procedure, connect.CodeOf(err), time.Since(start), err)
+
+	var connectErr *connect.Error
+	if !errors.As(err, &connectErr) {
+		connectErr = connect.NewError(connect.CodeOf(err), err)
+	}
+	connectErr.Meta().Set(RequestIDHeader, requestID)
+	return connectErr
+}
@@ -0,0 +1,128 @@
+package interceptor
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"connectrpc.com/connect"
+
+	couponv1 "github.com/kkkkikiki/coupon/gen/coupon/v1"
+	"github.com/kkkkikiki/coupon/gen/coupon/v1/couponv1connect"
+)
+
+// requestIDHandler reports the request ID its handlers see in the context,
+// as the remaining count of GetRemaining and in the progress stream, and
+// fails for campaign 0
+type requestIDHandler struct {
+	couponv1connect.UnimplementedCouponServiceHandler
+	seen chan string
+}
+
+func (h *requestIDHandler) GetRemaining(ctx context.Context, req *connect.Request[couponv1.GetRemainingRequest]) (*connect.Response[couponv1.GetRemainingResponse], error) {
+	h.seen <- RequestIDFromContext(ctx)
+	if req.Msg.CampaignId == 0 {
+		return nil, connect.NewError(connect.CodeNotFound, errors.New("campaign not found"))
+	}
+	return connect.NewResponse(&couponv1.GetRemainingResponse{}), nil
+}
+
+func (h *requestIDHandler) CreateCampaignStream(ctx context.Context, req *connect.Request[couponv1.CreateCampaignRequest], stream *connect.ServerStream[couponv1.CreateCampaignProgress]) error {
+	h.seen <- RequestIDFromContext(ctx)
+	if req.Msg.AvailableCoupons == 0 {
+		return connect.NewError(connect.CodeInvalidArgument, errors.New("available_coupons is required"))
+	}
+	return stream.Send(&couponv1.CreateCampaignProgress{Generated: req.Msg.AvailableCoupons})
+}
+
+func newRequestIDTestClient(t *testing.T) (couponv1connect.CouponServiceClient, *requestIDHandler) {
+	t.Helper()
+	handler := &requestIDHandler{seen: make(chan string, 1)}
+	mux := http.NewServeMux()
+	mux.Handle(couponv1connect.NewCouponServiceHandler(handler, connect.WithInterceptors(NewRequestIDInterceptor())))
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return couponv1connect.NewCouponServiceClient(server.Client(), server.URL), handler
+}
+
+func TestRequestIDUnary(t *testing.T) {
+	client, handler := newRequestIDTestClient(t)
+	ctx := context.Background()
+
+	req := connect.NewRequest(&couponv1.GetRemainingRequest{CampaignId: 1})
+	req.Header().Set(RequestIDHeader, "req-1")
+	res, err := client.GetRemaining(ctx, req)
+	if err != nil {
+		t.Fatalf("GetRemaining: %v", err)
+	}
+	if seen := <-handler.seen; seen != "req-1" {
+		t.Errorf("handler saw request ID %q, want req-1", seen)
+	}
+	if got := res.Header().Get(RequestIDHeader); got != "req-1" {
+		t.Errorf("response %s = %q, want req-1", RequestIDHeader, got)
+	}
+
+	// A request without an ID gets a generated one
+	res, err = client.GetRemaining(ctx, connect.NewRequest(&couponv1.GetRemainingRequest{CampaignId: 1}))
+	if err != nil {
+		t.Fatalf("GetRemaining: %v", err)
+	}
+	seen := <-handler.seen
+	if seen == "" || res.Header().Get(RequestIDHeader) != seen {
+		t.Errorf("generated request ID %q, echoed %q", seen, res.Header().Get(RequestIDHeader))
+	}
+
+	req = connect.NewRequest(&couponv1.GetRemainingRequest{CampaignId: 0})
+	req.Header().Set(RequestIDHeader, "req-2")
+	_, err = client.GetRemaining(ctx, req)
+	<-handler.seen
+	var connectErr *connect.Error
+	if !errors.As(err, &connectErr) || connectErr.Code() != connect.CodeNotFound {
+		t.Fatalf("GetRemaining error = %v, want NotFound", err)
+	}
+	if got := connectErr.Meta().Get(RequestIDHeader); got != "req-2" {
+		t.Errorf("error %s = %q, want req-2", RequestIDHeader, got)
+	}
+}
+
+func TestRequestIDStreaming(t *testing.T) {
+	client, handler := newRequestIDTestClient(t)
+	ctx := context.Background()
+
+	req := connect.NewRequest(&couponv1.CreateCampaignRequest{AvailableCoupons: 10})
+	req.Header().Set(RequestIDHeader, "stream-1")
+	stream, err := client.CreateCampaignStream(ctx, req)
+	if err != nil {
+		t.Fatalf("CreateCampaignStream: %v", err)
+	}
+	for stream.Receive() {
+	}
+	if err := stream.Err(); err != nil {
+		t.Fatalf("stream: %v", err)
+	}
+	if seen := <-handler.seen; seen != "stream-1" {
+		t.Errorf("handler saw request ID %q, want stream-1", seen)
+	}
+	if got := stream.ResponseHeader().Get(RequestIDHeader); got != "stream-1" {
+		t.Errorf("response %s = %q, want stream-1", RequestIDHeader, got)
+	}
+
+	req = connect.NewRequest(&couponv1.CreateCampaignRequest{})
+	req.Header().Set(RequestIDHeader, "stream-2")
+	stream, err = client.CreateCampaignStream(ctx, req)
+	if err != nil {
+		t.Fatalf("CreateCampaignStream: %v", err)
+	}
+	for stream.Receive() {
+	}
+	<-handler.seen
+	var connectErr *connect.Error
+	if !errors.As(stream.Err(), &connectErr) || connectErr.Code() != connect.CodeInvalidArgument {
+		t.Fatalf("stream error = %v, want InvalidArgument", stream.Err())
+	}
+	if got := stream.ResponseHeader().Get(RequestIDHeader); got != "stream-2" {
+		t.Errorf("response %s = %q, want stream-2", RequestIDHeader, got)
+	}
+}
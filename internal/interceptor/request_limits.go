@@ -0,0 +1,131 @@
+package interceptor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"connectrpc.com/connect"
+
+	couponv1 "github.com/kkkkikiki/coupon/gen/coupon/v1"
+)
+
+// RequestLimits are the largest amounts of work a single request may ask
+// for through its quantity and repeated fields
+type RequestLimits struct {
+	IssueBatchQuantity int32 // IssueBatch quantity
+	TransferCodes      int   // TransferCoupons codes
+	BatchCampaigns     int   // BatchCreateCampaigns campaigns
+	PeekLimit          int32 // PeekAvailableCoupons limit
+	// Coupons generated by one campaign creation request, summed over the
+	// campaigns of a BatchCreateCampaigns request
+	CampaignCoupons int64
+}
+
+// NewRequestLimitsInterceptor rejects requests exceeding limits with
+// InvalidArgument before the handler runs, so handlers only check the lower
+// bounds of these fields. The encoded body is already capped while decoding
+// (SERVER_MAX_READ_BYTES); this bounds the work a decoded request can cause.
+// Messages received by streaming handlers are checked as they are read.
+func NewRequestLimitsInterceptor(limits RequestLimits) connect.Interceptor {
+	return &requestLimitsInterceptor{limits: limits}
+}
+
+type requestLimitsInterceptor struct {
+	limits RequestLimits
+}
+
+func (i *requestLimitsInterceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		if req.Spec().IsClient {
+			return next(ctx, req)
+		}
+		if err := i.limits.check(req.Any()); err != nil {
+			return nil, err
+		}
+		return next(ctx, req)
+	}
+}
+
+func (i *requestLimitsInterceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return next
+}
+
+func (i *requestLimitsInterceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		return next(ctx, &limitedHandlerConn{StreamingHandlerConn: conn, limits: i.limits})
+	}
+}
+
+// limitedHandlerConn checks each received message against limits
+type limitedHandlerConn struct {
+	connect.StreamingHandlerConn
+	limits RequestLimits
+}
+
+func (c *limitedHandlerConn) Receive(msg any) error {
+	if err := c.StreamingHandlerConn.Receive(msg); err != nil {
+		return err
+	}
+	return c.limits.check(msg)
+}
+
+// check returns the violation of limits by msg, if any
+func (l RequestLimits) check(msg any) error {
+	switch msg := msg.(type) {
+	case *couponv1.IssueBatchRequest:
+		if msg.Quantity > l.IssueBatchQuantity {
+			return limitError("quantity", "must be at most %d", l.IssueBatchQuantity)
+		}
+	case *couponv1.TransferCouponsRequest:
+		if len(msg.Codes) > l.TransferCodes {
+			return limitError("codes", "must have at most %d entries", l.TransferCodes)
+		}
+	case *couponv1.CreateCampaignRequest:
+		if campaignCoupons(msg) > l.CampaignCoupons {
+			return limitError("available_coupons", "must be at most %d", l.CampaignCoupons)
+		}
+	case *couponv1.BatchCreateCampaignsRequest:
+		if len(msg.Campaigns) > l.BatchCampaigns {
+			return limitError("campaigns", "must have at most %d entries", l.BatchCampaigns)
+		}
+		var total int64
+		for _, campaign := range msg.Campaigns {
+			total += campaignCoupons(campaign)
+		}
+		if total > l.CampaignCoupons {
+			return limitError("campaigns", "must have at most %d coupons in total", l.CampaignCoupons)
+		}
+	case *couponv1.PeekAvailableCouponsRequest:
+		if msg.Limit > l.PeekLimit {
+			return limitError("limit", "must be at most %d", l.PeekLimit)
+		}
+	}
+	return nil
+}
+
+// campaignCoupons returns the number of coupons a campaign creation request
+// asks for: available_coupons, or the sum of its pool counts when larger
+func campaignCoupons(msg *couponv1.CreateCampaignRequest) int64 {
+	var pooled int64
+	for _, pool := range msg.GetPools() {
+		pooled += int64(pool.GetCount())
+	}
+	return max(int64(msg.GetAvailableCoupons()), pooled)
+}
+
+// limitError returns an InvalidArgument error for field with a BadRequest
+// detail, in the form the service reports field violations
+func limitError(field, format string, args ...any) *connect.Error {
+	violation := &couponv1.BadRequest_FieldViolation{
+		Field:       field,
+		Description: fmt.Sprintf(format, args...),
+	}
+	connectErr := connect.NewError(connect.CodeInvalidArgument, errors.New(field+": "+violation.Description))
+	if detail, err := connect.NewErrorDetail(&couponv1.BadRequest{
+		FieldViolations: []*couponv1.BadRequest_FieldViolation{violation},
+	}); err == nil {
+		connectErr.AddDetail(detail)
+	}
+	return connectErr
+}
@@ -0,0 +1,127 @@
+package interceptor
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"connectrpc.com/connect"
+	"google.golang.org/protobuf/proto"
+
+	couponv1 "github.com/kkkkikiki/coupon/gen/coupon/v1"
+)
+
+var testLimits = RequestLimits{
+	IssueBatchQuantity: 100,
+	TransferCodes:      3,
+	BatchCampaigns:     2,
+	PeekLimit:          50,
+	CampaignCoupons:    1000,
+}
+
+func TestRequestLimitsCheck(t *testing.T) {
+	tests := []struct {
+		name  string
+		msg   any
+		field string // "" when the request is within limits
+	}{
+		{"issue batch at the limit", &couponv1.IssueBatchRequest{Quantity: 100}, ""},
+		{"issue batch over the limit", &couponv1.IssueBatchRequest{Quantity: 101}, "quantity"},
+		{"transfer at the limit", &couponv1.TransferCouponsRequest{Codes: make([]string, 3)}, ""},
+		{"transfer over the limit", &couponv1.TransferCouponsRequest{Codes: make([]string, 4)}, "codes"},
+		{"batch create at the limit", &couponv1.BatchCreateCampaignsRequest{Campaigns: make([]*couponv1.CreateCampaignRequest, 2)}, ""},
+		{"batch create over the limit", &couponv1.BatchCreateCampaignsRequest{Campaigns: make([]*couponv1.CreateCampaignRequest, 3)}, "campaigns"},
+		{"peek at the limit", &couponv1.PeekAvailableCouponsRequest{Limit: 50}, ""},
+		{"peek over the limit", &couponv1.PeekAvailableCouponsRequest{Limit: 51}, "limit"},
+		{"create at the limit", &couponv1.CreateCampaignRequest{AvailableCoupons: 1000}, ""},
+		{"create over the limit", &couponv1.CreateCampaignRequest{AvailableCoupons: 1001}, "available_coupons"},
+		{"create with pools over the limit", &couponv1.CreateCampaignRequest{Pools: []*couponv1.CouponPool{
+			{Name: "a", Count: 600}, {Name: "b", Count: 401},
+		}}, "available_coupons"},
+		{"batch create coupons at the limit", &couponv1.BatchCreateCampaignsRequest{Campaigns: []*couponv1.CreateCampaignRequest{
+			{AvailableCoupons: 500}, {AvailableCoupons: 500},
+		}}, ""},
+		{"batch create coupons over the limit", &couponv1.BatchCreateCampaignsRequest{Campaigns: []*couponv1.CreateCampaignRequest{
+			{AvailableCoupons: 500}, {AvailableCoupons: 501},
+		}}, "campaigns"},
+		{"unlimited request", &couponv1.GetRemainingRequest{CampaignId: 1}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := testLimits.check(tt.msg)
+			if tt.field == "" {
+				if err != nil {
+					t.Fatalf("check() = %v, want no error", err)
+				}
+				return
+			}
+
+			var connectErr *connect.Error
+			if !errors.As(err, &connectErr) || connectErr.Code() != connect.CodeInvalidArgument {
+				t.Fatalf("check() = %v, want InvalidArgument", err)
+			}
+			var badRequest *couponv1.BadRequest
+			for _, detail := range connectErr.Details() {
+				if value, err := detail.Value(); err == nil {
+					if br, ok := value.(*couponv1.BadRequest); ok {
+						badRequest = br
+					}
+				}
+			}
+			if badRequest == nil || len(badRequest.FieldViolations) != 1 || badRequest.FieldViolations[0].Field != tt.field {
+				t.Errorf("BadRequest detail = %v, want a violation of %s", badRequest, tt.field)
+			}
+		})
+	}
+}
+
+func TestRequestLimitsInterceptorStopsOversizedRequests(t *testing.T) {
+	var called bool
+	next := connect.UnaryFunc(func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		called = true
+		return connect.NewResponse(&couponv1.IssueBatchResponse{}), nil
+	})
+	handler := NewRequestLimitsInterceptor(testLimits).WrapUnary(next)
+
+	_, err := handler(context.Background(), connect.NewRequest(&couponv1.IssueBatchRequest{Quantity: 1000}))
+	if connect.CodeOf(err) != connect.CodeInvalidArgument {
+		t.Errorf("oversized request = %v, want InvalidArgument", err)
+	}
+	if called {
+		t.Error("oversized request reached the handler")
+	}
+
+	if _, err := handler(context.Background(), connect.NewRequest(&couponv1.IssueBatchRequest{Quantity: 10})); err != nil {
+		t.Errorf("request within limits: %v", err)
+	}
+	if !called {
+		t.Error("request within limits didn't reach the handler")
+	}
+}
+
+// fakeHandlerConn delivers msg to the first Receive
+type fakeHandlerConn struct {
+	connect.StreamingHandlerConn
+	msg *couponv1.CreateCampaignRequest
+}
+
+func (c *fakeHandlerConn) Receive(msg any) error {
+	proto.Merge(msg.(*couponv1.CreateCampaignRequest), c.msg)
+	return nil
+}
+
+func TestRequestLimitsInterceptorChecksStreamedRequests(t *testing.T) {
+	receive := connect.StreamingHandlerFunc(func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		return conn.Receive(&couponv1.CreateCampaignRequest{})
+	})
+	handler := NewRequestLimitsInterceptor(testLimits).WrapStreamingHandler(receive)
+
+	err := handler(context.Background(), &fakeHandlerConn{msg: &couponv1.CreateCampaignRequest{AvailableCoupons: 5000}})
+	if connect.CodeOf(err) != connect.CodeInvalidArgument {
+		t.Errorf("oversized streamed request = %v, want InvalidArgument", err)
+	}
+
+	if err := handler(context.Background(), &fakeHandlerConn{msg: &couponv1.CreateCampaignRequest{AvailableCoupons: 10}}); err != nil {
+		t.Errorf("streamed request within limits: %v", err)
+	}
+}
@@ -0,0 +1,53 @@
+package interceptor
+
+import (
+	"context"
+	"errors"
+
+	"connectrpc.com/connect"
+)
+
+// connectErrorer is implemented by errors that translate themselves to the
+// connect error sent to clients, such as service.ServiceError
+type connectErrorer interface {
+	ConnectError() *connect.Error
+}
+
+// ConnectError returns the connect error err translates to when err, or an
+// error it wraps, implements ConnectError, and err unchanged otherwise
+func ConnectError(err error) error {
+	var translatable connectErrorer
+	if errors.As(err, &translatable) {
+		return translatable.ConnectError()
+	}
+	return err
+}
+
+// NewErrorInterceptor translates handler errors with ConnectError, so
+// handlers can return errors without choosing connect codes. It must be the
+// innermost interceptor for the others to see the translated errors.
+func NewErrorInterceptor() connect.Interceptor {
+	return &errorInterceptor{}
+}
+
+type errorInterceptor struct{}
+
+func (i *errorInterceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		res, err := next(ctx, req)
+		if err != nil && !req.Spec().IsClient {
+			return res, ConnectError(err)
+		}
+		return res, err
+	}
+}
+
+func (i *errorInterceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return next
+}
+
+func (i *errorInterceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		return ConnectError(next(ctx, conn))
+	}
+}
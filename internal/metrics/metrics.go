@@ -1,36 +1,366 @@
 package metrics
 
 import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
 	"github.com/prometheus/client_golang/prometheus"
-	"github.com/prometheus/client_golang/prometheus/promauto"
 )
 
-var (
+// active holds the collectors recording goes to. It is nil, and every
+// Record* function a no-op, until RegisterWith succeeds.
+var active atomic.Pointer[Collectors]
+
+// Collectors are the service's collectors, bound to the registry they were
+// registered with
+type Collectors struct {
+	registerer prometheus.Registerer
+
 	// IssueCouponDuration tracks the latency of coupon issuance
-	IssueCouponDuration = promauto.NewHistogramVec(
-		prometheus.HistogramOpts{
-			Name: "coupon_issue_duration_seconds",
-			Help: "Duration of coupon issuance requests in seconds",
-			Buckets: []float64{
-				0.001, // 1ms
-				0.005, // 5ms
-				0.01,  // 10ms
-				0.025, // 25ms
-				0.05,  // 50ms
-				0.1,   // 100ms
-				0.25,  // 250ms
-				0.5,   // 500ms
-				1.0,   // 1s
-				2.5,   // 2.5s
-				5.0,   // 5s
-				10.0,  // 10s
-			},
-		},
-		[]string{"status"}, // success or failure
-	)
-)
+	IssueCouponDuration *prometheus.HistogramVec
+
+	// DBBreakerState exposes the DB circuit breaker state
+	DBBreakerState prometheus.Gauge
+
+	// GetRemainingRequests counts GetRemaining calls to expose polling pressure
+	GetRemainingRequests *prometheus.CounterVec
+
+	// SlowQueries counts repository calls exceeding the slow query threshold
+	SlowQueries *prometheus.CounterVec
+
+	// InFlightRequests is the number of HTTP requests currently being served
+	InFlightRequests prometheus.Gauge
+
+	// IssueConcurrency is the number of issuance calls currently admitted by
+	// the global concurrency limit
+	IssueConcurrency prometheus.Gauge
+
+	// CreateConcurrency is the number of campaign creations currently
+	// admitted by the creation concurrency limit
+	CreateConcurrency prometheus.Gauge
+
+	// OverissuanceDetected counts campaigns found with more issued coupons
+	// than they ever had. It must always read zero.
+	OverissuanceDetected prometheus.Counter
+
+	// CodesGenerated counts generated coupon codes
+	CodesGenerated prometheus.Counter
+
+	// GenerationDuration tracks how long one batch of coupon codes takes to
+	// generate
+	GenerationDuration prometheus.Histogram
+
+	// LookupFailures counts coupon lookups by code that found no coupon
+	LookupFailures prometheus.Counter
+
+	// LookupThrottled counts coupon lookups rejected by the per-client limit
+	LookupThrottled prometheus.Counter
+
+	// CouponsArchived counts coupons moved to the archive table
+	CouponsArchived prometheus.Counter
+
+	// DBUp reports the result of the latest background DB ping
+	DBUp prometheus.Gauge
+}
+
+// newCollectors builds a fresh set of collectors. nativeHistograms switches
+// the issuance latency histogram to native histogram buckets.
+func newCollectors(reg prometheus.Registerer, nativeHistograms bool) *Collectors {
+	return &Collectors{
+		registerer:          reg,
+		IssueCouponDuration: newIssueCouponDuration(nativeHistograms),
+		DBBreakerState: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Name: "coupon_db_breaker_state",
+				Help: "State of the DB circuit breaker (0=closed, 1=half-open, 2=open)",
+			},
+		),
+		GetRemainingRequests: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "coupon_get_remaining_requests_total",
+				Help: "Number of GetRemaining requests by cache result",
+			},
+			[]string{"cache"}, // hit or miss
+		),
+		SlowQueries: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "coupon_slow_queries_total",
+				Help: "Number of repository calls slower than the configured threshold",
+			},
+			[]string{"operation"},
+		),
+		InFlightRequests: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Name: "coupon_http_inflight_requests",
+				Help: "Number of HTTP requests currently being served",
+			},
+		),
+		IssueConcurrency: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Name: "coupon_issue_concurrency",
+				Help: "Number of issuance requests currently holding a global concurrency slot",
+			},
+		),
+		CreateConcurrency: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Name: "coupon_create_concurrency",
+				Help: "Number of campaign creations currently holding a concurrency slot",
+			},
+		),
+		OverissuanceDetected: prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Name: "coupon_overissuance_detected_total",
+				Help: "Number of sampled checks that found a campaign with more issued coupons than its total",
+			},
+		),
+		CodesGenerated: prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Name: "coupon_codes_generated_total",
+				Help: "Number of coupon codes generated",
+			},
+		),
+		GenerationDuration: prometheus.NewHistogram(
+			prometheus.HistogramOpts{
+				Name:    "coupon_generation_duration_seconds",
+				Help:    "Time taken to generate one batch of coupon codes",
+				Buckets: []float64{0.001, 0.005, 0.01, 0.05, 0.1, 0.5, 1, 2.5, 5, 10},
+			},
+		),
+		LookupFailures: prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Name: "coupon_lookup_failures_total",
+				Help: "Number of coupon lookups by code that found no coupon",
+			},
+		),
+		LookupThrottled: prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Name: "coupon_lookup_throttled_total",
+				Help: "Number of coupon lookups rejected by the per-client rate limit",
+			},
+		),
+		CouponsArchived: prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Name: "coupon_archived_total",
+				Help: "Number of coupons moved from the coupons table to archived_coupons",
+			},
+		),
+		DBUp: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Name: "coupon_db_up",
+				Help: "Whether the latest background DB ping succeeded (1) or failed (0)",
+			},
+		),
+	}
+}
+
+// issueDurationBuckets are the explicit buckets used for the issuance
+// histogram, and by scrapers that do not negotiate native histograms
+var issueDurationBuckets = []float64{
+	0.001, // 1ms
+	0.005, // 5ms
+	0.01,  // 10ms
+	0.025, // 25ms
+	0.05,  // 50ms
+	0.1,   // 100ms
+	0.25,  // 250ms
+	0.5,   // 500ms
+	1.0,   // 1s
+	2.5,   // 2.5s
+	5.0,   // 5s
+	10.0,  // 10s
+}
+
+// newIssueCouponDuration builds the issuance histogram. With native set, it
+// additionally exposes a native (sparse) histogram whose buckets grow by at
+// most 10% each, so tail percentiles stay accurate without tuning boundaries.
+// Native histograms are only scraped by Prometheus 2.40+ with
+// --enable-feature=native-histograms; older servers keep reading the
+// explicit buckets.
+func newIssueCouponDuration(native bool) *prometheus.HistogramVec {
+	opts := prometheus.HistogramOpts{
+		Name:    "coupon_issue_duration_seconds",
+		Help:    "Duration of coupon issuance requests in seconds",
+		Buckets: issueDurationBuckets,
+	}
+	if native {
+		opts.NativeHistogramBucketFactor = 1.1
+		opts.NativeHistogramMaxBucketNumber = 160
+		opts.NativeHistogramMinResetDuration = time.Hour
+	}
+	return prometheus.NewHistogramVec(opts, []string{"status"}) // success or failure
+}
+
+// Register registers all collectors with the default Prometheus registry and
+// enables recording. It must be called before serving requests; when it is
+// never called, every Record* function is a no-op. nativeHistograms switches
+// the issuance latency histogram to native histogram buckets.
+func Register(nativeHistograms bool) (*Collectors, error) {
+	return RegisterWith(prometheus.DefaultRegisterer, nativeHistograms)
+}
+
+// RegisterWith is Register for a given registry, e.g. a fresh
+// prometheus.NewRegistry() in tests. It returns the collectors bound to reg,
+// which the Record* functions write to from then on.
+//
+// A collector the registry already holds under the same name and labels,
+// e.g. because the service is set up twice in one process, is adopted
+// instead of failing. If any collector fails to register otherwise, the ones
+// registered by this call are removed again and recording is left as it was,
+// so callers may continue without metrics.
+func RegisterWith(reg prometheus.Registerer, nativeHistograms bool) (*Collectors, error) {
+	c := newCollectors(reg, nativeHistograms)
+
+	var registered []prometheus.Collector
+	steps := []func() (prometheus.Collector, error){
+		registerAs(reg, &c.IssueCouponDuration), registerAs(reg, &c.DBBreakerState), registerAs(reg, &c.GetRemainingRequests),
+		registerAs(reg, &c.SlowQueries), registerAs(reg, &c.InFlightRequests), registerAs(reg, &c.IssueConcurrency),
+		registerAs(reg, &c.DBUp), registerAs(reg, &c.OverissuanceDetected), registerAs(reg, &c.CodesGenerated),
+		registerAs(reg, &c.GenerationDuration), registerAs(reg, &c.LookupFailures), registerAs(reg, &c.LookupThrottled),
+		registerAs(reg, &c.CreateConcurrency), registerAs(reg, &c.CouponsArchived),
+	}
+	for _, step := range steps {
+		collector, err := step()
+		if err != nil {
+			for _, r := range registered {
+				reg.Unregister(r)
+			}
+			return nil, fmt.Errorf("failed to register metrics: %w", err)
+		}
+		if collector != nil {
+			registered = append(registered, collector)
+		}
+	}
+	active.Store(c)
+	return c, nil
+}
+
+// registerAs returns a step registering *c with reg. When reg already holds
+// an equivalent collector, *c is replaced by it so recording reaches the
+// registered series, and the step reports no newly registered collector.
+func registerAs[T prometheus.Collector](reg prometheus.Registerer, c *T) func() (prometheus.Collector, error) {
+	return func() (prometheus.Collector, error) {
+		err := reg.Register(*c)
+		if err == nil {
+			return *c, nil
+		}
+		var already prometheus.AlreadyRegisteredError
+		if errors.As(err, &already) {
+			if existing, ok := already.ExistingCollector.(T); ok {
+				*c = existing
+				return nil, nil
+			}
+		}
+		return nil, err
+	}
+}
+
+// Enabled reports whether metrics are being recorded
+func Enabled() bool {
+	return active.Load() != nil
+}
 
 // RecordIssueCouponDuration records the duration of a coupon issuance request
 func RecordIssueCouponDuration(status string, duration float64) {
-	IssueCouponDuration.WithLabelValues(status).Observe(duration)
+	if c := active.Load(); c != nil {
+		c.IssueCouponDuration.WithLabelValues(status).Observe(duration)
+	}
+}
+
+// RecordDBBreakerState records the current DB circuit breaker state
+func RecordDBBreakerState(state int) {
+	if c := active.Load(); c != nil {
+		c.DBBreakerState.Set(float64(state))
+	}
+}
+
+// RecordDBUp records the result of a background DB ping
+func RecordDBUp(up bool) {
+	c := active.Load()
+	if c == nil {
+		return
+	}
+	if up {
+		c.DBUp.Set(1)
+	} else {
+		c.DBUp.Set(0)
+	}
+}
+
+// RecordGetRemaining records a GetRemaining request
+func RecordGetRemaining(cacheHit bool) {
+	c := active.Load()
+	if c == nil {
+		return
+	}
+	if cacheHit {
+		c.GetRemainingRequests.WithLabelValues("hit").Inc()
+	} else {
+		c.GetRemainingRequests.WithLabelValues("miss").Inc()
+	}
+}
+
+// RecordSlowQuery records a repository call exceeding the slow query threshold
+func RecordSlowQuery(operation string) {
+	if c := active.Load(); c != nil {
+		c.SlowQueries.WithLabelValues(operation).Inc()
+	}
+}
+
+// RecordInFlightRequests records the current number of in-flight HTTP requests
+func RecordInFlightRequests(count int64) {
+	if c := active.Load(); c != nil {
+		c.InFlightRequests.Set(float64(count))
+	}
+}
+
+// RecordIssueConcurrency records the number of admitted issuance calls
+func RecordIssueConcurrency(n int64) {
+	if c := active.Load(); c != nil {
+		c.IssueConcurrency.Set(float64(n))
+	}
+}
+
+// RecordCreateConcurrency records the number of admitted campaign creations
+func RecordCreateConcurrency(n int64) {
+	if c := active.Load(); c != nil {
+		c.CreateConcurrency.Set(float64(n))
+	}
+}
+
+// RecordOverissuance records a campaign found to be over-issued
+func RecordOverissuance() {
+	if c := active.Load(); c != nil {
+		c.OverissuanceDetected.Inc()
+	}
+}
+
+// RecordArchived records count coupons moved to the archive table
+func RecordArchived(count int64) {
+	if c := active.Load(); c != nil {
+		c.CouponsArchived.Add(float64(count))
+	}
+}
+
+// RecordCodeGeneration records a generated batch of count coupon codes
+func RecordCodeGeneration(count int, duration float64) {
+	if c := active.Load(); c != nil {
+		c.CodesGenerated.Add(float64(count))
+		c.GenerationDuration.Observe(duration)
+	}
+}
+
+// RecordLookupFailure records a coupon lookup of an unknown code
+func RecordLookupFailure() {
+	if c := active.Load(); c != nil {
+		c.LookupFailures.Inc()
+	}
+}
+
+// RecordLookupThrottled records a coupon lookup rejected by the rate limit
+func RecordLookupThrottled() {
+	if c := active.Load(); c != nil {
+		c.LookupThrottled.Inc()
+	}
 }
@@ -0,0 +1,107 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// registerTest records into a fresh registry for the rest of the test
+func registerTest(tb testing.TB) *Collectors {
+	tb.Helper()
+
+	c, err := RegisterWith(prometheus.NewRegistry(), false)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	tb.Cleanup(func() { active.Store(nil) })
+	return c
+}
+
+// counterValue returns the value of counter
+func counterValue(t *testing.T, counter prometheus.Counter) float64 {
+	t.Helper()
+
+	var m dto.Metric
+	if err := counter.Write(&m); err != nil {
+		t.Fatal(err)
+	}
+	return m.GetCounter().GetValue()
+}
+
+func BenchmarkRecordIssueCouponDuration(b *testing.B) {
+	b.Run("disabled", func(b *testing.B) {
+		active.Store(nil)
+		for i := 0; i < b.N; i++ {
+			RecordIssueCouponDuration("success", 0.01)
+		}
+	})
+
+	b.Run("enabled", func(b *testing.B) {
+		registerTest(b)
+
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			RecordIssueCouponDuration("success", 0.01)
+		}
+	})
+}
+
+func TestRecordWhileDisabled(t *testing.T) {
+	active.Store(nil)
+	allocs := testing.AllocsPerRun(100, func() {
+		RecordIssueCouponDuration("success", 0.01)
+		RecordSlowQuery("CouponRepository.ReserveAvailableCoupon")
+		RecordGetRemaining(true)
+	})
+	if allocs != 0 {
+		t.Errorf("recording with metrics disabled allocated %v times per call", allocs)
+	}
+}
+
+func TestRegisterWithIsolatedRegistries(t *testing.T) {
+	first := registerTest(t)
+	RecordLookupFailure()
+
+	// A second registry gets collectors of its own and takes over recording
+	second := registerTest(t)
+	RecordLookupFailure()
+	RecordLookupFailure()
+
+	if got := counterValue(t, first.LookupFailures); got != 1 {
+		t.Errorf("first registry counted %v lookup failures, want 1", got)
+	}
+	if got := counterValue(t, second.LookupFailures); got != 2 {
+		t.Errorf("second registry counted %v lookup failures, want 2", got)
+	}
+}
+
+func TestRegisterWithAdoptsRegisteredCollectors(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	first, err := RegisterWith(reg, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { active.Store(nil) })
+
+	second, err := RegisterWith(reg, false)
+	if err != nil {
+		t.Fatalf("registering twice with one registry: %v", err)
+	}
+	if second.LookupFailures != first.LookupFailures {
+		t.Error("second registration didn't adopt the registered collector")
+	}
+}
+
+func TestRecordingIsRaceFree(t *testing.T) {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for range 100 {
+			RecordGetRemaining(true)
+		}
+	}()
+	registerTest(t)
+	<-done
+}
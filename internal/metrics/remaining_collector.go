@@ -0,0 +1,84 @@
+package metrics
+
+import (
+	"context"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// remainingCacheTTL is how long a remaining-count query result serves
+	// scrapes before the database is queried again
+	remainingCacheTTL = 10 * time.Second
+	// remainingQueryTimeout bounds the query run during a scrape
+	remainingQueryTimeout = 5 * time.Second
+)
+
+// RemainingFunc returns the remaining coupon count of each campaign to
+// report, keyed by campaign ID
+type RemainingFunc func(ctx context.Context) (map[int64]int64, error)
+
+var remainingDesc = prometheus.NewDesc(
+	"coupon_campaign_remaining_coupons",
+	"Available coupons of active campaigns, computed at scrape time",
+	[]string{"campaign_id"}, nil,
+)
+
+// remainingCollector reports per-campaign remaining counts computed at scrape
+// time. Only the campaigns returned by fetch are exported, so series of
+// ended campaigns disappear instead of lingering as stale gauges.
+type remainingCollector struct {
+	fetch RemainingFunc
+
+	mu        sync.Mutex
+	counts    map[int64]int64
+	fetchedAt time.Time
+}
+
+// RegisterRemainingCollector registers a collector exporting the counts
+// returned by fetch with the registry given to Register. It is a no-op while
+// metrics are disabled.
+func RegisterRemainingCollector(fetch RemainingFunc) error {
+	c := active.Load()
+	if c == nil {
+		return nil
+	}
+	return c.registerer.Register(&remainingCollector{fetch: fetch})
+}
+
+func (c *remainingCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- remainingDesc
+}
+
+func (c *remainingCollector) Collect(ch chan<- prometheus.Metric) {
+	for id, count := range c.load() {
+		ch <- prometheus.MustNewConstMetric(remainingDesc, prometheus.GaugeValue, float64(count), strconv.FormatInt(id, 10))
+	}
+}
+
+// load returns the cached counts, refreshing them when they are older than
+// remainingCacheTTL. Concurrent scrapes share one query. When the query
+// fails the previous counts are served, so a slow database never fails the
+// whole scrape.
+func (c *remainingCollector) load() map[int64]int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if time.Since(c.fetchedAt) < remainingCacheTTL {
+		return c.counts
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), remainingQueryTimeout)
+	defer cancel()
+	counts, err := c.fetch(ctx)
+	if err != nil {
+		log.Printf("Failed to collect remaining coupon counts: %v", err)
+		return c.counts
+	}
+	c.counts, c.fetchedAt = counts, time.Now()
+	return c.counts
+}
@@ -6,18 +6,138 @@ import (
 
 // Campaign represents a coupon campaign in the database
 type Campaign struct {
-	ID               int64     `db:"id" json:"id"`
-	AvailableCoupons int32     `db:"available_coupons" json:"available_coupons"`
-	StartDate        time.Time `db:"start_date" json:"start_date"`
-	CreatedAt        time.Time `db:"created_at" json:"created_at"`
-	UpdatedAt        time.Time `db:"updated_at" json:"updated_at"`
+	ID               int64            `db:"id" json:"id"`
+	AvailableCoupons int32            `db:"available_coupons" json:"available_coupons"`
+	StartDate        time.Time        `db:"start_date" json:"start_date"`
+	CodeAlphabet     string           `db:"code_alphabet" json:"code_alphabet"`
+	CodeLength       int32            `db:"code_length" json:"code_length"`
+	CodePrefix       string           `db:"code_prefix" json:"code_prefix"`
+	CodeRoutingTag   bool             `db:"code_routing_tag" json:"code_routing_tag"`
+	NextCodeIndex    int64            `db:"next_code_index" json:"next_code_index"`     // Next unused coupon index for code generation
+	DiscountValue    int64            `db:"discount_value" json:"discount_value"`       // Value of one coupon in minor currency units
+	Budget           int64            `db:"budget" json:"budget"`                       // Cap on total issued value, 0 = unlimited
+	IssuedValue      int64            `db:"issued_value" json:"issued_value"`           // Total issued value, tracked only when Budget > 0
+	CouponMetadata   Metadata         `db:"coupon_metadata" json:"coupon_metadata"`     // Metadata given to generated coupons
+	PerUserLimit     int32            `db:"per_user_limit" json:"per_user_limit"`       // Max coupons per user, 0 = unlimited
+	MaxIssueRPS      float64          `db:"max_issue_rps" json:"max_issue_rps"`         // Issuance rate limit, 0 = global default
+	ShuffleSeed      *int64           `db:"shuffle_seed" json:"shuffle_seed"`           // Seed of the shuffled issuance order, nil = creation order
+	ReservationOrder ReservationOrder `db:"reservation_order" json:"reservation_order"` // Coupon column available coupons are issued by
+	Slug             *string          `db:"slug" json:"slug,omitempty"`                 // Unique human-readable name, nil when unset
+	DeletedAt        *time.Time       `db:"deleted_at" json:"deleted_at,omitempty"`     // Set when soft-deleted
+	CreatedAt        time.Time        `db:"created_at" json:"created_at"`
+	UpdatedAt        time.Time        `db:"updated_at" json:"updated_at"`
+
+	// Daily issuance window; WindowStart is nil when issuance is always open
+	WindowStart    *int32 `db:"window_start_minute" json:"window_start_minute,omitempty"` // Opening in minutes after midnight
+	WindowEnd      *int32 `db:"window_end_minute" json:"window_end_minute,omitempty"`     // Closing (exclusive), below WindowStart when spanning midnight
+	WindowTimeZone string `db:"window_time_zone" json:"window_time_zone"`                 // IANA time zone of the window
+	WindowDays     int16  `db:"window_days" json:"window_days"`                           // Weekdays the window opens on, bit 0 = Monday, 0 = every day
+}
+
+// ReservationOrder is the coupon column a campaign's available coupons are
+// issued by, as stored in campaigns.reservation_order
+type ReservationOrder string
+
+// Reservation orders. The set must match the CHECK constraint on
+// campaigns.reservation_order.
+const (
+	ReservationOrderCreatedAt ReservationOrder = "created_at" // Oldest first, or shuffled when the campaign has a shuffle seed
+	ReservationOrderExpiresAt ReservationOrder = "expires_at" // Soonest-expiring first, coupons without expiry last
+	ReservationOrderPriority  ReservationOrder = "priority"   // Highest priority first
+)
+
+// CouponGroup holds the attributes shared by coupons generated together
+type CouponGroup struct {
+	Pool      string     `db:"pool"`       // "" when the campaign has no pools
+	Priority  int32      `db:"priority"`   // Higher is issued first by campaigns ordered by priority
+	ExpiresAt *time.Time `db:"expires_at"` // nil gives issued coupons the default expiry
+}
+
+// CouponGroupCount is a number of coupons of one group
+type CouponGroupCount struct {
+	CouponGroup
+	Count int64 `db:"count"`
 }
 
 // Coupon represents an issued coupon in the database
 type Coupon struct {
-	Code       string    `db:"code" json:"code"`
+	Code       string       `db:"code" json:"code"`
+	CampaignID int64        `db:"campaign_id" json:"campaign_id"`
+	Status     CouponStatus `db:"status" json:"status"`
+	IssuedAt   time.Time    `db:"issued_at" json:"issued_at"`
+	ExpiresAt  *time.Time   `db:"expires_at" json:"expires_at"` // nil when the coupon never expires
+	Pool       string       `db:"pool" json:"pool"`             // Pool within the campaign, "" when it has none
+	Metadata   Metadata     `db:"metadata" json:"metadata"`
+	CreatedAt  time.Time    `db:"created_at" json:"created_at"`
+}
+
+// CouponWithCampaign is a coupon joined with the campaign fields needed to
+// redeem it
+type CouponWithCampaign struct {
+	Coupon
+	DiscountValue int64     `db:"discount_value"`
+	StartDate     time.Time `db:"start_date"`
+}
+
+// IssuanceEvent is an issued coupon as exported to analytics
+type IssuanceEvent struct {
+	Code       string       `db:"code"`
+	CampaignID int64        `db:"campaign_id"`
+	Status     CouponStatus `db:"status"`
+	IssuedAt   time.Time    `db:"issued_at"`
+	UserID     *string      `db:"user_id"`
+}
+
+// CodeIndex is a coupon code with the index it was generated from, nil for
+// coupons stored before indexes were recorded
+type CodeIndex struct {
+	Code  string `db:"code"`
+	Index *int64 `db:"code_index"`
+}
+
+// IssuanceCount compares a campaign's issued coupons with its total
+type IssuanceCount struct {
+	CampaignID int64 `db:"campaign_id"`
+	Total      int32 `db:"total"`
+	Issued     int32 `db:"issued"`
+}
+
+// RemainingCount is the number of available coupons of a campaign
+type RemainingCount struct {
+	CampaignID int64 `db:"campaign_id"`
+	Available  int64 `db:"available"`
+}
+
+// IssueIdempotencyKey records the coupon issued for an idempotency key
+type IssueIdempotencyKey struct {
+	Key        string    `db:"idempotency_key" json:"idempotency_key"`
 	CampaignID int64     `db:"campaign_id" json:"campaign_id"`
-	Status     string    `db:"status" json:"status"`        // 'available' or 'issued'
-	IssuedAt   time.Time `db:"issued_at" json:"issued_at"`
+	CouponCode string    `db:"coupon_code" json:"coupon_code"`
 	CreatedAt  time.Time `db:"created_at" json:"created_at"`
 }
+
+// CampaignStats holds coupon counts for a campaign
+type CampaignStats struct {
+	Total     int32 `db:"total" json:"total"`
+	Available int32 `db:"available" json:"available"`
+	Issued    int32 `db:"issued" json:"issued"`
+
+	// Revoked counts issued coupons later revoked, which no longer count as
+	// issued
+	Revoked int32 `db:"revoked" json:"revoked"`
+
+	// RecentlyIssued counts coupons issued within the requested window
+	RecentlyIssued int32 `db:"recently_issued" json:"recently_issued"`
+
+	// Pools breaks the counts down by pool; empty without pools
+	Pools []PoolStats `db:"-" json:"pools,omitempty"`
+}
+
+// PoolStats holds coupon counts for one pool of a campaign
+type PoolStats struct {
+	Pool      string `db:"pool" json:"pool"`
+	Total     int32  `db:"total" json:"total"`
+	Available int32  `db:"available" json:"available"`
+	Issued    int32  `db:"issued" json:"issued"`
+	Revoked   int32  `db:"revoked" json:"revoked"`
+}
@@ -0,0 +1,76 @@
+package model
+
+import (
+	"fmt"
+	"slices"
+)
+
+// CouponStatus is the lifecycle state of a coupon as stored in coupons.status
+type CouponStatus string
+
+// Coupon statuses. The set must match the CHECK constraint on coupons.status.
+const (
+	CouponStatusAvailable CouponStatus = "available"
+	CouponStatusIssued    CouponStatus = "issued"
+	CouponStatusReserved  CouponStatus = "reserved"
+	CouponStatusRedeemed  CouponStatus = "redeemed"
+	CouponStatusExpired   CouponStatus = "expired"
+	CouponStatusRevoked   CouponStatus = "revoked"
+)
+
+// couponTransitions is the coupon state machine: the statuses each status
+// may move to. Redeemed, expired and revoked are final.
+var couponTransitions = map[CouponStatus][]CouponStatus{
+	CouponStatusAvailable: {CouponStatusReserved, CouponStatusIssued},
+	CouponStatusReserved:  {CouponStatusAvailable, CouponStatusIssued},
+	CouponStatusIssued:    {CouponStatusRedeemed, CouponStatusExpired, CouponStatusRevoked},
+}
+
+// transitionVerbs names the action that moves a coupon into a status, for
+// error messages
+var transitionVerbs = map[CouponStatus]string{
+	CouponStatusAvailable: "release",
+	CouponStatusReserved:  "reserve",
+	CouponStatusIssued:    "issue",
+	CouponStatusRedeemed:  "redeem",
+	CouponStatusExpired:   "expire",
+	CouponStatusRevoked:   "revoke",
+}
+
+// ValidTransition reports whether a coupon may move from one status to another
+func ValidTransition(from, to CouponStatus) bool {
+	for _, next := range couponTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// TransitionSources returns the statuses from which a coupon may move to the
+// given status. Repositories use it to guard status updates.
+func TransitionSources(to CouponStatus) []string {
+	var sources []string
+	for from := range couponTransitions {
+		if ValidTransition(from, to) {
+			sources = append(sources, string(from))
+		}
+	}
+	slices.Sort(sources)
+	return sources
+}
+
+// TransitionError reports a status change the state machine doesn't allow
+type TransitionError struct {
+	From CouponStatus
+	To   CouponStatus
+}
+
+func (e *TransitionError) Error() string {
+	article := "a"
+	switch e.From {
+	case CouponStatusAvailable, CouponStatusIssued, CouponStatusExpired:
+		article = "an"
+	}
+	return fmt.Sprintf("cannot %s %s %s coupon", transitionVerbs[e.To], article, e.From)
+}
@@ -0,0 +1,72 @@
+package model
+
+import (
+	"slices"
+	"testing"
+)
+
+var allCouponStatuses = []CouponStatus{
+	CouponStatusAvailable,
+	CouponStatusIssued,
+	CouponStatusReserved,
+	CouponStatusRedeemed,
+	CouponStatusExpired,
+	CouponStatusRevoked,
+}
+
+func TestValidTransition(t *testing.T) {
+	allowed := map[[2]CouponStatus]bool{
+		{CouponStatusAvailable, CouponStatusReserved}: true,
+		{CouponStatusAvailable, CouponStatusIssued}:   true,
+		{CouponStatusReserved, CouponStatusAvailable}: true,
+		{CouponStatusReserved, CouponStatusIssued}:    true,
+		{CouponStatusIssued, CouponStatusRedeemed}:    true,
+		{CouponStatusIssued, CouponStatusExpired}:     true,
+		{CouponStatusIssued, CouponStatusRevoked}:     true,
+	}
+	for _, from := range allCouponStatuses {
+		for _, to := range allCouponStatuses {
+			want := allowed[[2]CouponStatus{from, to}]
+			if got := ValidTransition(from, to); got != want {
+				t.Errorf("ValidTransition(%s, %s) = %v, want %v", from, to, got, want)
+			}
+		}
+	}
+}
+
+func TestTransitionSources(t *testing.T) {
+	tests := []struct {
+		to   CouponStatus
+		want []string
+	}{
+		{CouponStatusAvailable, []string{"reserved"}},
+		{CouponStatusReserved, []string{"available"}},
+		{CouponStatusIssued, []string{"available", "reserved"}},
+		{CouponStatusRedeemed, []string{"issued"}},
+		{CouponStatusExpired, []string{"issued"}},
+		// Only issued coupons can be revoked
+		{CouponStatusRevoked, []string{"issued"}},
+	}
+	for _, tt := range tests {
+		if got := TransitionSources(tt.to); !slices.Equal(got, tt.want) {
+			t.Errorf("TransitionSources(%s) = %v, want %v", tt.to, got, tt.want)
+		}
+	}
+}
+
+func TestTransitionError(t *testing.T) {
+	tests := []struct {
+		err  TransitionError
+		want string
+	}{
+		{TransitionError{From: CouponStatusAvailable, To: CouponStatusRevoked}, "cannot revoke an available coupon"},
+		{TransitionError{From: CouponStatusRedeemed, To: CouponStatusRevoked}, "cannot revoke a redeemed coupon"},
+		{TransitionError{From: CouponStatusExpired, To: CouponStatusRedeemed}, "cannot redeem an expired coupon"},
+		{TransitionError{From: CouponStatusIssued, To: CouponStatusIssued}, "cannot issue an issued coupon"},
+	}
+	for _, tt := range tests {
+		if got := tt.err.Error(); got != tt.want {
+			t.Errorf("Error() = %q, want %q", got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+// Metadata holds arbitrary string attributes stored as a JSONB object.
+// A nil Metadata is stored as NULL.
+type Metadata map[string]string
+
+// Value implements driver.Valuer
+func (m Metadata) Value() (driver.Value, error) {
+	if m == nil {
+		return nil, nil
+	}
+	b, err := json.Marshal(m)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal metadata: %w", err)
+	}
+	return string(b), nil
+}
+
+// Scan implements sql.Scanner
+func (m *Metadata) Scan(src interface{}) error {
+	var data []byte
+	switch v := src.(type) {
+	case nil:
+		*m = nil
+		return nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("unsupported metadata type %T", src)
+	}
+	return json.Unmarshal(data, m)
+}
@@ -1,20 +1,31 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"strconv"
 	"time"
 
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
 	"github.com/kkkkikiki/coupon/internal/model"
 )
 
 // DBExecutor interface for database operations (can be *sqlx.DB or *sqlx.Tx)
 type DBExecutor interface {
-	Exec(query string, args ...interface{}) (sql.Result, error)
-	Get(dest interface{}, query string, args ...interface{}) error
-	Select(dest interface{}, query string, args ...interface{}) error
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
 }
 
+// campaignColumns lists the columns scanned into model.Campaign
+const campaignColumns = `id, available_coupons, start_date, code_alphabet, code_length, code_prefix, code_routing_tag, next_code_index,
+		discount_value, budget, issued_value, coupon_metadata, per_user_limit, max_issue_rps, shuffle_seed, reservation_order, slug,
+		window_start_minute, window_end_minute, window_time_zone, window_days, deleted_at, created_at, updated_at`
+
 // CampaignRepository handles campaign data operations
 type CampaignRepository struct {
 	// DB-only repository - no Redis dependencies
@@ -25,41 +36,105 @@ func NewCampaignRepository() *CampaignRepository {
 	return &CampaignRepository{}
 }
 
-// CreateCampaign creates a new campaign
-func (r *CampaignRepository) CreateCampaign(db DBExecutor, campaign *model.Campaign) error {
+// CreateCampaign creates a new campaign stamped with createdAt. A non-empty
+// requestID is stored uniquely; when a campaign with the same requestID
+// already exists nothing is inserted and created is false.
+func (r *CampaignRepository) CreateCampaign(ctx context.Context, db DBExecutor, campaign *model.Campaign, requestID string, createdAt time.Time) (created bool, err error) {
+	defer observeQuery("CampaignRepository.CreateCampaign", time.Now())
+
 	query := `
-		INSERT INTO campaigns (available_coupons, start_date, created_at, updated_at)
-		VALUES ($1, $2, $3, $4)
+		INSERT INTO campaigns (available_coupons, start_date, code_alphabet, code_length, code_prefix, code_routing_tag, next_code_index,
+			discount_value, budget, coupon_metadata, per_user_limit, max_issue_rps, shuffle_seed, reservation_order, slug,
+			window_start_minute, window_end_minute, window_time_zone, window_days, created_at, updated_at, create_request_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, NULLIF($22, ''))
+		ON CONFLICT (create_request_id) DO NOTHING
 		RETURNING id
 	`
 
-	now := time.Now()
-	campaign.CreatedAt = now
-	campaign.UpdatedAt = now
+	campaign.CreatedAt = createdAt
+	campaign.UpdatedAt = createdAt
 
-	err := db.Get(&campaign.ID, query,
-		campaign.AvailableCoupons, campaign.StartDate, campaign.CreatedAt, campaign.UpdatedAt)
+	err = db.GetContext(ctx, &campaign.ID, query,
+		campaign.AvailableCoupons, campaign.StartDate,
+		campaign.CodeAlphabet, campaign.CodeLength, campaign.CodePrefix, campaign.CodeRoutingTag, campaign.NextCodeIndex,
+		campaign.DiscountValue, campaign.Budget, campaign.CouponMetadata, campaign.PerUserLimit, campaign.MaxIssueRPS, campaign.ShuffleSeed,
+		campaign.ReservationOrder, campaign.Slug, campaign.WindowStart, campaign.WindowEnd, campaign.WindowTimeZone, campaign.WindowDays,
+		campaign.CreatedAt, campaign.UpdatedAt, requestID)
 
 	if err != nil {
-		return fmt.Errorf("failed to create campaign: %w", err)
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == pgUniqueViolation && pqErr.Constraint == "campaigns_slug_key" {
+			return false, ErrCampaignSlugExists
+		}
+		return false, fmt.Errorf("failed to create campaign: %w", err)
 	}
 
-	return nil
+	return true, nil
+}
+
+// GetCampaignIDBySlug resolves a campaign slug to its ID, including
+// soft-deleted campaigns
+func (r *CampaignRepository) GetCampaignIDBySlug(ctx context.Context, db DBExecutor, slug string) (int64, error) {
+	defer observeQuery("CampaignRepository.GetCampaignIDBySlug", time.Now())
+
+	var id int64
+	err := db.GetContext(ctx, &id, `SELECT id FROM campaigns WHERE slug = $1`, slug)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return 0, ErrCampaignNotFound
+		}
+		return 0, fmt.Errorf("failed to get campaign: %w", err)
+	}
+
+	return id, nil
+}
+
+// GetCampaignByRequestID retrieves the campaign created with a CreateCampaign
+// request ID, including soft-deleted campaigns
+func (r *CampaignRepository) GetCampaignByRequestID(ctx context.Context, db DBExecutor, requestID string) (*model.Campaign, error) {
+	defer observeQuery("CampaignRepository.GetCampaignByRequestID", time.Now())
+
+	query := `
+		SELECT ` + campaignColumns + `
+		FROM campaigns
+		WHERE create_request_id = $1
+	`
+
+	var campaign model.Campaign
+	err := db.GetContext(ctx, &campaign, query, requestID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrCampaignNotFound
+		}
+		return nil, fmt.Errorf("failed to get campaign: %w", err)
+	}
+
+	return &campaign, nil
 }
 
-// GetCampaign retrieves a campaign by ID
-func (r *CampaignRepository) GetCampaign(db DBExecutor, id int64) (*model.Campaign, error) {
+// GetCampaign retrieves a live (not soft-deleted) campaign by ID
+func (r *CampaignRepository) GetCampaign(ctx context.Context, db DBExecutor, id int64) (*model.Campaign, error) {
+	return r.getCampaign(ctx, db, id, false)
+}
+
+// getCampaign retrieves a campaign by ID, optionally including soft-deleted ones
+func (r *CampaignRepository) getCampaign(ctx context.Context, db DBExecutor, id int64, includeDeleted bool) (*model.Campaign, error) {
+	defer observeQuery("CampaignRepository.GetCampaign", time.Now())
+
 	query := `
-		SELECT id, available_coupons, start_date, created_at, updated_at
+		SELECT ` + campaignColumns + `
 		FROM campaigns
-		WHERE id = $1
+		WHERE id = $1 AND ($2 OR deleted_at IS NULL)
 	`
 
 	var campaign model.Campaign
-	err := db.Get(&campaign, query, id)
+	err := db.GetContext(ctx, &campaign, query, id, includeDeleted)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("campaign not found")
+			return nil, ErrCampaignNotFound
 		}
 		return nil, fmt.Errorf("failed to get campaign: %w", err)
 	}
@@ -67,26 +142,425 @@ func (r *CampaignRepository) GetCampaign(db DBExecutor, id int64) (*model.Campai
 	return &campaign, nil
 }
 
-// GetCampaignWithCoupons retrieves a campaign with all issued coupon codes
-func (r *CampaignRepository) GetCampaignWithCoupons(db DBExecutor, campaignID int64) (*model.Campaign, []string, error) {
-	campaign, err := r.GetCampaign(db, campaignID)
+// ListCampaigns returns a page of campaigns ordered by ID. page.After is the
+// decimal ID of the last campaign of the previous page. Soft-deleted
+// campaigns are skipped unless includeDeleted is set.
+func (r *CampaignRepository) ListCampaigns(ctx context.Context, db DBExecutor, page Page, includeDeleted bool) ([]model.Campaign, error) {
+	defer observeQuery("CampaignRepository.ListCampaigns", time.Now())
+
+	var afterID int64
+	if page.After != "" {
+		id, err := strconv.ParseInt(page.After, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidPageCursor, err)
+		}
+		afterID = id
+	}
+
+	query := `
+		SELECT ` + campaignColumns + `
+		FROM campaigns
+		WHERE id > $1 AND ($3 OR deleted_at IS NULL)
+		ORDER BY id
+		LIMIT $2
+	`
+
+	var campaigns []model.Campaign
+	if err := db.SelectContext(ctx, &campaigns, query, afterID, page.Limit, includeDeleted); err != nil {
+		return nil, fmt.Errorf("failed to list campaigns: %w", err)
+	}
+
+	return campaigns, nil
+}
+
+// GetCampaignWithCoupons retrieves a campaign with all issued coupon codes,
+// or with a random sample of sampleSize of them when sampleSize is positive
+func (r *CampaignRepository) GetCampaignWithCoupons(ctx context.Context, db DBExecutor, campaignID int64, includeDeleted bool, sampleSize int) (*model.Campaign, []string, error) {
+	defer observeQuery("CampaignRepository.GetCampaignWithCoupons", time.Now())
+
+	campaign, err := r.getCampaign(ctx, db, campaignID, includeDeleted)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	// Get only successfully issued coupon codes (status = 'issued')
+	// Get only successfully issued coupon codes (status = 'issued'). Coupons
+	// issued in the same instant are common under load, so ties are broken by
+	// code to keep the listing stable, matching the issuance cursor.
 	query := `
 		SELECT code
 		FROM coupons
 		WHERE campaign_id = $1 AND status = 'issued'
-		ORDER BY issued_at ASC
-	`
+		ORDER BY ` + issuanceOrder
+	args := []any{campaignID}
+	if sampleSize > 0 {
+		// Sorting the campaign's issued codes is still a full scan of them,
+		// but only sampleSize rows leave the database
+		query = `
+			SELECT code
+			FROM coupons
+			WHERE campaign_id = $1 AND status = 'issued'
+			ORDER BY random()
+			LIMIT $2
+		`
+		args = append(args, sampleSize)
+	}
 
 	var couponCodes []string
-	err = db.Select(&couponCodes, query, campaignID)
+	err = db.SelectContext(ctx, &couponCodes, query, args...)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to get coupon codes: %w", err)
 	}
 
 	return campaign, couponCodes, nil
 }
+
+// GetCampaignStats retrieves a campaign with its coupon counts, archived
+// coupons included
+func (r *CampaignRepository) GetCampaignStats(ctx context.Context, db DBExecutor, campaignID int64, now time.Time, recentWindow time.Duration) (*model.Campaign, *model.CampaignStats, error) {
+	defer observeQuery("CampaignRepository.GetCampaignStats", time.Now())
+
+	campaign, err := r.GetCampaign(ctx, db, campaignID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	query := `
+		SELECT
+			COUNT(*) AS total,
+			COUNT(*) FILTER (WHERE status = 'available') AS available,
+			COUNT(*) FILTER (WHERE status = 'issued') AS issued,
+			COUNT(*) FILTER (WHERE status = 'revoked') AS revoked,
+			COUNT(*) FILTER (WHERE status = 'issued' AND issued_at > $2) AS recently_issued
+		FROM ` + allCoupons + `
+		WHERE campaign_id = $1
+	`
+
+	var stats model.CampaignStats
+	if err := db.GetContext(ctx, &stats, query, campaignID, now.Add(-recentWindow)); err != nil {
+		return nil, nil, fmt.Errorf("failed to get campaign stats: %w", err)
+	}
+
+	poolQuery := `
+		SELECT
+			pool,
+			COUNT(*) AS total,
+			COUNT(*) FILTER (WHERE status = 'available') AS available,
+			COUNT(*) FILTER (WHERE status = 'issued') AS issued,
+			COUNT(*) FILTER (WHERE status = 'revoked') AS revoked
+		FROM ` + allCoupons + `
+		WHERE campaign_id = $1 AND pool <> ''
+		GROUP BY pool
+		ORDER BY pool
+	`
+
+	if err := db.SelectContext(ctx, &stats.Pools, poolQuery, campaignID); err != nil {
+		return nil, nil, fmt.Errorf("failed to get pool stats: %w", err)
+	}
+
+	return campaign, &stats, nil
+}
+
+// CountAvailableCoupons returns the number of unissued coupons of a campaign
+// that can still be issued at now, leaving out those whose pool expired
+func (r *CampaignRepository) CountAvailableCoupons(ctx context.Context, db DBExecutor, campaignID int64, now time.Time) (int32, error) {
+	defer observeQuery("CampaignRepository.CountAvailableCoupons", time.Now())
+
+	// Selecting from campaigns distinguishes a missing campaign from an empty one
+	query := `
+		SELECT (
+			SELECT COUNT(*)
+			FROM coupons
+			WHERE campaign_id = campaigns.id AND status = 'available' AND ` + unexpired("$2") + `
+		)
+		FROM campaigns
+		WHERE id = $1 AND deleted_at IS NULL
+	`
+
+	var count int32
+	err := db.GetContext(ctx, &count, query, campaignID, now)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return 0, ErrCampaignNotFound
+		}
+		return 0, fmt.Errorf("failed to count available coupons: %w", err)
+	}
+
+	return count, nil
+}
+
+// ChargeBudget charges the value of up to n coupons against the campaign
+// budget and returns how many were charged (0 when the budget is exhausted).
+// The campaign row is locked for the read-modify-write, so concurrent
+// issuances can't overspend.
+func (r *CampaignRepository) ChargeBudget(ctx context.Context, tx *sqlx.Tx, id int64, n int32) (int32, error) {
+	defer observeQuery("CampaignRepository.ChargeBudget", time.Now())
+
+	query := `
+		WITH affordable AS (
+			SELECT id, LEAST($2::bigint, (budget - issued_value) / discount_value) AS n
+			FROM campaigns
+			WHERE id = $1 AND discount_value > 0
+			FOR UPDATE
+		)
+		UPDATE campaigns
+		SET issued_value = issued_value + discount_value * affordable.n
+		FROM affordable
+		WHERE campaigns.id = affordable.id AND affordable.n > 0
+		RETURNING affordable.n
+	`
+
+	var charged int32
+	err := tx.GetContext(ctx, &charged, query, id, n)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("failed to charge campaign budget: %w", err)
+	}
+
+	return charged, nil
+}
+
+// LockCampaign retrieves a live campaign by ID and locks its row until the transaction ends
+func (r *CampaignRepository) LockCampaign(ctx context.Context, tx *sqlx.Tx, id int64) (*model.Campaign, error) {
+	defer observeQuery("CampaignRepository.LockCampaign", time.Now())
+
+	query := `
+		SELECT ` + campaignColumns + `
+		FROM campaigns
+		WHERE id = $1 AND deleted_at IS NULL
+		FOR UPDATE
+	`
+
+	var campaign model.Campaign
+	err := tx.GetContext(ctx, &campaign, query, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrCampaignNotFound
+		}
+		return nil, fmt.Errorf("failed to lock campaign: %w", err)
+	}
+
+	return &campaign, nil
+}
+
+// UpdateCodeFormat stores the campaign's code format and next coupon index
+func (r *CampaignRepository) UpdateCodeFormat(ctx context.Context, db DBExecutor, campaign *model.Campaign) error {
+	defer observeQuery("CampaignRepository.UpdateCodeFormat", time.Now())
+
+	query := `
+		UPDATE campaigns
+		SET code_alphabet = $1, code_length = $2, code_prefix = $3, code_routing_tag = $4, next_code_index = $5
+		WHERE id = $6
+	`
+
+	_, err := db.ExecContext(ctx, query,
+		campaign.CodeAlphabet, campaign.CodeLength, campaign.CodePrefix, campaign.CodeRoutingTag, campaign.NextCodeIndex, campaign.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update code format: %w", err)
+	}
+
+	return nil
+}
+
+// SoftDeleteCampaign marks a live campaign as deleted at deletedAt and returns
+// the recorded deletion time. Its rows, coupons included, are kept for history.
+// Unless force is set, a campaign with any coupon no longer available is left
+// alone and "campaign has handed out coupons" is returned; the check is part
+// of the update, so a coupon issued after an earlier check still blocks it.
+func (r *CampaignRepository) SoftDeleteCampaign(ctx context.Context, db DBExecutor, id int64, deletedAt time.Time, force bool) (time.Time, error) {
+	defer observeQuery("CampaignRepository.SoftDeleteCampaign", time.Now())
+
+	query := `
+		UPDATE campaigns
+		SET deleted_at = $2
+		WHERE id = $1 AND deleted_at IS NULL
+			AND ($3 OR (
+				NOT EXISTS (SELECT 1 FROM coupons WHERE campaign_id = $1 AND status <> 'available')
+				AND NOT EXISTS (SELECT 1 FROM archived_coupons WHERE campaign_id = $1)
+			))
+		RETURNING deleted_at
+	`
+
+	err := db.GetContext(ctx, &deletedAt, query, id, deletedAt, force)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return time.Time{}, r.softDeleteError(ctx, db, id)
+		}
+		return time.Time{}, fmt.Errorf("failed to delete campaign: %w", err)
+	}
+
+	return deletedAt, nil
+}
+
+// softDeleteError explains why SoftDeleteCampaign matched no row. It is only
+// called on that zero-rows path.
+func (r *CampaignRepository) softDeleteError(ctx context.Context, db DBExecutor, id int64) error {
+	var live bool
+	err := db.GetContext(ctx, &live, `SELECT EXISTS (SELECT 1 FROM campaigns WHERE id = $1 AND deleted_at IS NULL)`, id)
+	if err != nil {
+		return fmt.Errorf("failed to get campaign: %w", err)
+	}
+	if !live {
+		return ErrCampaignNotFound
+	}
+	return ErrCampaignHandedOut
+}
+
+// RestoreCampaign clears the deletion mark of a soft-deleted campaign
+func (r *CampaignRepository) RestoreCampaign(ctx context.Context, db DBExecutor, id int64) (*model.Campaign, error) {
+	defer observeQuery("CampaignRepository.RestoreCampaign", time.Now())
+
+	query := `
+		UPDATE campaigns
+		SET deleted_at = NULL
+		WHERE id = $1 AND deleted_at IS NOT NULL
+		RETURNING ` + campaignColumns
+
+	var campaign model.Campaign
+	err := db.GetContext(ctx, &campaign, query, id)
+	if err == nil {
+		return &campaign, nil
+	}
+	if err != sql.ErrNoRows {
+		return nil, fmt.Errorf("failed to restore campaign: %w", err)
+	}
+
+	// Tell a missing campaign apart from one that isn't deleted
+	if _, err := r.getCampaign(ctx, db, id, true); err != nil {
+		return nil, err
+	}
+	return nil, ErrCampaignNotDeleted
+}
+
+// UpdateMaxIssueRPS sets the issuance rate limit of a live campaign
+func (r *CampaignRepository) UpdateMaxIssueRPS(ctx context.Context, db DBExecutor, id int64, rps float64) (*model.Campaign, error) {
+	defer observeQuery("CampaignRepository.UpdateMaxIssueRPS", time.Now())
+
+	query := `
+		UPDATE campaigns
+		SET max_issue_rps = $2
+		WHERE id = $1 AND deleted_at IS NULL
+		RETURNING ` + campaignColumns
+
+	var campaign model.Campaign
+	if err := db.GetContext(ctx, &campaign, query, id, rps); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrCampaignNotFound
+		}
+		return nil, fmt.Errorf("failed to update campaign: %w", err)
+	}
+
+	return &campaign, nil
+}
+
+// ListDueActivations returns live campaigns that became active since the
+// given instant: those whose start_date passed in (since, now], and those
+// created after since with a start_date already in the past.
+func (r *CampaignRepository) ListDueActivations(ctx context.Context, db DBExecutor, since, now time.Time) ([]model.Campaign, error) {
+	defer observeQuery("CampaignRepository.ListDueActivations", time.Now())
+
+	query := `
+		SELECT ` + campaignColumns + `
+		FROM campaigns
+		WHERE start_date <= $2
+		  AND (start_date > $1 OR created_at > $1)
+		  AND deleted_at IS NULL
+		ORDER BY start_date, id
+	`
+
+	var campaigns []model.Campaign
+	if err := db.SelectContext(ctx, &campaigns, query, since, now); err != nil {
+		return nil, fmt.Errorf("failed to list due activations: %w", err)
+	}
+
+	return campaigns, nil
+}
+
+// NextActivation returns the earliest start_date of a live campaign after the
+// given instant. ok is false when no campaign is scheduled.
+func (r *CampaignRepository) NextActivation(ctx context.Context, db DBExecutor, after time.Time) (next time.Time, ok bool, err error) {
+	defer observeQuery("CampaignRepository.NextActivation", time.Now())
+
+	query := `
+		SELECT MIN(start_date)
+		FROM campaigns
+		WHERE start_date > $1 AND deleted_at IS NULL
+	`
+
+	var start sql.NullTime
+	if err := db.GetContext(ctx, &start, query, after); err != nil {
+		return time.Time{}, false, fmt.Errorf("failed to get next activation: %w", err)
+	}
+
+	return start.Time, start.Valid, nil
+}
+
+// FindOverissued counts issued coupons of up to sample randomly chosen live
+// campaigns started by now and returns those with more issued coupons than
+// their total. Sampling keeps each check cheap; over time every campaign is
+// seen.
+func (r *CampaignRepository) FindOverissued(ctx context.Context, db DBExecutor, sample int, now time.Time) ([]model.IssuanceCount, error) {
+	defer observeQuery("CampaignRepository.FindOverissued", time.Now())
+
+	query := `
+		WITH sampled AS (
+			SELECT id, available_coupons
+			FROM campaigns
+			WHERE deleted_at IS NULL AND start_date <= $2
+			ORDER BY random()
+			LIMIT $1
+		)
+		SELECT s.id AS campaign_id, s.available_coupons AS total, COUNT(c.code) AS issued
+		FROM sampled s
+		JOIN coupons c ON c.campaign_id = s.id AND c.status NOT IN ('available', 'reserved')
+		GROUP BY s.id, s.available_coupons
+		HAVING COUNT(c.code) > s.available_coupons
+	`
+
+	var counts []model.IssuanceCount
+	if err := db.SelectContext(ctx, &counts, query, sample, now); err != nil {
+		return nil, fmt.Errorf("failed to check issued counts: %w", err)
+	}
+
+	return counts, nil
+}
+
+// ActiveRemainingCounts returns the count of coupons still issuable at now of
+// up to limit live campaigns started by now, most recently started first
+func (r *CampaignRepository) ActiveRemainingCounts(ctx context.Context, db DBExecutor, limit int, now time.Time) ([]model.RemainingCount, error) {
+	defer observeQuery("CampaignRepository.ActiveRemainingCounts", time.Now())
+
+	query := `
+		SELECT c.id AS campaign_id,
+			(SELECT COUNT(*) FROM coupons WHERE campaign_id = c.id AND status = 'available' AND ` + unexpired("$2") + `) AS available
+		FROM campaigns c
+		WHERE c.deleted_at IS NULL AND c.start_date <= $2
+		ORDER BY c.start_date DESC, c.id DESC
+		LIMIT $1
+	`
+
+	var counts []model.RemainingCount
+	if err := db.SelectContext(ctx, &counts, query, limit, now); err != nil {
+		return nil, fmt.Errorf("failed to count remaining coupons: %w", err)
+	}
+
+	return counts, nil
+}
+
+// AdjustCouponCount adds delta to the campaign's coupon count
+func (r *CampaignRepository) AdjustCouponCount(ctx context.Context, db DBExecutor, id int64, delta int32) error {
+	defer observeQuery("CampaignRepository.AdjustCouponCount", time.Now())
+
+	query := `
+		UPDATE campaigns
+		SET available_coupons = available_coupons + $1
+		WHERE id = $2
+	`
+
+	if _, err := db.ExecContext(ctx, query, delta, id); err != nil {
+		return fmt.Errorf("failed to adjust coupon count: %w", err)
+	}
+
+	return nil
+}
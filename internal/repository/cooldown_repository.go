@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+)
+
+// CooldownRepository tracks per-user issuance cooldowns
+type CooldownRepository struct{}
+
+// NewCooldownRepository creates a new cooldown repository
+func NewCooldownRepository() *CooldownRepository {
+	return &CooldownRepository{}
+}
+
+// ClaimIssueCooldown records an issuance at now for the user if their
+// previous one is older than window. It returns zero when the claim
+// succeeded, otherwise the time left until the cooldown ends. Run it inside
+// the issuance transaction so the claim is rolled back when issuance fails.
+func (r *CooldownRepository) ClaimIssueCooldown(ctx context.Context, db DBExecutor, userID string, now time.Time, window time.Duration) (time.Duration, error) {
+	defer observeQuery("CooldownRepository.ClaimIssueCooldown", time.Now())
+
+	query := `
+		INSERT INTO user_issue_cooldowns (user_id, last_issued_at)
+		VALUES ($1, $2)
+		ON CONFLICT (user_id) DO UPDATE
+		SET last_issued_at = $2
+		WHERE user_issue_cooldowns.last_issued_at <= $2::timestamptz - make_interval(secs => $3)
+	`
+
+	result, err := db.ExecContext(ctx, query, userID, now, window.Seconds())
+	if err != nil {
+		return 0, fmt.Errorf("failed to claim user cooldown: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 1 {
+		return 0, nil
+	}
+
+	// The conflicting row is locked by the upsert, so this reads a stable value
+	var remaining float64
+	err = db.GetContext(ctx, &remaining, `
+		SELECT GREATEST(EXTRACT(EPOCH FROM last_issued_at + make_interval(secs => $2) - $3::timestamptz), 0)
+		FROM user_issue_cooldowns
+		WHERE user_id = $1
+	`, userID, window.Seconds(), now)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get user cooldown: %w", err)
+	}
+
+	// Round up so the hint never undershoots the remaining time
+	retryAfter := time.Duration(math.Ceil(remaining*1000)) * time.Millisecond
+	if retryAfter <= 0 {
+		retryAfter = time.Millisecond
+	}
+	return retryAfter, nil
+}
@@ -1,14 +1,37 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"github.com/kkkkikiki/coupon/internal/model"
 )
 
+// pgLockNotAvailable is the PostgreSQL error code raised by FOR UPDATE NOWAIT
+const pgLockNotAvailable = "55P03"
+
+// pgUniqueViolation is the PostgreSQL error code raised by a duplicate key
+const pgUniqueViolation = "23505"
+
+// couponColumns lists the columns scanned into model.Coupon
+const couponColumns = `code, campaign_id, status, issued_at, expires_at, pool, metadata, created_at`
+
+// allCoupons reads the coupons and archived_coupons tables as one, for lookups
+// that must still find coupons after archival. Filters on it are pushed down
+// to each table's indexes.
+const allCoupons = `(SELECT ` + couponColumns + ` FROM coupons UNION ALL SELECT ` + couponColumns + ` FROM archived_coupons) AS coupons`
+
+// liveCampaign restricts coupon reads to coupons of campaigns that are not
+// soft-deleted
+const liveCampaign = `EXISTS (SELECT 1 FROM campaigns WHERE campaigns.id = coupons.campaign_id AND campaigns.deleted_at IS NULL)`
+
 // CouponRepository handles coupon data operations
 type CouponRepository struct {
 	// DB-only repository - no Redis dependencies
@@ -19,58 +42,657 @@ func NewCouponRepository() *CouponRepository {
 	return &CouponRepository{}
 }
 
-// MarkCouponAsIssued updates coupon status from 'available' to 'issued'
-func (r *CouponRepository) MarkCouponAsIssued(db DBExecutor, couponCode string) error {
+// MarkCouponAsIssued moves a coupon to 'issued' at issuedAt from any status the coupon
+// state machine allows. userID may be empty when the caller is anonymous. A coupon without an
+// explicit expiry expires defaultTTL after issuance; a zero defaultTTL leaves
+// it without expiry. It returns the coupon's resulting expiry, if any.
+func (r *CouponRepository) MarkCouponAsIssued(ctx context.Context, db DBExecutor, campaignID int64, couponCode, userID string, issuedAt time.Time, defaultTTL time.Duration) (*time.Time, error) {
+	defer observeQuery("CouponRepository.MarkCouponAsIssued", time.Now())
+
 	query := `
 		UPDATE coupons 
-		SET status = 'issued', issued_at = $1 
-		WHERE code = $2 AND status = 'available'
+		SET status = 'issued', issued_at = $1, user_id = NULLIF($3, ''), expires_at = COALESCE(expires_at, $4)
+		WHERE campaign_id = $6 AND code = $2 AND status = ANY($5)
+		RETURNING expires_at
 	`
 
-	now := time.Now()
-	result, err := db.Exec(query, now, couponCode)
+	var expiresAt *time.Time
+	err := db.GetContext(ctx, &expiresAt, query, issuedAt, couponCode, userID, defaultExpiry(issuedAt, defaultTTL),
+		pq.Array(model.TransitionSources(model.CouponStatusIssued)), campaignID)
 	if err != nil {
-		return fmt.Errorf("failed to mark coupon as issued: %w", err)
+		// No row was updated
+		if err == sql.ErrNoRows {
+			return nil, r.transitionError(ctx, db, campaignID, couponCode, model.CouponStatusIssued)
+		}
+		return nil, fmt.Errorf("failed to mark coupon as issued: %w", err)
 	}
 
-	// Check if any row was actually updated
-	rowsAffected, err := result.RowsAffected()
+	return expiresAt, nil
+}
+
+// transitionError explains why a guarded status update of a coupon matched no
+// row. It is only called on that zero-rows path. An unknown code yields
+// ErrCouponNotFound; an existing coupon yields a *model.TransitionError from
+// its current status, which may also have changed concurrently since the
+// update.
+func (r *CouponRepository) transitionError(ctx context.Context, db DBExecutor, campaignID int64, code string, to model.CouponStatus) error {
+	var from model.CouponStatus
+	err := db.GetContext(ctx, &from, `SELECT status FROM coupons WHERE campaign_id = $1 AND code = $2`, campaignID, code)
 	if err != nil {
-		return fmt.Errorf("failed to get rows affected: %w", err)
-	}
-	if rowsAffected == 0 {
-		return fmt.Errorf("coupon not found or already issued")
+		if err == sql.ErrNoRows {
+			return ErrCouponNotFound
+		}
+		return fmt.Errorf("failed to get coupon status: %w", err)
 	}
+	return &model.TransitionError{From: from, To: to}
+}
 
-	return nil
+// defaultExpiry returns the expiry given to coupons issued at issuedAt
+// without an explicit one, or nil when ttl is zero
+func defaultExpiry(issuedAt time.Time, ttl time.Duration) *time.Time {
+	if ttl <= 0 {
+		return nil
+	}
+	expiresAt := issuedAt.Add(ttl)
+	return &expiresAt
 }
 
-// ReserveAvailableCoupon finds and reserves an available coupon using SELECT FOR UPDATE
-func (r *CouponRepository) ReserveAvailableCoupon(tx *sqlx.Tx, campaignID int64) (string, error) {
+// ReserveAvailableCoupon finds and reserves an available coupon using SELECT FOR UPDATE,
+// taking coupons in the campaign's reservation order and skipping those expired
+// at now. A non-empty pool restricts the reservation to that pool. It returns
+// the coupon's code and pool.
+func (r *CouponRepository) ReserveAvailableCoupon(ctx context.Context, tx *sqlx.Tx, campaignID int64, order model.ReservationOrder, pool string, now time.Time) (string, string, error) {
+	defer observeQuery("CouponRepository.ReserveAvailableCoupon", time.Now())
+
+	args := []interface{}{campaignID, now}
+	poolFilter := ""
+	if pool != "" {
+		args = append(args, pool)
+		poolFilter = "AND pool = $3"
+	}
+
 	query := `
-		SELECT code 
+		SELECT code, pool
 		FROM coupons 
-		WHERE campaign_id = $1 AND status = 'available' 
-		ORDER BY created_at ASC 
-		LIMIT 1 
+		WHERE campaign_id = $1 AND status = 'available' AND ` + unexpired("$2") + ` ` + poolFilter + `
+		ORDER BY ` + reservationOrder(order) + `
+		LIMIT 1
 		FOR UPDATE SKIP LOCKED
 	`
 
-	var couponCode string
-	err := tx.Get(&couponCode, query, campaignID)
+	var coupon struct {
+		Code string `db:"code"`
+		Pool string `db:"pool"`
+	}
+	err := tx.GetContext(ctx, &coupon, query, args...)
+	if err == nil || err == sql.ErrNoRows {
+		// The transaction is still usable; catch index regressions of the
+		// hot-path query in debug deployments
+		explainSampled(ctx, tx, "CouponRepository.ReserveAvailableCoupon", campaignID, query, args...)
+	}
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", "", ErrNoAvailableCoupons
+		}
+		return "", "", fmt.Errorf("failed to reserve coupon: %w", err)
+	}
+
+	return coupon.Code, coupon.Pool, nil
+}
+
+// ReserveAvailableCoupons locks up to n available coupons of a campaign in
+// its reservation order, skipping coupons expired at now and coupons locked by
+// concurrent issuances. It may return fewer than n.
+func (r *CouponRepository) ReserveAvailableCoupons(ctx context.Context, tx *sqlx.Tx, campaignID int64, order model.ReservationOrder, n int32, now time.Time) ([]string, error) {
+	defer observeQuery("CouponRepository.ReserveAvailableCoupons", time.Now())
+
+	query := `
+		SELECT code
+		FROM coupons
+		WHERE campaign_id = $1 AND status = 'available' AND ` + unexpired("$3") + `
+		ORDER BY ` + reservationOrder(order) + `
+		LIMIT $2
+		FOR UPDATE SKIP LOCKED
+	`
+
+	var codes []string
+	if err := tx.SelectContext(ctx, &codes, query, campaignID, n, now); err != nil {
+		return nil, fmt.Errorf("failed to reserve coupons: %w", err)
+	}
+
+	return codes, nil
+}
+
+// MarkCouponsAsIssued marks reserved coupons as issued to a user at issuedAt,
+// applying defaultTTL like MarkCouponAsIssued. It returns the expiry of each
+// coupon that has one, keyed by code.
+func (r *CouponRepository) MarkCouponsAsIssued(ctx context.Context, tx *sqlx.Tx, campaignID int64, codes []string, userID string, issuedAt time.Time, defaultTTL time.Duration) (map[string]time.Time, error) {
+	defer observeQuery("CouponRepository.MarkCouponsAsIssued", time.Now())
+
+	query := `
+		UPDATE coupons
+		SET status = 'issued', issued_at = $1, user_id = NULLIF($3, ''), expires_at = COALESCE(expires_at, $4)
+		WHERE campaign_id = $6 AND code = ANY($2) AND status = ANY($5)
+		RETURNING code, expires_at
+	`
+
+	var rows []struct {
+		Code      string     `db:"code"`
+		ExpiresAt *time.Time `db:"expires_at"`
+	}
+	if err := tx.SelectContext(ctx, &rows, query, issuedAt, pq.Array(codes), userID, defaultExpiry(issuedAt, defaultTTL),
+		pq.Array(model.TransitionSources(model.CouponStatusIssued)), campaignID); err != nil {
+		return nil, fmt.Errorf("failed to mark coupons as issued: %w", err)
+	}
+	if len(rows) != len(codes) {
+		// Explain the first coupon that wasn't updated
+		updated := make(map[string]bool, len(rows))
+		for _, row := range rows {
+			updated[row.Code] = true
+		}
+		for _, code := range codes {
+			if !updated[code] {
+				return nil, r.transitionError(ctx, tx, campaignID, code, model.CouponStatusIssued)
+			}
+		}
+	}
+
+	expiries := make(map[string]time.Time, len(rows))
+	for _, row := range rows {
+		if row.ExpiresAt != nil {
+			expiries[row.Code] = *row.ExpiresAt
+		}
+	}
+	return expiries, nil
+}
+
+// LockUserIssuance serializes issuances of a campaign to one user until the
+// transaction ends, so per-user counts can't race
+func (r *CouponRepository) LockUserIssuance(ctx context.Context, tx *sqlx.Tx, campaignID int64, userID string) error {
+	defer observeQuery("CouponRepository.LockUserIssuance", time.Now())
+
+	query := `SELECT pg_advisory_xact_lock(hashtextextended($1::bigint || ':' || $2, 0))`
+
+	if _, err := tx.ExecContext(ctx, query, campaignID, userID); err != nil {
+		return fmt.Errorf("failed to lock user issuance: %w", err)
+	}
+
+	return nil
+}
+
+// CountUserCoupons returns the number of coupons of a campaign issued to a user
+func (r *CouponRepository) CountUserCoupons(ctx context.Context, db DBExecutor, campaignID int64, userID string) (int32, error) {
+	defer observeQuery("CouponRepository.CountUserCoupons", time.Now())
+
+	query := `
+		SELECT COUNT(*)
+		FROM coupons
+		WHERE campaign_id = $1 AND user_id = $2
+	`
+
+	var count int32
+	if err := db.GetContext(ctx, &count, query, campaignID, userID); err != nil {
+		return 0, fmt.Errorf("failed to count user coupons: %w", err)
+	}
+
+	return count, nil
+}
+
+// CountHandedOutCoupons counts the coupons of a campaign that are no longer
+// available, including archived ones
+func (r *CouponRepository) CountHandedOutCoupons(ctx context.Context, db DBExecutor, campaignID int64) (int64, error) {
+	defer observeQuery("CouponRepository.CountHandedOutCoupons", time.Now())
+
+	query := `
+		SELECT COUNT(*)
+		FROM ` + allCoupons + `
+		WHERE campaign_id = $1 AND status <> 'available'
+	`
+
+	var count int64
+	if err := db.GetContext(ctx, &count, query, campaignID); err != nil {
+		return 0, fmt.Errorf("failed to count handed out coupons: %w", err)
+	}
+
+	return count, nil
+}
+
+// DeleteAvailableCoupons deletes all available coupons of a campaign and
+// returns the number deleted per pool ("" for coupons outside any pool),
+// priority and expiry, in pool order.
+// Fails with "coupons are currently reserved" if any of them is locked by an
+// in-flight issuance.
+func (r *CouponRepository) DeleteAvailableCoupons(ctx context.Context, tx *sqlx.Tx, campaignID int64) ([]model.CouponGroupCount, error) {
+	defer observeQuery("CouponRepository.DeleteAvailableCoupons", time.Now())
+
+	query := `
+		WITH deleted AS (
+			DELETE FROM coupons
+			WHERE campaign_id = $1 AND code IN (
+				SELECT code
+				FROM coupons
+				WHERE campaign_id = $1 AND status = 'available'
+				FOR UPDATE NOWAIT
+			)
+			RETURNING pool, priority, expires_at
+		)
+		SELECT pool, priority, expires_at, COUNT(*) AS count
+		FROM deleted
+		GROUP BY pool, priority, expires_at
+		ORDER BY pool, priority DESC, expires_at
+	`
+
+	var groups []model.CouponGroupCount
+	if err := tx.SelectContext(ctx, &groups, query, campaignID); err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == pgLockNotAvailable {
+			return nil, ErrCouponsReserved
+		}
+		return nil, fmt.Errorf("failed to delete available coupons: %w", err)
+	}
+
+	return groups, nil
+}
+
+// LockCoupons locks the given coupons of a campaign and returns them.
+// Fails with "coupons are currently reserved" if any of them is locked by an
+// in-flight issuance.
+func (r *CouponRepository) LockCoupons(ctx context.Context, tx *sqlx.Tx, campaignID int64, codes []string) ([]model.Coupon, error) {
+	defer observeQuery("CouponRepository.LockCoupons", time.Now())
+
+	query := `
+		SELECT ` + couponColumns + `
+		FROM coupons
+		WHERE campaign_id = $1 AND code = ANY($2)
+		FOR UPDATE NOWAIT
+	`
+
+	var coupons []model.Coupon
+	err := tx.SelectContext(ctx, &coupons, query, campaignID, pq.Array(codes))
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == pgLockNotAvailable {
+			return nil, ErrCouponsReserved
+		}
+		return nil, fmt.Errorf("failed to lock coupons: %w", err)
+	}
+
+	return coupons, nil
+}
+
+// MoveCoupons reassigns available coupons to another campaign, re-keying
+// them for the target campaign's issuance order (toShuffleSeed, nil when it
+// issues in creation order)
+func (r *CouponRepository) MoveCoupons(ctx context.Context, tx *sqlx.Tx, codes []string, fromCampaignID, toCampaignID int64, toShuffleSeed *int64) (int64, error) {
+	defer observeQuery("CouponRepository.MoveCoupons", time.Now())
+
+	query := `
+		UPDATE coupons
+		SET campaign_id = $1, sort_key = moved.sort_key
+		FROM unnest($2::text[], $4::bigint[]) AS moved(code, sort_key)
+		WHERE coupons.code = moved.code AND coupons.campaign_id = $3 AND coupons.status = 'available'
+	`
+
+	result, err := tx.ExecContext(ctx, query, toCampaignID, pq.Array(codes), fromCampaignID,
+		pq.Array(sortKeys(toShuffleSeed, codes)))
+	if err != nil {
+		// Only possible when codes are unique per campaign
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == pgUniqueViolation {
+			return 0, ErrTargetCodesExist
+		}
+		return 0, fmt.Errorf("failed to move coupons: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected, nil
+}
+
+// GetCoupon retrieves a coupon by its code, including archived coupons. A
+// non-zero campaignID restricts the lookup to that campaign, which is
+// required to tell coupons apart when codes are only unique per campaign.
+func (r *CouponRepository) GetCoupon(ctx context.Context, db DBExecutor, campaignID int64, code string) (*model.Coupon, error) {
+	defer observeQuery("CouponRepository.GetCoupon", time.Now())
+
+	query := `
+		SELECT ` + couponColumns + `
+		FROM ` + allCoupons + `
+		WHERE code = $1 AND ($2 = 0 OR campaign_id = $2) AND ` + liveCampaign + `
+	`
+
+	var coupon model.Coupon
+	err := db.GetContext(ctx, &coupon, query, code, campaignID)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return "", fmt.Errorf("no available coupons")
+			return nil, ErrCouponNotFound
 		}
-		return "", fmt.Errorf("failed to reserve coupon: %w", err)
+		return nil, fmt.Errorf("failed to get coupon: %w", err)
 	}
 
-	return couponCode, nil
+	return &coupon, nil
 }
 
-// CreatePregeneratedCoupons creates multiple coupons in batch within existing transaction
-func (r *CouponRepository) CreatePregeneratedCoupons(tx *sqlx.Tx, campaignID int64, couponCodes []string) error {
-	now := time.Now()
+// GetCouponWithCampaign retrieves a coupon of a live campaign, archived or
+// not, together with its campaign's redemption fields. Both sides are primary
+// key lookups. A non-zero campaignID restricts the lookup like in GetCoupon.
+func (r *CouponRepository) GetCouponWithCampaign(ctx context.Context, db DBExecutor, campaignID int64, code string) (*model.CouponWithCampaign, error) {
+	defer observeQuery("CouponRepository.GetCouponWithCampaign", time.Now())
+
+	query := `
+		SELECT c.code, c.campaign_id, c.status, c.issued_at, c.expires_at, c.pool, c.metadata, c.created_at,
+			k.discount_value, k.start_date
+		FROM ` + allCoupons + ` c
+		JOIN campaigns k ON k.id = c.campaign_id
+		WHERE c.code = $1 AND ($2 = 0 OR c.campaign_id = $2) AND k.deleted_at IS NULL
+	`
+
+	var coupon model.CouponWithCampaign
+	err := db.GetContext(ctx, &coupon, query, code, campaignID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrCouponNotFound
+		}
+		return nil, fmt.Errorf("failed to get coupon: %w", err)
+	}
+
+	return &coupon, nil
+}
+
+// SearchCouponsByPrefix returns a page of coupons of a campaign whose code
+// starts with prefix, ordered by code
+func (r *CouponRepository) SearchCouponsByPrefix(ctx context.Context, db DBExecutor, campaignID int64, prefix string, page Page) ([]model.Coupon, error) {
+	defer observeQuery("CouponRepository.SearchCouponsByPrefix", time.Now())
+
+	query := `
+		SELECT ` + couponColumns + `
+		FROM coupons
+		WHERE campaign_id = $1 AND code LIKE $2 || '%' AND code > $3 AND ` + liveCampaign + `
+		ORDER BY code
+		LIMIT $4
+	`
+
+	var coupons []model.Coupon
+	err := db.SelectContext(ctx, &coupons, query, campaignID, escapeLike(prefix), page.After, page.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search coupons: %w", err)
+	}
+
+	return coupons, nil
+}
+
+// ListCoupons returns a page of coupons of a campaign ordered by code,
+// optionally filtered by status and by a metadata key/value pair
+func (r *CouponRepository) ListCoupons(ctx context.Context, db DBExecutor, campaignID int64, status model.CouponStatus, metadataKey, metadataValue string, page Page) ([]model.Coupon, error) {
+	defer observeQuery("CouponRepository.ListCoupons", time.Now())
+
+	query := `
+		SELECT ` + couponColumns + `
+		FROM coupons
+		WHERE campaign_id = $1 AND ($2 = '' OR status = $2) AND code > $3
+			AND ($5::text = '' OR metadata @> jsonb_build_object($5::text, $6::text))
+			AND ` + liveCampaign + `
+		ORDER BY code
+		LIMIT $4
+	`
+
+	var coupons []model.Coupon
+	err := db.SelectContext(ctx, &coupons, query, campaignID, status, page.After, page.Limit, metadataKey, metadataValue)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list coupons: %w", err)
+	}
+
+	return coupons, nil
+}
+
+// RevokeIssuedCoupons revokes up to limit coupons of a campaign that the
+// coupon state machine allows to be revoked, and returns how many were
+// revoked. Callers repeat until fewer than limit come back.
+func (r *CouponRepository) RevokeIssuedCoupons(ctx context.Context, db DBExecutor, campaignID int64, limit int) (int64, error) {
+	defer observeQuery("CouponRepository.RevokeIssuedCoupons", time.Now())
+
+	query := `
+		UPDATE coupons
+		SET status = 'revoked'
+		WHERE campaign_id = $1 AND code IN (
+			SELECT code
+			FROM coupons
+			WHERE campaign_id = $1 AND status = ANY($3)
+			LIMIT $2
+			FOR UPDATE
+		)
+	`
+
+	result, err := db.ExecContext(ctx, query, campaignID, limit, pq.Array(model.TransitionSources(model.CouponStatusRevoked)))
+	if err != nil {
+		return 0, fmt.Errorf("failed to revoke coupons: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected, nil
+}
+
+// FindArchivableCampaigns returns up to limit IDs of campaigns that still
+// have coupons in the coupons table, all of them redeemed, expired or revoked,
+// and none issued at or after before
+func (r *CouponRepository) FindArchivableCampaigns(ctx context.Context, db DBExecutor, before time.Time, limit int) ([]int64, error) {
+	defer observeQuery("CouponRepository.FindArchivableCampaigns", time.Now())
+
+	query := `
+		SELECT k.id
+		FROM campaigns k
+		WHERE EXISTS (SELECT 1 FROM coupons WHERE campaign_id = k.id)
+			AND NOT EXISTS (
+				SELECT 1
+				FROM coupons
+				WHERE campaign_id = k.id AND (status IN ('available', 'reserved', 'issued') OR issued_at >= $1)
+			)
+		ORDER BY k.id
+		LIMIT $2
+	`
+
+	var ids []int64
+	if err := db.SelectContext(ctx, &ids, query, before, limit); err != nil {
+		return nil, fmt.Errorf("failed to find archivable campaigns: %w", err)
+	}
+
+	return ids, nil
+}
+
+// ArchiveCoupons moves up to limit redeemed, expired or revoked coupons of a
+// campaign from coupons to archived_coupons in one statement, stamping them
+// with archivedAt, and returns how many were moved. Callers repeat until fewer
+// than limit come back.
+func (r *CouponRepository) ArchiveCoupons(ctx context.Context, db DBExecutor, campaignID int64, limit int, archivedAt time.Time) (int64, error) {
+	defer observeQuery("CouponRepository.ArchiveCoupons", time.Now())
+
+	query := `
+		WITH moved AS (
+			DELETE FROM coupons
+			WHERE campaign_id = $1 AND code IN (
+				SELECT code
+				FROM coupons
+				WHERE campaign_id = $1 AND status IN ('redeemed', 'expired', 'revoked')
+				LIMIT $2
+				FOR UPDATE SKIP LOCKED
+			)
+			RETURNING code, campaign_id, status, user_id, pool, priority, sort_key, code_index, metadata, expires_at, issued_at, created_at
+		)
+		INSERT INTO archived_coupons (code, campaign_id, status, user_id, pool, priority, sort_key, code_index, metadata, expires_at, issued_at, created_at, archived_at)
+		SELECT code, campaign_id, status, user_id, pool, priority, sort_key, code_index, metadata, expires_at, issued_at, created_at, $3
+		FROM moved
+	`
+
+	result, err := db.ExecContext(ctx, query, campaignID, limit, archivedAt)
+	if err != nil {
+		return 0, fmt.Errorf("failed to archive coupons: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected, nil
+}
+
+// ListIssuanceEvents returns coupons issued at or after since, ordered by
+// issuance time then code, optionally restricted to one campaign (0 for all).
+// page.After is a cursor from IssuanceCursor. Coupons that were never handed
+// out (available, reserved) have no issuance event; coupons issued and later
+// redeemed, expired or revoked are included.
+func (r *CouponRepository) ListIssuanceEvents(ctx context.Context, db DBExecutor, campaignID int64, since time.Time, page Page) ([]model.IssuanceEvent, error) {
+	defer observeQuery("CouponRepository.ListIssuanceEvents", time.Now())
+
+	afterIssuedAt, afterCode := since, ""
+	if page.After != "" {
+		at, code, ok := strings.Cut(page.After, " ")
+		t, err := time.Parse(time.RFC3339Nano, at)
+		if !ok || err != nil {
+			return nil, ErrInvalidPageCursor
+		}
+		afterIssuedAt, afterCode = t, code
+	}
+
+	query := `
+		SELECT code, campaign_id, status, issued_at, user_id
+		FROM coupons
+		WHERE status NOT IN ('available', 'reserved')
+			AND ($1 = 0 OR campaign_id = $1)
+			AND (issued_at, code) > ($2, $3)
+		ORDER BY ` + issuanceOrder + `
+		LIMIT $4
+	`
+
+	var events []model.IssuanceEvent
+	err := db.SelectContext(ctx, &events, query, campaignID, afterIssuedAt, afterCode, page.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list issuance events: %w", err)
+	}
+
+	return events, nil
+}
+
+// issuanceOrder is the total order of issued coupons shared by every listing
+// and by IssuanceCursor. issued_at alone has ties; code makes it unique.
+const issuanceOrder = `issued_at, code`
+
+// IssuanceCursor returns the page cursor positioned after an issuance event
+func IssuanceCursor(event *model.IssuanceEvent) string {
+	return event.IssuedAt.Format(time.RFC3339Nano) + " " + event.Code
+}
+
+// GetCodeIndexes returns the coupon index each of a campaign's codes was
+// generated from. Coupons created before indexes were recorded are missing
+// from the result.
+func (r *CouponRepository) GetCodeIndexes(ctx context.Context, db DBExecutor, campaignID int64, codes []string) (map[string]int64, error) {
+	defer observeQuery("CouponRepository.GetCodeIndexes", time.Now())
+
+	query := `
+		SELECT code, code_index
+		FROM coupons
+		WHERE campaign_id = $1 AND code = ANY($2) AND code_index IS NOT NULL
+	`
+
+	var rows []struct {
+		Code  string `db:"code"`
+		Index int64  `db:"code_index"`
+	}
+	if err := db.SelectContext(ctx, &rows, query, campaignID, pq.Array(codes)); err != nil {
+		return nil, fmt.Errorf("failed to get coupon indexes: %w", err)
+	}
+
+	indexes := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		indexes[row.Code] = row.Index
+	}
+	return indexes, nil
+}
+
+// ListCodeIndexes returns up to limit coupons of a campaign with their code
+// indexes, ordered by code and starting after the given code ("" for the
+// first page)
+func (r *CouponRepository) ListCodeIndexes(ctx context.Context, db DBExecutor, campaignID int64, after string, limit int) ([]model.CodeIndex, error) {
+	defer observeQuery("CouponRepository.ListCodeIndexes", time.Now())
+
+	query := `
+		SELECT code, code_index
+		FROM coupons
+		WHERE campaign_id = $1 AND code > $2
+		ORDER BY code
+		LIMIT $3
+	`
+
+	var rows []model.CodeIndex
+	if err := db.SelectContext(ctx, &rows, query, campaignID, after, limit); err != nil {
+		return nil, fmt.Errorf("failed to list coupon indexes: %w", err)
+	}
+	return rows, nil
+}
+
+// DuplicateCodeIndexes returns up to limit code indexes shared by more than
+// one coupon of a campaign
+func (r *CouponRepository) DuplicateCodeIndexes(ctx context.Context, db DBExecutor, campaignID int64, limit int) ([]int64, error) {
+	defer observeQuery("CouponRepository.DuplicateCodeIndexes", time.Now())
+
+	query := `
+		SELECT code_index
+		FROM coupons
+		WHERE campaign_id = $1 AND code_index IS NOT NULL
+		GROUP BY code_index
+		HAVING COUNT(*) > 1
+		ORDER BY code_index
+		LIMIT $2
+	`
+
+	var indexes []int64
+	if err := db.SelectContext(ctx, &indexes, query, campaignID, limit); err != nil {
+		return nil, fmt.Errorf("failed to find duplicate coupon indexes: %w", err)
+	}
+	return indexes, nil
+}
+
+// escapeLike escapes LIKE wildcards so the input is matched literally
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+// PeekAvailable returns up to limit available coupon codes of a campaign
+// unexpired at now, in reservation order. Unlike ReserveAvailableCoupons it
+// takes no row locks and never changes a coupon's status.
+func (r *CouponRepository) PeekAvailable(ctx context.Context, db DBExecutor, campaignID int64, order model.ReservationOrder, limit int, now time.Time) ([]string, error) {
+	defer observeQuery("CouponRepository.PeekAvailable", time.Now())
+
+	query := `
+		SELECT code
+		FROM coupons
+		WHERE campaign_id = $1 AND status = 'available' AND ` + unexpired("$3") + `
+		ORDER BY ` + reservationOrder(order) + `
+		LIMIT $2
+	`
+
+	var codes []string
+	if err := db.SelectContext(ctx, &codes, query, campaignID, limit, now); err != nil {
+		return nil, fmt.Errorf("failed to peek coupons: %w", err)
+	}
+
+	return codes, nil
+}
+
+// CreatePregeneratedCoupons creates multiple coupons in batch within existing transaction.
+// Every coupon gets the pool, priority and expiry of group and the given metadata (nil for none).
+// The coupons get consecutive coupon indexes from firstIndex and are stamped
+// with createdAt. A non-nil shuffleSeed gives the coupons their shuffled
+// issuance order.
+func (r *CouponRepository) CreatePregeneratedCoupons(ctx context.Context, tx *sqlx.Tx, campaignID int64, couponCodes []string, firstIndex int64, group model.CouponGroup, metadata model.Metadata, shuffleSeed *int64, createdAt time.Time) error {
+	defer observeQuery("CouponRepository.CreatePregeneratedCoupons", time.Now())
 
 	// 배치 크기 설정 (PostgreSQL 파라미터 제한 고려)
 	batchSize := 1000
@@ -82,7 +704,10 @@ func (r *CouponRepository) CreatePregeneratedCoupons(tx *sqlx.Tx, campaignID int
 		}
 
 		batch := couponCodes[i:end]
-		if err := r.insertCouponBatch(tx, campaignID, batch, now); err != nil {
+		if err := r.insertCouponBatch(ctx, tx, campaignID, batch, firstIndex+int64(i), sortKeys(shuffleSeed, batch), group, metadata, createdAt); err != nil {
+			if errors.Is(err, ErrCouponCodeExists) {
+				return err
+			}
 			return fmt.Errorf("failed to insert coupon batch: %w", err)
 		}
 	}
@@ -91,28 +716,34 @@ func (r *CouponRepository) CreatePregeneratedCoupons(tx *sqlx.Tx, campaignID int
 }
 
 // insertCouponBatch inserts a batch of coupons using a single query
-func (r *CouponRepository) insertCouponBatch(tx *sqlx.Tx, campaignID int64, codes []string, createdAt time.Time) error {
+func (r *CouponRepository) insertCouponBatch(ctx context.Context, tx *sqlx.Tx, campaignID int64, codes []string, firstIndex int64, keys []int64, group model.CouponGroup, metadata model.Metadata, createdAt time.Time) error {
 	if len(codes) == 0 {
 		return nil
 	}
 
 	// VALUES 절을 동적으로 생성
+	// 공통 값(campaign_id, created_at, metadata, pool, 첫 인덱스, priority, expires_at)은 한 번만 바인딩
 	valuesClause := make([]string, len(codes))
-	args := make([]interface{}, 0, len(codes)*4)
+	args := make([]interface{}, 0, 2*len(codes)+7)
+	args = append(args, campaignID, createdAt, metadata, group.Pool, firstIndex, group.Priority, group.ExpiresAt)
 
 	for i, code := range codes {
-		valuesClause[i] = fmt.Sprintf("($%d, $%d, $%d, $%d)",
-			i*4+1, i*4+2, i*4+3, i*4+4)
-		args = append(args, code, campaignID, "available", createdAt)
+		valuesClause[i] = fmt.Sprintf("($%d, $1, 'available', $2, $3::jsonb, $4, $%d, $5::bigint + %d, $6::integer, $7::timestamptz)", 2*i+8, 2*i+9, i)
+		args = append(args, code, keys[i])
 	}
 
 	query := fmt.Sprintf(`
-		INSERT INTO coupons (code, campaign_id, status, created_at)
+		INSERT INTO coupons (code, campaign_id, status, created_at, metadata, pool, sort_key, code_index, priority, expires_at)
 		VALUES %s
 	`, strings.Join(valuesClause, ", "))
 
-	_, err := tx.Exec(query, args...)
+	_, err := tx.ExecContext(ctx, query, args...)
 	if err != nil {
+		// Generated codes collided, within the batch or with stored codes
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == pgUniqueViolation {
+			return ErrCouponCodeExists
+		}
 		return fmt.Errorf("failed to execute batch insert: %w", err)
 	}
 
@@ -0,0 +1,19 @@
+package repository
+
+import "errors"
+
+// Errors returned by the repositories for outcomes callers act on. Match them
+// with errors.Is; their messages may change.
+var (
+	ErrCampaignNotFound     = errors.New("campaign not found")
+	ErrCampaignSlugExists   = errors.New("campaign slug already exists")
+	ErrCampaignHandedOut    = errors.New("campaign has handed out coupons")
+	ErrCampaignNotDeleted   = errors.New("campaign is not deleted")
+	ErrCouponNotFound       = errors.New("coupon not found")
+	ErrCouponCodeExists     = errors.New("coupon code already exists")
+	ErrTargetCodesExist     = errors.New("coupon codes already exist in the target campaign")
+	ErrCouponsReserved      = errors.New("coupons are currently reserved")
+	ErrNoAvailableCoupons   = errors.New("no available coupons")
+	ErrIdempotencyKeyAbsent = errors.New("idempotency key not found")
+	ErrInvalidPageCursor    = errors.New("invalid page cursor")
+)
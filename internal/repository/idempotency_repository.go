@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/kkkkikiki/coupon/internal/model"
+)
+
+// IdempotencyRepository stores the outcome of idempotent IssueCoupon calls
+type IdempotencyRepository struct{}
+
+// NewIdempotencyRepository creates a new idempotency repository
+func NewIdempotencyRepository() *IdempotencyRepository {
+	return &IdempotencyRepository{}
+}
+
+// GetIssuedCoupon returns the coupon recorded for an idempotency key
+func (r *IdempotencyRepository) GetIssuedCoupon(ctx context.Context, db DBExecutor, key string) (*model.IssueIdempotencyKey, error) {
+	defer observeQuery("IdempotencyRepository.GetIssuedCoupon", time.Now())
+
+	query := `
+		SELECT idempotency_key, campaign_id, coupon_code, created_at
+		FROM issue_idempotency_keys
+		WHERE idempotency_key = $1
+	`
+
+	var record model.IssueIdempotencyKey
+	err := db.GetContext(ctx, &record, query, key)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrIdempotencyKeyAbsent
+		}
+		return nil, fmt.Errorf("failed to get idempotency key: %w", err)
+	}
+
+	return &record, nil
+}
+
+// SaveIssuedCoupon records the coupon issued for an idempotency key at
+// createdAt, from which the key's TTL runs. It returns false without error when the key was already recorded, e.g. by
+// a concurrent request with the same key.
+func (r *IdempotencyRepository) SaveIssuedCoupon(ctx context.Context, db DBExecutor, key string, campaignID int64, couponCode string, createdAt time.Time) (bool, error) {
+	defer observeQuery("IdempotencyRepository.SaveIssuedCoupon", time.Now())
+
+	query := `
+		INSERT INTO issue_idempotency_keys (idempotency_key, campaign_id, coupon_code, created_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (idempotency_key) DO NOTHING
+	`
+
+	result, err := db.ExecContext(ctx, query, key, campaignID, couponCode, createdAt)
+	if err != nil {
+		return false, fmt.Errorf("failed to save idempotency key: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected == 1, nil
+}
+
+// DeleteExpiredKeys removes up to limit idempotency keys created before the
+// given instant and returns how many were deleted
+func (r *IdempotencyRepository) DeleteExpiredKeys(ctx context.Context, db DBExecutor, before time.Time, limit int) (int64, error) {
+	defer observeQuery("IdempotencyRepository.DeleteExpiredKeys", time.Now())
+
+	query := `
+		DELETE FROM issue_idempotency_keys
+		WHERE idempotency_key IN (
+			SELECT idempotency_key
+			FROM issue_idempotency_keys
+			WHERE created_at < $1
+			LIMIT $2
+		)
+	`
+
+	result, err := db.ExecContext(ctx, query, before, limit)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired idempotency keys: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected, nil
+}
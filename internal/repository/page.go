@@ -0,0 +1,10 @@
+package repository
+
+// Page selects a window of a keyset-paginated query
+type Page struct {
+	// After is the sort key of the last row of the previous page; empty for
+	// the first page
+	After string
+	// Limit is the maximum number of rows to return
+	Limit int
+}
@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"context"
+	"log/slog"
+	"math/rand/v2"
+	"os"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// planSampleRate is the fraction of sampled queries whose plan is logged.
+// Zero disables plan sampling. It is set once at startup.
+var planSampleRate float64
+
+// planLogger writes sampled query plans as JSON lines at debug level
+var planLogger = slog.New(slog.NewJSONHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+// SetPlanSampleRate enables logging the plan of hot-path queries for the
+// given fraction of calls. It is meant for debug deployments only, since
+// each sample runs an extra EXPLAIN. It must be called before serving
+// requests.
+func SetPlanSampleRate(rate float64) {
+	planSampleRate = rate
+}
+
+// explainSampled logs the plan of query for a sampled fraction of calls. The
+// EXPLAIN runs without ANALYZE, so the query is planned but not executed, and
+// inside a savepoint so a failure can't abort the caller's transaction.
+func explainSampled(ctx context.Context, tx *sqlx.Tx, operation string, campaignID int64, query string, args ...interface{}) {
+	if planSampleRate <= 0 || rand.Float64() >= planSampleRate {
+		return
+	}
+
+	if _, err := tx.ExecContext(ctx, "SAVEPOINT query_plan"); err != nil {
+		planLogger.Debug("query plan failed", "operation", operation, "campaign_id", campaignID, "error", err.Error())
+		return
+	}
+	var plan []string
+	if err := tx.SelectContext(ctx, &plan, "EXPLAIN "+query, args...); err != nil {
+		tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT query_plan")
+		planLogger.Debug("query plan failed", "operation", operation, "campaign_id", campaignID, "error", err.Error())
+		return
+	}
+	tx.ExecContext(ctx, "RELEASE SAVEPOINT query_plan")
+
+	planLogger.Debug("query plan",
+		"operation", operation,
+		"campaign_id", campaignID,
+		"plan", strings.Join(plan, "\n"),
+	)
+}
@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/kkkkikiki/coupon/internal/metrics"
+)
+
+// slowQueryThreshold is the duration above which a repository call is logged
+// as slow. Zero disables slow query logging. It is set once at startup.
+var slowQueryThreshold time.Duration
+
+// slowQueryLogger writes slow query reports as JSON lines
+var slowQueryLogger = slog.New(slog.NewJSONHandler(os.Stderr, nil))
+
+// SetSlowQueryThreshold enables slow query logging for repository calls
+// taking longer than threshold. It must be called before serving requests.
+func SetSlowQueryThreshold(threshold time.Duration) {
+	slowQueryThreshold = threshold
+}
+
+// observeQuery reports the repository operation started at start if it
+// exceeded the slow query threshold. Query parameters are never logged since
+// they may contain coupon codes.
+func observeQuery(operation string, start time.Time) {
+	if slowQueryThreshold <= 0 {
+		return
+	}
+
+	duration := time.Since(start)
+	if duration < slowQueryThreshold {
+		return
+	}
+
+	slowQueryLogger.Warn("slow query",
+		"operation", operation,
+		"duration_ms", duration.Milliseconds(),
+		"threshold_ms", slowQueryThreshold.Milliseconds(),
+	)
+	metrics.RecordSlowQuery(operation)
+}
@@ -0,0 +1,25 @@
+package repository
+
+import (
+	"testing"
+	"time"
+)
+
+func BenchmarkObserveQuery(b *testing.B) {
+	for _, bc := range []struct {
+		name      string
+		threshold time.Duration
+	}{
+		{"disabled", 0},
+		{"under threshold", time.Hour},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			SetSlowQueryThreshold(bc.threshold)
+			defer SetSlowQueryThreshold(0)
+
+			for i := 0; i < b.N; i++ {
+				observeQuery("CouponRepository.ReserveAvailableCoupon", time.Now())
+			}
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+
+	"github.com/kkkkikiki/coupon/internal/model"
+)
+
+// reservationOrders are the orders in which available coupons are issued.
+// sort_key is 0 unless the campaign is shuffled, so unshuffled campaigns fall
+// back to created_at; every order keeps it ahead of created_at so a shuffled
+// campaign's keys decide ties whatever its order. code breaks the remaining
+// ties. Each is served by an index on its columns.
+var reservationOrders = map[model.ReservationOrder]string{
+	model.ReservationOrderCreatedAt: `sort_key, created_at, code`,
+	model.ReservationOrderExpiresAt: `expires_at, sort_key, created_at, code`,
+	model.ReservationOrderPriority:  `priority DESC, sort_key, created_at, code`,
+}
+
+// reservationOrder returns the ORDER BY list of order, creation order for
+// unknown orders
+func reservationOrder(order model.ReservationOrder) string {
+	if columns, ok := reservationOrders[order]; ok {
+		return columns
+	}
+	return reservationOrders[model.ReservationOrderCreatedAt]
+}
+
+// unexpired restricts reservations to coupons whose pool expiry hasn't passed
+// at the instant bound to param, the server clock's now
+func unexpired(param string) string {
+	return `(expires_at IS NULL OR expires_at > ` + param + `)`
+}
+
+// ShuffleSortKey returns the sort key of a coupon of a shuffled campaign: the
+// first 8 bytes of SHA-256(seed as 8 big-endian bytes || code) read as a
+// big-endian signed integer. Sorting a campaign's codes by (key, code)
+// reproduces its issuance order.
+func ShuffleSortKey(seed int64, code string) int64 {
+	h := sha256.New()
+	binary.Write(h, binary.BigEndian, seed)
+	h.Write([]byte(code))
+	return int64(binary.BigEndian.Uint64(h.Sum(nil)))
+}
+
+// sortKeys returns the sort keys of codes for a campaign with the given
+// shuffle seed, all 0 for a campaign without one
+func sortKeys(shuffleSeed *int64, codes []string) []int64 {
+	keys := make([]int64, len(codes))
+	if shuffleSeed == nil {
+		return keys
+	}
+	for i, code := range codes {
+		keys[i] = ShuffleSortKey(*shuffleSeed, code)
+	}
+	return keys
+}
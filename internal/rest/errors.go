@@ -0,0 +1,65 @@
+package rest
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"connectrpc.com/connect"
+
+	"github.com/kkkkikiki/coupon/internal/interceptor"
+)
+
+// errorBody is the JSON body of a failed request
+type errorBody struct {
+	Status  string `json:"status"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// httpStatus maps connect error codes to HTTP statuses, following the
+// connect protocol
+var httpStatus = map[connect.Code]int{
+	connect.CodeCanceled:           499,
+	connect.CodeUnknown:            http.StatusInternalServerError,
+	connect.CodeInvalidArgument:    http.StatusBadRequest,
+	connect.CodeDeadlineExceeded:   http.StatusGatewayTimeout,
+	connect.CodeNotFound:           http.StatusNotFound,
+	connect.CodeAlreadyExists:      http.StatusConflict,
+	connect.CodePermissionDenied:   http.StatusForbidden,
+	connect.CodeResourceExhausted:  http.StatusTooManyRequests,
+	connect.CodeFailedPrecondition: http.StatusBadRequest,
+	connect.CodeAborted:            http.StatusConflict,
+	connect.CodeOutOfRange:         http.StatusBadRequest,
+	connect.CodeUnimplemented:      http.StatusNotImplemented,
+	connect.CodeInternal:           http.StatusInternalServerError,
+	connect.CodeUnavailable:        http.StatusServiceUnavailable,
+	connect.CodeDataLoss:           http.StatusInternalServerError,
+	connect.CodeUnauthenticated:    http.StatusUnauthorized,
+}
+
+// writeError writes err as a JSON error response, translating service errors
+// like the connect handler does. Metadata of a connect error, such as
+// Retry-After, is copied to the response headers.
+func writeError(w http.ResponseWriter, err error) {
+	err = interceptor.ConnectError(err)
+	code := connect.CodeOf(err)
+	message := err.Error()
+	var connectErr *connect.Error
+	if errors.As(err, &connectErr) {
+		message = connectErr.Message()
+		for key, values := range connectErr.Meta() {
+			for _, value := range values {
+				w.Header().Add(key, value)
+			}
+		}
+	}
+
+	status, ok := httpStatus[code]
+	if !ok {
+		status = http.StatusInternalServerError
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(errorBody{Status: "error", Code: code.String(), Message: message})
+}
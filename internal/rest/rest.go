@@ -0,0 +1,167 @@
+// Package rest serves a minimal JSON REST facade over the core coupon
+// operations for clients that can't use connect or gRPC. The connect endpoint
+// remains the primary API; the facade covers only creating, reading and
+// issuing from campaigns.
+package rest
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"connectrpc.com/connect"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	couponv1 "github.com/kkkkikiki/coupon/gen/coupon/v1"
+	"github.com/kkkkikiki/coupon/gen/coupon/v1/couponv1connect"
+	"github.com/kkkkikiki/coupon/internal/interceptor"
+)
+
+// Options configures the REST facade
+type Options struct {
+	// MaxBodyBytes caps the request body size; 0 means unlimited
+	MaxBodyBytes int64
+	// CreateLimit and IssueLimit are the concurrency limits also guarding
+	// the connect procedures, so the facade can't bypass them
+	CreateLimit *interceptor.ConcurrencyLimit
+	IssueLimit  *interceptor.ConcurrencyLimit
+}
+
+type handler struct {
+	svc  couponv1connect.CouponServiceHandler
+	opts Options
+}
+
+// NewHandler returns the REST facade over svc. It calls the service directly
+// rather than through a connect client, and encodes messages with the same
+// JSON mapping as the connect endpoint:
+//
+//	POST /v1/campaigns             body: CreateCampaignRequest
+//	GET  /v1/campaigns/{id}
+//	POST /v1/campaigns/{id}/issue  body: IssueCouponRequest (optional)
+//
+// Errors are returned as {"status":"error","code":...,"message":...} with the
+// HTTP status connect uses for the error code.
+func NewHandler(svc couponv1connect.CouponServiceHandler, opts Options) http.Handler {
+	h := &handler{svc: svc, opts: opts}
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /v1/campaigns", h.createCampaign)
+	mux.HandleFunc("GET /v1/campaigns/{id}", h.getCampaign)
+	mux.HandleFunc("POST /v1/campaigns/{id}/issue", h.issueCoupon)
+	return mux
+}
+
+func (h *handler) createCampaign(w http.ResponseWriter, r *http.Request) {
+	msg := &couponv1.CreateCampaignRequest{}
+	if err := h.readBody(w, r, msg); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	release, err := h.opts.CreateLimit.Acquire()
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	defer release()
+
+	res, err := h.svc.CreateCampaign(r.Context(), connect.NewRequest(msg))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeMessage(w, http.StatusCreated, res.Msg)
+}
+
+func (h *handler) getCampaign(w http.ResponseWriter, r *http.Request) {
+	id, err := campaignID(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	res, err := h.svc.GetCampaign(r.Context(), connect.NewRequest(&couponv1.GetCampaignRequest{
+		Campaign: &couponv1.GetCampaignRequest_CampaignId{CampaignId: id},
+	}))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeMessage(w, http.StatusOK, res.Msg)
+}
+
+func (h *handler) issueCoupon(w http.ResponseWriter, r *http.Request) {
+	id, err := campaignID(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	msg := &couponv1.IssueCouponRequest{}
+	if err := h.readBody(w, r, msg); err != nil {
+		writeError(w, err)
+		return
+	}
+	// The path names the campaign; a campaign in the body is overridden
+	msg.Campaign = &couponv1.IssueCouponRequest_CampaignId{CampaignId: id}
+
+	release, err := h.opts.IssueLimit.Acquire()
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	defer release()
+
+	res, err := h.svc.IssueCoupon(r.Context(), connect.NewRequest(msg))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeMessage(w, http.StatusOK, res.Msg)
+}
+
+// campaignID parses the campaign ID path segment
+func campaignID(r *http.Request) (int64, error) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil || id <= 0 {
+		return 0, connect.NewError(connect.CodeInvalidArgument, errors.New("invalid campaign id"))
+	}
+	return id, nil
+}
+
+// readBody decodes the JSON request body into msg. An empty body leaves msg
+// unset.
+func (h *handler) readBody(w http.ResponseWriter, r *http.Request, msg proto.Message) error {
+	body := r.Body
+	if h.opts.MaxBodyBytes > 0 {
+		body = http.MaxBytesReader(w, r.Body, h.opts.MaxBodyBytes)
+	}
+	data, err := io.ReadAll(body)
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			return connect.NewError(connect.CodeResourceExhausted, errors.New("request body too large"))
+		}
+		return connect.NewError(connect.CodeInvalidArgument, errors.New("failed to read request body"))
+	}
+	if len(data) == 0 {
+		return nil
+	}
+	if err := protojson.Unmarshal(data, msg); err != nil {
+		return connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	return nil
+}
+
+// writeMessage writes msg as the JSON response body
+func writeMessage(w http.ResponseWriter, status int, msg proto.Message) {
+	data, err := protojson.Marshal(msg)
+	if err != nil {
+		writeError(w, connect.NewError(connect.CodeInternal, err))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(data)
+}
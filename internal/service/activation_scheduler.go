@@ -0,0 +1,220 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/kkkkikiki/coupon/internal/model"
+)
+
+const (
+	// activationPollInterval bounds the sleep between scans so campaigns
+	// created on other replicas are picked up without a rescan signal
+	activationPollInterval = time.Minute
+	// activationRetryInterval is the wait after a failed scan
+	activationRetryInterval = 5 * time.Second
+	// activationOverlap re-reads this much of the already scanned window, so
+	// campaigns committed just after a scan, or stamped by a DB clock that
+	// lags ours, are not missed
+	activationOverlap = 10 * time.Second
+	// activationWebhookTimeout bounds a single webhook delivery
+	activationWebhookTimeout = 5 * time.Second
+)
+
+// campaignStartedEvent is the webhook payload sent when a campaign starts
+type campaignStartedEvent struct {
+	Event      string    `json:"event"`
+	CampaignID int64     `json:"campaign_id"`
+	StartDate  time.Time `json:"start_date"`
+}
+
+// ActivationScheduler runs side effects when campaigns reach their
+// start_date: it preloads the campaign's remaining count into the cache ahead
+// of the first issuance wave and, when configured, posts a "campaign.started"
+// webhook.
+//
+// Campaigns that were already active when the scheduler started are not
+// announced again; campaigns created later with a start_date in the past are
+// announced right away. Every replica runs its own scheduler, so webhook
+// receivers should treat campaign_id as an idempotency key.
+type ActivationScheduler struct {
+	server     *CouponServer
+	webhookURL string
+	httpClient *http.Client
+
+	rescan chan struct{}
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	// since and fired are only touched by the scan loop
+	since time.Time
+	fired map[int64]time.Time
+}
+
+// NewActivationScheduler creates a scheduler for server. Campaign creation and
+// restore on server trigger a rescan. An empty webhookURL disables the
+// webhook.
+func NewActivationScheduler(server *CouponServer, webhookURL string) *ActivationScheduler {
+	a := &ActivationScheduler{
+		server:     server,
+		webhookURL: webhookURL,
+		httpClient: &http.Client{Timeout: activationWebhookTimeout},
+		rescan:     make(chan struct{}, 1),
+		fired:      make(map[int64]time.Time),
+	}
+	server.activations = a
+	return a
+}
+
+// Start launches the scheduler loop
+func (a *ActivationScheduler) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	a.cancel = cancel
+	a.since = a.server.clock.Now()
+
+	a.wg.Add(1)
+	go func() {
+		defer a.wg.Done()
+
+		for {
+			timer := time.NewTimer(a.scan(ctx))
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case <-a.rescan:
+				timer.Stop()
+			case <-timer.C:
+			}
+		}
+	}()
+}
+
+// Stop stops the scheduler and waits for pending webhooks to finish
+func (a *ActivationScheduler) Stop() {
+	if a.cancel == nil {
+		return
+	}
+	a.cancel()
+	a.wg.Wait()
+}
+
+// Rescan asks the scheduler to re-read upcoming start dates. It never blocks.
+func (a *ActivationScheduler) Rescan() {
+	select {
+	case a.rescan <- struct{}{}:
+	default:
+	}
+}
+
+// scan activates due campaigns and returns how long to sleep until the next
+// start_date
+func (a *ActivationScheduler) scan(ctx context.Context) time.Duration {
+	now := a.server.clock.Now()
+	windowStart := a.since.Add(-activationOverlap)
+
+	var due []model.Campaign
+	var next time.Time
+	var scheduled bool
+	err := a.server.guardDB(ctx, func() error {
+		var err error
+		due, err = a.server.campaignRepo.ListDueActivations(ctx, a.server.postgres, windowStart, now)
+		if err != nil {
+			return err
+		}
+		next, scheduled, err = a.server.campaignRepo.NextActivation(ctx, a.server.postgres, now)
+		return err
+	})
+	if err != nil {
+		if ctx.Err() == nil {
+			log.Printf("Campaign activation scan failed: %v", err)
+		}
+		return activationRetryInterval
+	}
+
+	for i := range due {
+		if _, ok := a.fired[due[i].ID]; ok {
+			continue
+		}
+		a.fired[due[i].ID] = now
+		a.activate(ctx, &due[i])
+	}
+
+	// Forget campaigns that have left the overlap window
+	for id, firedAt := range a.fired {
+		if firedAt.Before(now.Add(-2 * activationOverlap)) {
+			delete(a.fired, id)
+		}
+	}
+	a.since = now
+
+	if !scheduled {
+		return activationPollInterval
+	}
+	return min(max(time.Until(next), 0), activationPollInterval)
+}
+
+// activate runs the side effects of a campaign starting
+func (a *ActivationScheduler) activate(ctx context.Context, campaign *model.Campaign) {
+	log.Printf("Campaign %d started", campaign.ID)
+
+	// Count now so the first wave of GetRemaining polls hits the cache
+	var count int32
+	err := a.server.guardDB(ctx, func() error {
+		var err error
+		count, err = a.server.campaignRepo.CountAvailableCoupons(ctx, a.server.postgres, campaign.ID, a.server.clock.Now())
+		return err
+	})
+	if err != nil {
+		log.Printf("Failed to preload remaining count for campaign %d: %v", campaign.ID, err)
+	} else {
+		a.server.remaining.set(campaign.ID, count)
+	}
+
+	if a.webhookURL == "" {
+		return
+	}
+	event := campaignStartedEvent{
+		Event:      "campaign.started",
+		CampaignID: campaign.ID,
+		StartDate:  campaign.StartDate,
+	}
+	a.wg.Add(1)
+	go func() {
+		defer a.wg.Done()
+		if err := a.postWebhook(ctx, event); err != nil {
+			log.Printf("Campaign %d started webhook failed: %v", event.CampaignID, err)
+		}
+	}()
+}
+
+// postWebhook delivers a single event to the webhook URL
+func (a *ActivationScheduler) postWebhook(ctx context.Context, event campaignStartedEvent) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode event: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := a.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return nil
+}
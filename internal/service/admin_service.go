@@ -0,0 +1,548 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"connectrpc.com/connect"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	couponv1 "github.com/kkkkikiki/coupon/gen/coupon/v1"
+	"github.com/kkkkikiki/coupon/internal/model"
+	"github.com/kkkkikiki/coupon/internal/repository"
+)
+
+const (
+	// maxCouponIndex is the size of the per-campaign coupon index space
+	// (the lower 32 bits of the generation sequence)
+	maxCouponIndex = 1 << 32
+	// revokeBatchSize is the number of coupons revoked per statement
+	revokeBatchSize = 1000
+	// drainBatchSize is the number of coupons issued per drain transaction
+	drainBatchSize = 1000
+	// defaultPeekLimit is the PeekAvailableCoupons limit when unset
+	defaultPeekLimit = 10
+)
+
+// RegenerateCoupons replaces all unissued coupon codes of a campaign with codes
+// generated under an updated code format. Issued coupons are left untouched.
+// Fails with FailedPrecondition if any available coupon is currently reserved
+// by an in-flight issuance. With dry_run set, the same checks run and sample
+// codes in the new format are returned, but nothing is changed.
+func (s *CouponServer) RegenerateCoupons(
+	ctx context.Context,
+	req *connect.Request[couponv1.RegenerateCouponsRequest],
+) (*connect.Response[couponv1.RegenerateCouponsResponse], error) {
+	var campaign *model.Campaign
+	var regenerated int
+	var sampleCodes []string
+	err := s.guardDB(ctx, func() error {
+		tx, err := s.postgres.BeginTxx(ctx, nil)
+		if err != nil {
+			return newServiceError(ErrDB, fmt.Errorf("failed to begin transaction: %w", err))
+		}
+		defer tx.Rollback()
+
+		// Lock the campaign so concurrent regenerations serialize
+		campaign, err = s.campaignRepo.LockCampaign(ctx, tx, req.Msg.CampaignId)
+		if err != nil {
+			if errors.Is(err, repository.ErrCampaignNotFound) {
+				return newServiceError(ErrCampaignNotFound, err)
+			}
+			return newServiceError(ErrDB, fmt.Errorf("failed to get campaign: %w", err))
+		}
+
+		deleted, err := s.couponRepo.DeleteAvailableCoupons(ctx, tx, campaign.ID)
+		if err != nil {
+			if errors.Is(err, repository.ErrCouponsReserved) {
+				return newServiceError(ErrFailedPrecondition, err)
+			}
+			return newServiceError(ErrDB, err)
+		}
+		// Each pool gets back as many fresh codes as it lost, with the same
+		// priorities and expiries
+		allocations := poolAllocations(deleted)
+		regenerated = allocatedCount(allocations)
+
+		alphabet, length, prefix, routingTag, err := resolveCodeFormat(req.Msg.CodeFormat, toProtoCodeFormat(campaign), int32(regenerated))
+		if err != nil {
+			return newServiceError(ErrInvalidArgument, err)
+		}
+		if err := s.checkCodeEntropy(alphabet, length, prefix, routingTag, int32(regenerated)); err != nil {
+			return newServiceError(ErrInvalidArgument, fmt.Errorf("code format %w", err))
+		}
+		// Replays of hashed issuances regenerate the code in the current
+		// format, so issued coupons must keep theirs
+		if s.hashedStorage() && (alphabet != campaign.CodeAlphabet || length != campaign.CodeLength || prefix != campaign.CodePrefix ||
+			routingTag != campaign.CodeRoutingTag) {
+			return newServiceError(ErrFailedPrecondition, fmt.Errorf("code format can't change with hashed code storage"))
+		}
+		if campaign.NextCodeIndex+int64(regenerated) > maxCouponIndex {
+			return newServiceError(ErrFailedPrecondition, fmt.Errorf("campaign coupon index space exhausted"))
+		}
+
+		// New codes use fresh coupon indexes so they can't collide with
+		// previously generated codes in the same format
+		start := uint64(campaign.NextCodeIndex)
+		campaign.CodeAlphabet = alphabet
+		campaign.CodeLength = length
+		campaign.CodePrefix = prefix
+		campaign.CodeRoutingTag = routingTag
+		campaign.NextCodeIndex += int64(regenerated)
+
+		if req.Msg.DryRun {
+			// The deferred rollback restores the deleted coupons
+			sampleCodes, err = s.generateCouponCodes(campaign, start, min(regenerated, defaultDryRunSamples))
+			if err != nil {
+				return generationFailure(err)
+			}
+			return nil
+		}
+
+		if err := s.campaignRepo.UpdateCodeFormat(ctx, tx, campaign); err != nil {
+			return newServiceError(ErrDB, err)
+		}
+
+		if err := s.storeCoupons(ctx, tx, campaign, start, allocations); err != nil {
+			return err
+		}
+
+		if err := tx.Commit(); err != nil {
+			return newServiceError(ErrDB, fmt.Errorf("failed to commit transaction: %w", err))
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res := connect.NewResponse(&couponv1.RegenerateCouponsResponse{
+		RegeneratedCount: int32(regenerated),
+		Campaign:         toProtoCampaign(campaign, nil),
+		SampleCodes:      sampleCodes,
+	})
+
+	return res, nil
+}
+
+// DeleteCampaign soft-deletes a campaign. It disappears from reads and can no
+// longer issue coupons, but all rows are kept and RestoreCampaign undoes it.
+// A campaign with any coupon that is no longer available is only deleted with
+// force set, so cleanup scripts can't hide coupons users already hold by
+// accident.
+func (s *CouponServer) DeleteCampaign(
+	ctx context.Context,
+	req *connect.Request[couponv1.DeleteCampaignRequest],
+) (*connect.Response[couponv1.DeleteCampaignResponse], error) {
+	var deletedAt time.Time
+	err := s.guardDB(ctx, func() error {
+		tx, err := s.postgres.BeginTxx(ctx, nil)
+		if err != nil {
+			return newServiceError(ErrDB, fmt.Errorf("failed to begin transaction: %w", err))
+		}
+		defer tx.Rollback()
+
+		deletedAt, err = s.campaignRepo.SoftDeleteCampaign(ctx, tx, req.Msg.CampaignId, s.clock.Now(), req.Msg.Force)
+		if err != nil {
+			switch {
+			case errors.Is(err, repository.ErrCampaignNotFound):
+				return newServiceError(ErrCampaignNotFound, err)
+			case errors.Is(err, repository.ErrCampaignHandedOut):
+				handedOut, err := s.couponRepo.CountHandedOutCoupons(ctx, tx, req.Msg.CampaignId)
+				if err != nil {
+					return newServiceError(ErrDB, err)
+				}
+				return newServiceError(ErrFailedPrecondition,
+					fmt.Errorf("campaign has %d coupons that are no longer available; set force to delete it anyway", handedOut))
+			}
+			return newServiceError(ErrDB, err)
+		}
+
+		if err := tx.Commit(); err != nil {
+			return newServiceError(ErrDB, fmt.Errorf("failed to commit transaction: %w", err))
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res := connect.NewResponse(&couponv1.DeleteCampaignResponse{
+		DeletedAt: timestamppb.New(deletedAt),
+	})
+
+	return res, nil
+}
+
+// UpdateCampaign changes the issuance rate limit of a live campaign. The
+// limiter on this replica is updated right away; other replicas pick the new
+// rate up with their next issuance for the campaign.
+func (s *CouponServer) UpdateCampaign(
+	ctx context.Context,
+	req *connect.Request[couponv1.UpdateCampaignRequest],
+) (*connect.Response[couponv1.UpdateCampaignResponse], error) {
+	var v validator
+	v.add("max_issue_rps", validateMaxIssueRPS(req.Msg.MaxIssueRps))
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
+	var campaign *model.Campaign
+	err := s.guardDB(ctx, func() error {
+		var err error
+		campaign, err = s.campaignRepo.UpdateMaxIssueRPS(ctx, s.postgres, req.Msg.CampaignId, req.Msg.MaxIssueRps)
+		if err != nil {
+			if errors.Is(err, repository.ErrCampaignNotFound) {
+				return newServiceError(ErrCampaignNotFound, err)
+			}
+			return newServiceError(ErrDB, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.issueLimiter.setRate(campaign.ID, campaign.MaxIssueRPS)
+
+	res := connect.NewResponse(&couponv1.UpdateCampaignResponse{
+		Campaign: toProtoCampaign(campaign, nil),
+	})
+
+	return res, nil
+}
+
+// RestoreCampaign undoes the soft delete of a campaign
+func (s *CouponServer) RestoreCampaign(
+	ctx context.Context,
+	req *connect.Request[couponv1.RestoreCampaignRequest],
+) (*connect.Response[couponv1.RestoreCampaignResponse], error) {
+	var campaign *model.Campaign
+	err := s.guardDB(ctx, func() error {
+		var err error
+		campaign, err = s.campaignRepo.RestoreCampaign(ctx, s.postgres, req.Msg.CampaignId)
+		if err != nil {
+			switch {
+			case errors.Is(err, repository.ErrCampaignNotFound):
+				return newServiceError(ErrCampaignNotFound, err)
+			case errors.Is(err, repository.ErrCampaignNotDeleted):
+				return newServiceError(ErrFailedPrecondition, err)
+			}
+			return newServiceError(ErrDB, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.rescanActivations()
+
+	res := connect.NewResponse(&couponv1.RestoreCampaignResponse{
+		Campaign: toProtoCampaign(campaign, nil),
+	})
+
+	return res, nil
+}
+
+// TransferCoupons moves available coupons from one campaign to another,
+// updating the coupon counts of both campaigns. Issued or currently reserved
+// coupons cannot be transferred.
+func (s *CouponServer) TransferCoupons(
+	ctx context.Context,
+	req *connect.Request[couponv1.TransferCouponsRequest],
+) (*connect.Response[couponv1.TransferCouponsResponse], error) {
+	sourceID, targetID := req.Msg.SourceCampaignId, req.Msg.TargetCampaignId
+	if sourceID == targetID {
+		return nil, newServiceError(ErrInvalidArgument, fmt.Errorf("source and target campaigns must differ"))
+	}
+	// The upper bound is enforced by the request limits interceptor
+	if len(req.Msg.Codes) == 0 {
+		return nil, newServiceError(ErrInvalidArgument, fmt.Errorf("codes must not be empty"))
+	}
+	// A hashed coupon is revealed by regenerating its code with its campaign's
+	// key, which no longer works once it belongs to another campaign
+	if s.hashedStorage() {
+		return nil, newServiceError(ErrFailedPrecondition, fmt.Errorf("coupons can't be transferred with hashed code storage"))
+	}
+
+	// Deduplicate so the counts below match the moved rows
+	codes := make([]string, 0, len(req.Msg.Codes))
+	seen := make(map[string]bool, len(req.Msg.Codes))
+	for _, code := range req.Msg.Codes {
+		if !seen[code] {
+			seen[code] = true
+			codes = append(codes, code)
+		}
+	}
+
+	var transferred int64
+	err := s.guardDB(ctx, func() error {
+		tx, err := s.postgres.BeginTxx(ctx, nil)
+		if err != nil {
+			return newServiceError(ErrDB, fmt.Errorf("failed to begin transaction: %w", err))
+		}
+		defer tx.Rollback()
+
+		// Lock both campaigns in ID order to avoid deadlocks between opposite transfers
+		firstID, secondID := sourceID, targetID
+		if firstID > secondID {
+			firstID, secondID = secondID, firstID
+		}
+		var target *model.Campaign
+		for _, id := range []int64{firstID, secondID} {
+			campaign, err := s.campaignRepo.LockCampaign(ctx, tx, id)
+			if err != nil {
+				if errors.Is(err, repository.ErrCampaignNotFound) {
+					return newServiceError(ErrCampaignNotFound, fmt.Errorf("campaign %d not found", id))
+				}
+				return newServiceError(ErrDB, fmt.Errorf("failed to get campaign: %w", err))
+			}
+			if id == targetID {
+				target = campaign
+			}
+		}
+
+		coupons, err := s.couponRepo.LockCoupons(ctx, tx, sourceID, codes)
+		if err != nil {
+			if errors.Is(err, repository.ErrCouponsReserved) {
+				return newServiceError(ErrFailedPrecondition, err)
+			}
+			return newServiceError(ErrDB, err)
+		}
+		if len(coupons) != len(codes) {
+			return newServiceError(ErrCouponNotFound, fmt.Errorf("%d of %d coupons not found in campaign %d",
+				len(codes)-len(coupons), len(codes), sourceID))
+		}
+		for _, coupon := range coupons {
+			if coupon.Status != model.CouponStatusAvailable {
+				return newServiceError(ErrFailedPrecondition, fmt.Errorf("coupon %s is %s and cannot be transferred", coupon.Code, coupon.Status))
+			}
+		}
+
+		transferred, err = s.couponRepo.MoveCoupons(ctx, tx, codes, sourceID, targetID, target.ShuffleSeed)
+		if err != nil {
+			if errors.Is(err, repository.ErrTargetCodesExist) {
+				return newServiceError(ErrFailedPrecondition, err)
+			}
+			return newServiceError(ErrDB, err)
+		}
+
+		if err := s.campaignRepo.AdjustCouponCount(ctx, tx, sourceID, -int32(transferred)); err != nil {
+			return newServiceError(ErrDB, err)
+		}
+		if err := s.campaignRepo.AdjustCouponCount(ctx, tx, targetID, int32(transferred)); err != nil {
+			return newServiceError(ErrDB, err)
+		}
+
+		if err := tx.Commit(); err != nil {
+			return newServiceError(ErrDB, fmt.Errorf("failed to commit transaction: %w", err))
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res := connect.NewResponse(&couponv1.TransferCouponsResponse{
+		TransferredCount: int32(transferred),
+	})
+
+	return res, nil
+}
+
+// RevokeCampaignCoupons revokes all issued coupons of a campaign. Each batch
+// is its own statement so row locks are held only briefly and issuance of
+// the campaign's remaining coupons isn't blocked. Revoked coupons move from
+// the issued to the revoked count of GetCampaignStats; the available count and
+// the campaign's issued value are left unchanged, since revoked coupons must
+// not free stock or budget for new issuances. Coupons issued after the call
+// are not affected; delete the campaign first to stop issuance.
+func (s *CouponServer) RevokeCampaignCoupons(
+	ctx context.Context,
+	req *connect.Request[couponv1.RevokeCampaignCouponsRequest],
+) (*connect.Response[couponv1.RevokeCampaignCouponsResponse], error) {
+	var revoked int64
+	err := s.guardDB(ctx, func() error {
+		if _, err := s.campaignRepo.GetCampaign(ctx, s.postgres, req.Msg.CampaignId); err != nil {
+			if errors.Is(err, repository.ErrCampaignNotFound) {
+				return newServiceError(ErrCampaignNotFound, err)
+			}
+			return newServiceError(ErrDB, fmt.Errorf("failed to get campaign: %w", err))
+		}
+
+		for {
+			n, err := s.couponRepo.RevokeIssuedCoupons(ctx, s.postgres, req.Msg.CampaignId, revokeBatchSize)
+			if err != nil {
+				return newServiceError(ErrDB, err)
+			}
+			revoked += n
+			if n < revokeBatchSize {
+				return nil
+			}
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res := connect.NewResponse(&couponv1.RevokeCampaignCouponsResponse{
+		RevokedCount: int32(revoked),
+	})
+
+	return res, nil
+}
+
+// DrainCampaign issues all available coupons of a campaign to a recipient in
+// transactions of drainBatchSize coupons. Coupons are reserved with the same
+// SKIP LOCKED reservation as IssueCoupon, so concurrent issuances never get
+// the same coupon; the drain ends once no unlocked available coupon is left.
+// The budget is charged as usual, while the per-user limit and cooldown do
+// not apply. When a batch fails, earlier batches stay issued and the error
+// reports how many coupons were issued.
+func (s *CouponServer) DrainCampaign(
+	ctx context.Context,
+	req *connect.Request[couponv1.DrainCampaignRequest],
+) (*connect.Response[couponv1.DrainCampaignResponse], error) {
+	var v validator
+	v.check(req.Msg.Recipient != "", "recipient", "is required")
+	v.check(req.Msg.Confirm, "confirm", "must be true to drain a campaign")
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
+	var codes []string
+	err := s.guardDB(ctx, func() error {
+		campaign, err := s.campaignRepo.GetCampaign(ctx, s.postgres, req.Msg.CampaignId)
+		if err != nil {
+			if errors.Is(err, repository.ErrCampaignNotFound) {
+				return newServiceError(ErrCampaignNotFound, err)
+			}
+			return newServiceError(ErrDB, fmt.Errorf("failed to get campaign: %w", err))
+		}
+
+		for {
+			batch, err := s.drainBatch(ctx, campaign, req.Msg.Recipient)
+			if err != nil {
+				if len(codes) > 0 {
+					return newServiceError(errorCodeOf(err), fmt.Errorf("drain stopped after issuing %d coupons: %w", len(codes), err))
+				}
+				return err
+			}
+			codes = append(codes, batch...)
+			if len(batch) < drainBatchSize {
+				return nil
+			}
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res := connect.NewResponse(&couponv1.DrainCampaignResponse{
+		Codes:       codes,
+		IssuedCount: int32(len(codes)),
+	})
+
+	return res, nil
+}
+
+// drainBatch issues up to drainBatchSize available coupons of a campaign to
+// recipient in one transaction. It returns fewer coupons once the campaign or
+// its budget runs out.
+func (s *CouponServer) drainBatch(ctx context.Context, campaign *model.Campaign, recipient string) ([]string, error) {
+	tx, err := s.postgres.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, newServiceError(ErrDB, fmt.Errorf("failed to begin transaction: %w", err))
+	}
+	defer tx.Rollback()
+
+	reserved, err := s.couponRepo.ReserveAvailableCoupons(ctx, tx, campaign.ID, campaign.ReservationOrder, drainBatchSize, s.clock.Now())
+	if err != nil {
+		return nil, newServiceError(ErrDB, err)
+	}
+	if len(reserved) == 0 {
+		return nil, nil
+	}
+
+	if campaign.Budget > 0 {
+		charged, err := s.campaignRepo.ChargeBudget(ctx, tx, campaign.ID, int32(len(reserved)))
+		if err != nil {
+			return nil, newServiceError(ErrDB, err)
+		}
+		reserved = reserved[:charged]
+		if charged == 0 {
+			return nil, nil
+		}
+	}
+
+	if _, err := s.couponRepo.MarkCouponsAsIssued(ctx, tx, campaign.ID, reserved, recipient, s.clock.Now(), s.defaultCouponTTL); err != nil {
+		return nil, markIssuedError(fmt.Sprintf("drain batch of %d", len(reserved)), err)
+	}
+
+	revealed, err := s.revealCodes(ctx, tx, campaign, reserved)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, newServiceError(ErrDB, fmt.Errorf("failed to commit transaction: %w", err))
+	}
+
+	return revealed, nil
+}
+
+// PeekAvailableCoupons lists available coupons of a campaign without reserving
+// them. It runs in a read-only transaction so it can never issue a coupon,
+// and is kept apart from the reservation path used by IssueCoupon.
+func (s *CouponServer) PeekAvailableCoupons(
+	ctx context.Context,
+	req *connect.Request[couponv1.PeekAvailableCouponsRequest],
+) (*connect.Response[couponv1.PeekAvailableCouponsResponse], error) {
+	limit := int(req.Msg.Limit)
+	switch {
+	case limit < 0:
+		return nil, newServiceError(ErrInvalidArgument, fmt.Errorf("limit must not be negative"))
+	case limit == 0:
+		limit = defaultPeekLimit
+	}
+
+	var codes []string
+	err := s.guardDB(ctx, func() error {
+		tx, err := s.postgres.BeginTxx(ctx, &sql.TxOptions{ReadOnly: true})
+		if err != nil {
+			return newServiceError(ErrDB, fmt.Errorf("failed to begin transaction: %w", err))
+		}
+		defer tx.Rollback()
+
+		campaign, err := s.campaignRepo.GetCampaign(ctx, tx, req.Msg.CampaignId)
+		if err != nil {
+			if errors.Is(err, repository.ErrCampaignNotFound) {
+				return newServiceError(ErrCampaignNotFound, err)
+			}
+			return newServiceError(ErrDB, fmt.Errorf("failed to get campaign: %w", err))
+		}
+
+		stored, err := s.couponRepo.PeekAvailable(ctx, tx, campaign.ID, campaign.ReservationOrder, limit, s.clock.Now())
+		if err != nil {
+			return newServiceError(ErrDB, err)
+		}
+		// In hashed storage the stored codes are hashes; list the codes
+		// themselves, as issuance hands them out
+		codes, err = s.revealCodes(ctx, tx, campaign, stored)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res := connect.NewResponse(&couponv1.PeekAvailableCouponsResponse{
+		Codes: codes,
+	})
+
+	return res, nil
+}
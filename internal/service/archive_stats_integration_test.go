@@ -0,0 +1,66 @@
+//go:build integration
+
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+
+	couponv1 "github.com/kkkkikiki/coupon/gen/coupon/v1"
+	"github.com/kkkkikiki/coupon/internal/config"
+)
+
+func TestStatsIncludeArchivedCoupons(t *testing.T) {
+	s, clock := newTestServer(t, func(cfg *config.Config) {
+		cfg.App.ArchiveAfter = 3600
+	})
+	ctx := context.Background()
+	campaign := createTestCampaign(t, s, 3, func(req *couponv1.CreateCampaignRequest) {
+		req.Pools = []*couponv1.CouponPool{
+			{Name: "a", Count: 2},
+			{Name: "b", Count: 1},
+		}
+	})
+	for range 3 {
+		if _, err := issueTestCoupon(s, campaign.Id, ""); err != nil {
+			t.Fatalf("IssueCoupon: %v", err)
+		}
+	}
+	if _, err := s.RevokeCampaignCoupons(ctx, connect.NewRequest(&couponv1.RevokeCampaignCouponsRequest{CampaignId: campaign.Id})); err != nil {
+		t.Fatalf("RevokeCampaignCoupons: %v", err)
+	}
+
+	clock.Advance(2 * time.Hour)
+	NewCouponArchiver(s).archive(ctx)
+	var live int
+	if err := testDB.Get(&live, `SELECT COUNT(*) FROM coupons WHERE campaign_id = $1`, campaign.Id); err != nil {
+		t.Fatalf("count live coupons: %v", err)
+	}
+	if live != 0 {
+		t.Fatalf("%d coupons left after archiving, want all archived", live)
+	}
+
+	resp, err := s.GetCampaignStats(ctx, connect.NewRequest(&couponv1.GetCampaignStatsRequest{CampaignId: campaign.Id}))
+	if err != nil {
+		t.Fatalf("GetCampaignStats: %v", err)
+	}
+	stats := resp.Msg.Stats
+	if stats.TotalCount != 3 || stats.RevokedCount != 3 || stats.IssuedCount != 0 || stats.AvailableCount != 0 {
+		t.Errorf("stats = total %d, revoked %d, issued %d, available %d, want 3 revoked of 3",
+			stats.TotalCount, stats.RevokedCount, stats.IssuedCount, stats.AvailableCount)
+	}
+
+	want := map[string]int32{"a": 2, "b": 1}
+	if len(stats.Pools) != len(want) {
+		t.Fatalf("got %d pool stats, want %d", len(stats.Pools), len(want))
+	}
+	for _, pool := range stats.Pools {
+		if pool.TotalCount != want[pool.Pool] || pool.RevokedCount != want[pool.Pool] {
+			t.Errorf("pool %q = total %d, revoked %d, want %d revoked of %d",
+				pool.Pool, pool.TotalCount, pool.RevokedCount, want[pool.Pool], want[pool.Pool])
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"connectrpc.com/connect"
+
+	couponv1 "github.com/kkkkikiki/coupon/gen/coupon/v1"
+	"github.com/kkkkikiki/coupon/internal/model"
+	"github.com/kkkkikiki/coupon/internal/repository"
+)
+
+// BatchCreateCampaigns creates several campaigns in a single transaction.
+// Every campaign is validated like a CreateCampaign request; any violation,
+// or any failure while storing, creates none of them.
+func (s *CouponServer) BatchCreateCampaigns(
+	ctx context.Context,
+	req *connect.Request[couponv1.BatchCreateCampaignsRequest],
+) (*connect.Response[couponv1.BatchCreateCampaignsResponse], error) {
+	var v validator
+	v.check(len(req.Msg.Campaigns) > 0, "campaigns", "must not be empty")
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
+	campaigns := make([]*model.Campaign, len(req.Msg.Campaigns))
+	allocations := make([][]poolAllocation, len(req.Msg.Campaigns))
+	slugs := make(map[string]bool)
+	var totalCoupons int
+	for i, msg := range req.Msg.Campaigns {
+		var entry validator
+		entry.check(!msg.DryRun, "dry_run", "is not supported in a batch")
+		entry.check(msg.RequestId == "", "request_id", "is not supported in a batch")
+		entry.check(msg.Slug == "" || !slugs[msg.Slug], "slug", "is used by another campaign of the batch")
+		slugs[msg.Slug] = true
+		campaigns[i], allocations[i] = s.campaignFromRequest(&entry, msg)
+		v.merge(fmt.Sprintf("campaigns[%d].", i), &entry)
+		totalCoupons += allocatedCount(allocations[i])
+	}
+	// The request limits interceptor checks the requested counts too; this
+	// bounds the transaction for callers that bypass it
+	v.check(int64(totalCoupons) <= s.maxRequestCoupons, "campaigns", "must have at most %d coupons in total", s.maxRequestCoupons)
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
+	err := s.guardDB(ctx, func() error {
+		tx, err := s.postgres.BeginTxx(ctx, nil)
+		if err != nil {
+			return newServiceError(ErrDB, fmt.Errorf("failed to begin transaction: %w", err))
+		}
+		defer tx.Rollback()
+
+		for i, campaign := range campaigns {
+			if _, err := s.campaignRepo.CreateCampaign(ctx, tx, campaign, "", s.clock.Now()); err != nil {
+				if errors.Is(err, repository.ErrCampaignSlugExists) {
+					return newServiceError(ErrAlreadyExists, fmt.Errorf("campaigns[%d]: %w", i, err))
+				}
+				return newServiceError(ErrDB, fmt.Errorf("failed to create campaigns[%d]: %w", i, err))
+			}
+			if err := s.storeCoupons(ctx, tx, campaign, 0, allocations[i]); err != nil {
+				return newServiceError(errorCodeOf(err), fmt.Errorf("campaigns[%d]: %w", i, err))
+			}
+		}
+
+		if err := tx.Commit(); err != nil {
+			return newServiceError(ErrDB, fmt.Errorf("failed to commit transaction: %w", err))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.rescanActivations()
+
+	ids := make([]int64, len(campaigns))
+	for i, campaign := range campaigns {
+		ids[i] = campaign.ID
+	}
+	return connect.NewResponse(&couponv1.BatchCreateCampaignsResponse{
+		CampaignIds: ids,
+	}), nil
+}
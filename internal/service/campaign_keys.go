@@ -0,0 +1,77 @@
+package service
+
+import (
+	"container/list"
+	"crypto/aes"
+	"crypto/cipher"
+	"fmt"
+	"sync"
+)
+
+// campaignKeyCacheSize caps the number of campaigns whose ciphers are kept
+const campaignKeyCacheSize = 1024
+
+// campaignKeyCache keeps the AES cipher of recently used campaigns, so code
+// generation derives a campaign's key and expands its key schedule once
+// instead of once per coupon. It is bounded by evicting the least recently
+// used campaign and is safe for concurrent use; cached ciphers only encrypt
+// and can be shared.
+type campaignKeyCache struct {
+	size int
+
+	mu      sync.Mutex
+	order   *list.List // front is most recently used
+	entries map[int64]*list.Element
+}
+
+type campaignKeyEntry struct {
+	campaignID int64
+	block      cipher.Block
+}
+
+// newCampaignKeyCache creates a cache holding up to size campaigns
+func newCampaignKeyCache(size int) *campaignKeyCache {
+	return &campaignKeyCache{
+		size:    size,
+		order:   list.New(),
+		entries: make(map[int64]*list.Element),
+	}
+}
+
+// get returns the cached cipher of a campaign, creating it from the key
+// returned by derive on a miss
+func (c *campaignKeyCache) get(campaignID int64, derive func(int64) []byte) (cipher.Block, error) {
+	c.mu.Lock()
+	if element, ok := c.entries[campaignID]; ok {
+		c.order.MoveToFront(element)
+		c.mu.Unlock()
+		return element.Value.(*campaignKeyEntry).block, nil
+	}
+	c.mu.Unlock()
+
+	// Derive outside the lock; concurrent misses of one campaign derive the
+	// same key and the last one wins
+	block, err := aes.NewCipher(derive(campaignID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create AES cipher: %w", err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if element, ok := c.entries[campaignID]; ok {
+		c.order.MoveToFront(element)
+		return element.Value.(*campaignKeyEntry).block, nil
+	}
+	c.entries[campaignID] = c.order.PushFront(&campaignKeyEntry{campaignID: campaignID, block: block})
+	if c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*campaignKeyEntry).campaignID)
+	}
+	return block, nil
+}
+
+// campaignCipher returns the AES cipher generating a campaign's codes
+func (s *CouponServer) campaignCipher(campaignID int64) (cipher.Block, error) {
+	return s.campaignKeys.get(campaignID, s.generateCampaignKey)
+}
@@ -0,0 +1,50 @@
+//go:build integration
+
+package service
+
+import (
+	"context"
+	"testing"
+
+	"connectrpc.com/connect"
+
+	couponv1 "github.com/kkkkikiki/coupon/gen/coupon/v1"
+)
+
+func TestCreateCampaignRetryReturnsSameCampaign(t *testing.T) {
+	s, _ := newTestServer(t, nil)
+	withRequestID := func(req *couponv1.CreateCampaignRequest) {
+		req.RequestId = "create-retry"
+	}
+
+	first := createTestCampaign(t, s, 5, withRequestID)
+
+	// A retry after a lost response replays the campaign instead of creating
+	// another one with its own coupons
+	msg := &couponv1.CreateCampaignRequest{AvailableCoupons: 5, StartDate: first.StartDate, RequestId: "create-retry"}
+	retry, err := s.CreateCampaign(context.Background(), connect.NewRequest(msg))
+	if err != nil {
+		t.Fatalf("retried CreateCampaign: %v", err)
+	}
+	if !retry.Msg.Replayed {
+		t.Error("retried CreateCampaign isn't marked replayed")
+	}
+	if retry.Msg.Campaign.Id != first.Id {
+		t.Errorf("retry returned campaign %d, want %d", retry.Msg.Campaign.Id, first.Id)
+	}
+
+	stats, err := s.GetCampaignStats(context.Background(), connect.NewRequest(&couponv1.GetCampaignStatsRequest{CampaignId: first.Id}))
+	if err != nil {
+		t.Fatalf("GetCampaignStats: %v", err)
+	}
+	if stats.Msg.Stats.TotalCount != 5 {
+		t.Errorf("campaign has %d coupons after the retry, want 5", stats.Msg.Stats.TotalCount)
+	}
+
+	other := createTestCampaign(t, s, 5, func(req *couponv1.CreateCampaignRequest) {
+		req.RequestId = "create-other"
+	})
+	if other.Id == first.Id {
+		t.Errorf("a different request ID returned campaign %d again", first.Id)
+	}
+}
@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/kkkkikiki/coupon/internal/repository"
+)
+
+// slugPattern matches campaign slugs: lowercase letters and digits in
+// groups separated by single hyphens
+var slugPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// maxSlugLength caps the length of a campaign slug
+const maxSlugLength = 64
+
+// validateSlug checks the slug of a new campaign; empty means no slug
+func validateSlug(slug string) error {
+	if slug == "" {
+		return nil
+	}
+	if len(slug) > maxSlugLength || !slugPattern.MatchString(slug) {
+		return fmt.Errorf("must be 1 to %d lowercase letters, digits and single hyphens", maxSlugLength)
+	}
+	return nil
+}
+
+// resolveCampaignID returns the ID of the campaign a request names by ID or,
+// when slug is set, by slug. Unknown slugs fail with NotFound.
+func (s *CouponServer) resolveCampaignID(ctx context.Context, campaignID int64, slug string) (int64, error) {
+	if slug == "" {
+		return campaignID, nil
+	}
+	// Slugs never change, so a resolved slug can be cached for good
+	if id, ok := s.slugIDs.Load(slug); ok {
+		return id.(int64), nil
+	}
+
+	err := s.guardDB(ctx, func() error {
+		var err error
+		campaignID, err = s.campaignRepo.GetCampaignIDBySlug(ctx, s.postgres, slug)
+		if err != nil {
+			if errors.Is(err, repository.ErrCampaignNotFound) {
+				return newServiceError(ErrCampaignNotFound, fmt.Errorf("campaign %q not found", slug))
+			}
+			return newServiceError(ErrDB, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	s.slugIDs.Store(slug, campaignID)
+	return campaignID, nil
+}
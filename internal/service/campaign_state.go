@@ -0,0 +1,29 @@
+package service
+
+import (
+	"time"
+
+	couponv1 "github.com/kkkkikiki/coupon/gen/coupon/v1"
+	"github.com/kkkkikiki/coupon/internal/model"
+)
+
+// campaignState derives whether a campaign issues coupons at now, given its
+// available coupon count, using the checks IssueCoupon rejects requests with
+func campaignState(campaign *model.Campaign, remaining int32, now time.Time) couponv1.CampaignState {
+	if campaign.DeletedAt != nil || remaining <= 0 || budgetExhausted(campaign) {
+		return couponv1.CampaignState_CAMPAIGN_STATE_ENDED
+	}
+	if now.Before(campaign.StartDate) {
+		return couponv1.CampaignState_CAMPAIGN_STATE_UPCOMING
+	}
+	if open, _, err := issuanceWindowOpen(campaign, now); err == nil && !open {
+		return couponv1.CampaignState_CAMPAIGN_STATE_UPCOMING
+	}
+	return couponv1.CampaignState_CAMPAIGN_STATE_ACTIVE
+}
+
+// budgetExhausted reports whether a campaign's budget can't cover another
+// coupon
+func budgetExhausted(campaign *model.Campaign) bool {
+	return campaign.Budget > 0 && campaign.IssuedValue+campaign.DiscountValue > campaign.Budget
+}
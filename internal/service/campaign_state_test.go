@@ -0,0 +1,54 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	couponv1 "github.com/kkkkikiki/coupon/gen/coupon/v1"
+	"github.com/kkkkikiki/coupon/internal/model"
+)
+
+func TestCampaignState(t *testing.T) {
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC) // A Monday
+	deletedAt := start.Add(time.Hour)
+	nineToFive := func(c *model.Campaign) {
+		opens, closes := int32(9*60), int32(17*60)
+		c.WindowStart, c.WindowEnd, c.WindowTimeZone = &opens, &closes, "UTC"
+	}
+
+	const (
+		upcoming = couponv1.CampaignState_CAMPAIGN_STATE_UPCOMING
+		active   = couponv1.CampaignState_CAMPAIGN_STATE_ACTIVE
+		ended    = couponv1.CampaignState_CAMPAIGN_STATE_ENDED
+	)
+	tests := []struct {
+		name      string
+		configure func(*model.Campaign)
+		remaining int32
+		now       time.Time
+		want      couponv1.CampaignState
+	}{
+		{"just before the start", nil, 10, start.Add(-time.Nanosecond), upcoming},
+		{"at the start", nil, 10, start, active},
+		{"last coupon left", nil, 1, start, active},
+		{"sold out", nil, 0, start, ended},
+		{"sold out before the start", nil, 0, start.Add(-time.Hour), ended},
+		{"deleted", func(c *model.Campaign) { c.DeletedAt = &deletedAt }, 10, start.Add(2 * time.Hour), ended},
+		{"budget covers one more", func(c *model.Campaign) { c.Budget, c.IssuedValue, c.DiscountValue = 1000, 900, 100 }, 10, start, active},
+		{"budget exhausted", func(c *model.Campaign) { c.Budget, c.IssuedValue, c.DiscountValue = 1000, 901, 100 }, 10, start, ended},
+		{"window just opened", nineToFive, 10, start, active},
+		{"window just closed", nineToFive, 10, start.Add(8 * time.Hour), upcoming},
+		{"window about to close", nineToFive, 10, start.Add(8*time.Hour - time.Nanosecond), active},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			campaign := &model.Campaign{ID: 1, StartDate: start}
+			if tt.configure != nil {
+				tt.configure(campaign)
+			}
+			if got := campaignState(campaign, tt.remaining, tt.now); got != tt.want {
+				t.Errorf("campaignState() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
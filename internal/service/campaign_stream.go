@@ -0,0 +1,178 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+
+	"connectrpc.com/connect"
+	"github.com/jmoiron/sqlx"
+
+	couponv1 "github.com/kkkkikiki/coupon/gen/coupon/v1"
+	"github.com/kkkkikiki/coupon/internal/model"
+	"github.com/kkkkikiki/coupon/internal/repository"
+)
+
+// createProgressStep is the number of coupons generated or inserted between
+// CreateCampaignStream progress messages
+const createProgressStep = 10000
+
+// responseControllerKey is the context key of the request's
+// http.ResponseController
+type responseControllerKey struct{}
+
+// WithResponseController makes each request's http.ResponseController
+// available to streaming RPC handlers, which connect gives no access to the
+// ResponseWriter
+func WithResponseController(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), responseControllerKey{}, http.NewResponseController(w))
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// codeChunk is a run of generated codes with consecutive coupon indexes
+type codeChunk struct {
+	group model.CouponGroup
+	start uint64
+	codes []string
+}
+
+// CreateCampaignStream creates a campaign like CreateCampaign, sending a
+// progress message every createProgressStep coupons generated and then
+// inserted, and the created campaign last. Each message gives the stream a
+// full write timeout, so large campaigns aren't cut off while they make
+// progress. dry_run is not supported; use CreateCampaign to preview codes.
+func (s *CouponServer) CreateCampaignStream(
+	ctx context.Context,
+	req *connect.Request[couponv1.CreateCampaignRequest],
+	stream *connect.ServerStream[couponv1.CreateCampaignProgress],
+) error {
+	var v validator
+	campaign, allocations := s.campaignFromRequest(&v, req.Msg)
+	v.check(!req.Msg.DryRun, "dry_run", "is not supported when streaming")
+	if err := v.err(); err != nil {
+		return err
+	}
+	total := campaign.AvailableCoupons
+
+	send := func(progress *couponv1.CreateCampaignProgress) error {
+		if rc, ok := ctx.Value(responseControllerKey{}).(*http.ResponseController); ok {
+			s.extendWriteDeadline(rc)
+		}
+		progress.Total = total
+		if err := stream.Send(progress); err != nil {
+			// The client is gone; not a database failure
+			return newServiceError(ErrCanceled, fmt.Errorf("failed to send progress: %w", err))
+		}
+		return nil
+	}
+
+	var replayed bool
+	err := s.guardDB(ctx, func() error {
+		tx, err := s.postgres.BeginTxx(ctx, nil)
+		if err != nil {
+			return newServiceError(ErrDB, fmt.Errorf("failed to begin transaction: %w", err))
+		}
+		defer tx.Rollback()
+
+		created, err := s.campaignRepo.CreateCampaign(ctx, tx, campaign, req.Msg.RequestId, s.clock.Now())
+		if err != nil {
+			if errors.Is(err, repository.ErrCampaignSlugExists) {
+				return newServiceError(ErrAlreadyExists, err)
+			}
+			return newServiceError(ErrDB, fmt.Errorf("failed to create campaign: %w", err))
+		}
+
+		// A retry of an already completed request returns the same campaign
+		// without progress messages
+		if !created {
+			existing, err := s.campaignRepo.GetCampaignByRequestID(ctx, tx, req.Msg.RequestId)
+			if err != nil {
+				return newServiceError(ErrDB, err)
+			}
+			campaign, replayed = existing, true
+			return nil
+		}
+
+		chunks, err := s.generateChunks(campaign, allocations, func(generated int32) error {
+			return send(&couponv1.CreateCampaignProgress{Generated: generated})
+		})
+		if err != nil {
+			return err
+		}
+		if err := s.insertChunks(ctx, tx, campaign, chunks, func(inserted int32) error {
+			return send(&couponv1.CreateCampaignProgress{Generated: total, Inserted: inserted})
+		}); err != nil {
+			return err
+		}
+
+		if err := tx.Commit(); err != nil {
+			return newServiceError(ErrDB, fmt.Errorf("failed to commit transaction: %w", err))
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if !replayed {
+		s.rescanActivations()
+	}
+
+	progress := &couponv1.CreateCampaignProgress{
+		Campaign: toProtoCampaign(campaign, []string{}),
+		Replayed: replayed,
+	}
+	if !replayed {
+		progress.Generated, progress.Inserted = total, total
+	}
+	return send(progress)
+}
+
+// generateChunks generates the codes of every allocation in chunks of
+// createProgressStep, calling report with the running count after each
+func (s *CouponServer) generateChunks(campaign *model.Campaign, allocations []poolAllocation, report func(int32) error) ([]codeChunk, error) {
+	var chunks []codeChunk
+	var start uint64
+	var generated int32
+	for _, allocation := range allocations {
+		for done := 0; done < allocation.count; {
+			n := min(createProgressStep, allocation.count-done)
+			codes, err := s.generateCouponCodes(campaign, start, n)
+			if err != nil {
+				log.Printf("Coupon generation failed: %v", err)
+				return nil, generationFailure(err)
+			}
+			chunks = append(chunks, codeChunk{group: allocation.CouponGroup, start: start, codes: s.storedCodes(codes)})
+
+			start += uint64(n)
+			done += n
+			generated += int32(n)
+			if err := report(generated); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return chunks, nil
+}
+
+// insertChunks stores generated chunks, calling report with the running count
+// after each
+func (s *CouponServer) insertChunks(ctx context.Context, tx *sqlx.Tx, campaign *model.Campaign, chunks []codeChunk, report func(int32) error) error {
+	var inserted int32
+	for _, chunk := range chunks {
+		if err := s.couponRepo.CreatePregeneratedCoupons(ctx, tx, campaign.ID, chunk.codes, int64(chunk.start),
+			chunk.group, campaign.CouponMetadata, campaign.ShuffleSeed, s.clock.Now()); err != nil {
+			return storeFailure(campaign, err)
+		}
+
+		inserted += int32(len(chunk.codes))
+		if err := report(inserted); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,15 @@
+package service
+
+import "time"
+
+// Clock is the service's source of the current time. Everything the service
+// decides by time (start dates, expiries, TTLs, rate limits) reads it, so
+// tests can pin it with NewCouponServerWithClock instead of sleeping.
+type Clock interface {
+	Now() time.Time
+}
+
+// realClock reads the system time
+type realClock struct{}
+
+func (realClock) Now() time.Time { return time.Now() }
@@ -0,0 +1,127 @@
+//go:build integration
+
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	couponv1 "github.com/kkkkikiki/coupon/gen/coupon/v1"
+	"github.com/kkkkikiki/coupon/internal/config"
+)
+
+func TestWritesUseServerClock(t *testing.T) {
+	s, clock := newTestServer(t, nil)
+	ctx := context.Background()
+	campaign := createTestCampaign(t, s, 3, nil)
+
+	// A clock far from the wall clock shows which time each write used
+	clock.Advance(72 * time.Hour)
+	issuedAt := clock.Now()
+	resp, err := issueTestCoupon(s, campaign.Id, "")
+	if err != nil {
+		t.Fatalf("IssueCoupon: %v", err)
+	}
+	var stored time.Time
+	if err := testDB.Get(&stored, `SELECT issued_at FROM coupons WHERE campaign_id = $1 AND code = $2`, campaign.Id, resp.Coupon.Code); err != nil {
+		t.Fatalf("failed to read issued_at: %v", err)
+	}
+	if !stored.Equal(issuedAt) {
+		t.Errorf("issued_at = %v, want the server clock %v", stored, issuedAt)
+	}
+
+	recentlyIssued := func() int32 {
+		t.Helper()
+		stats, err := s.GetCampaignStats(ctx, connect.NewRequest(&couponv1.GetCampaignStatsRequest{CampaignId: campaign.Id}))
+		if err != nil {
+			t.Fatalf("GetCampaignStats: %v", err)
+		}
+		return stats.Msg.Stats.RecentlyIssuedCount
+	}
+	if got := recentlyIssued(); got != 1 {
+		t.Errorf("recently issued = %d, want 1", got)
+	}
+	// The default window is an hour of server time
+	clock.Advance(2 * time.Hour)
+	if got := recentlyIssued(); got != 0 {
+		t.Errorf("recently issued two hours later = %d, want 0", got)
+	}
+
+	deleted, err := s.DeleteCampaign(ctx, connect.NewRequest(&couponv1.DeleteCampaignRequest{CampaignId: campaign.Id, Force: true}))
+	if err != nil {
+		t.Fatalf("DeleteCampaign: %v", err)
+	}
+	if got := deleted.Msg.DeletedAt.AsTime(); !got.Equal(clock.Now()) {
+		t.Errorf("deleted_at = %v, want the server clock %v", got, clock.Now())
+	}
+}
+
+func TestCreationUsesServerClock(t *testing.T) {
+	s, clock := newTestServer(t, nil)
+	clock.Advance(72 * time.Hour)
+	createdAt := clock.Now()
+	campaign := createTestCampaign(t, s, 2, nil)
+
+	var stamps []time.Time
+	if err := testDB.Select(&stamps, `
+		SELECT created_at FROM campaigns WHERE id = $1
+		UNION ALL
+		SELECT created_at FROM coupons WHERE campaign_id = $1
+	`, campaign.Id); err != nil {
+		t.Fatalf("failed to read created_at: %v", err)
+	}
+	if len(stamps) != 3 {
+		t.Fatalf("read %d created_at values, want the campaign's and 2 coupons'", len(stamps))
+	}
+	for _, stamp := range stamps {
+		if !stamp.Equal(createdAt) {
+			t.Errorf("created_at = %v, want the server clock %v", stamp, createdAt)
+		}
+	}
+}
+
+func TestCooldownUsesServerClock(t *testing.T) {
+	s, clock := newTestServer(t, func(cfg *config.Config) {
+		cfg.App.UserCooldown = 60
+	})
+	campaign := createTestCampaign(t, s, 3, nil)
+
+	if _, err := issueTestCoupon(s, campaign.Id, "user-1"); err != nil {
+		t.Fatalf("first IssueCoupon: %v", err)
+	}
+	clock.Advance(59 * time.Second)
+	_, err := issueTestCoupon(s, campaign.Id, "user-1")
+	wantCode(t, err, ErrRateLimited)
+
+	// The cooldown ends on the server clock, not the wall clock
+	clock.Advance(time.Second)
+	if _, err := issueTestCoupon(s, campaign.Id, "user-1"); err != nil {
+		t.Fatalf("IssueCoupon after the cooldown: %v", err)
+	}
+}
+
+func TestPoolExpiryUsesServerClock(t *testing.T) {
+	s, clock := newTestServer(t, nil)
+	campaign := createTestCampaign(t, s, 3, func(req *couponv1.CreateCampaignRequest) {
+		req.IssuanceOrder = couponv1.IssuanceOrder_ISSUANCE_ORDER_EXPIRES_AT
+		req.Pools = []*couponv1.CouponPool{
+			{Name: "expiring", Count: 2, ExpiresAt: timestamppb.New(clock.Now().Add(time.Hour))},
+			{Name: "live", Count: 1},
+		}
+	})
+
+	clock.Advance(time.Hour)
+	resp, err := issueTestCoupon(s, campaign.Id, "")
+	if err != nil {
+		t.Fatalf("IssueCoupon: %v", err)
+	}
+	if resp.Coupon.Pool != "live" {
+		t.Fatalf("issued a coupon of pool %q after its expiry, want live", resp.Coupon.Pool)
+	}
+	_, err = issueTestCoupon(s, campaign.Id, "")
+	wantCode(t, err, ErrSoldOut)
+}
@@ -0,0 +1,220 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"unicode/utf8"
+
+	couponv1 "github.com/kkkkikiki/coupon/gen/coupon/v1"
+	"github.com/kkkkikiki/coupon/internal/codetag"
+	"github.com/kkkkikiki/coupon/internal/model"
+	"github.com/kkkkikiki/coupon/internal/repository"
+)
+
+const (
+	// defaultCodeAlphabet is the "읽기 편한" alphabet: 10 digits + 28 Hangul syllables
+	defaultCodeAlphabet = "0123456789가나다라마바사아자차카타파하거너더러머버서어저처커터퍼허"
+	// defaultCodeLength is the default total code length
+	defaultCodeLength = 10
+	// maxCodeLength matches the width of the coupons.code column
+	maxCodeLength = 32
+	// maxCollisionProbability bounds the chance that two generated codes of a
+	// campaign collide. Codes are truncated encryptions of the coupon index,
+	// so by the birthday bound a format holds far fewer coupons than codes.
+	maxCollisionProbability = 0.01
+)
+
+// defaultCodeFormat returns the format used when a campaign doesn't specify one
+func defaultCodeFormat() *couponv1.CodeFormat {
+	return &couponv1.CodeFormat{
+		Alphabet: defaultCodeAlphabet,
+		Length:   defaultCodeLength,
+	}
+}
+
+// resolveCodeFormat fills unset alphabet/length fields of the requested format
+// from base and validates the result against the number of coupons to generate.
+// When format is nil, base is used as a whole.
+func resolveCodeFormat(format, base *couponv1.CodeFormat, couponCount int32) (alphabet string, length int32, prefix string, routingTag bool, err error) {
+	alphabet = base.Alphabet
+	length = base.Length
+	prefix = base.Prefix
+	routingTag = base.RoutingTag
+	if format != nil {
+		if format.Alphabet != "" {
+			alphabet = format.Alphabet
+		}
+		if format.Length != 0 {
+			length = format.Length
+		}
+		prefix = format.Prefix
+		routingTag = format.RoutingTag
+	}
+
+	runes := []rune(alphabet)
+	seen := make(map[rune]bool, len(runes))
+	for _, r := range runes {
+		if seen[r] {
+			return "", 0, "", false, fmt.Errorf("code alphabet contains duplicate character %q", r)
+		}
+		seen[r] = true
+	}
+	if len(runes) < 2 {
+		return "", 0, "", false, fmt.Errorf("code alphabet must contain at least 2 distinct characters")
+	}
+
+	if length < 1 || length > maxCodeLength {
+		return "", 0, "", false, fmt.Errorf("code length must be between 1 and %d", maxCodeLength)
+	}
+	bodyLen := codeBodyLength(length, prefix, routingTag)
+	if bodyLen < 1 {
+		if routingTag {
+			return "", 0, "", false, fmt.Errorf("code prefix and %d-character routing tag must be shorter than the code length", codetag.TagLength)
+		}
+		return "", 0, "", false, fmt.Errorf("code prefix must be shorter than the code length")
+	}
+
+	// The format must hold every coupon of the campaign without codes
+	// likely colliding
+	if limit := maxCodesFor(alphabet, length, prefix, routingTag); int64(couponCount) > limit {
+		return "", 0, "", false, fmt.Errorf("code alphabet of %d characters with %d free positions holds at most %d coupons without likely code collisions, %d requested",
+			len(runes), bodyLen, limit, couponCount)
+	}
+
+	return alphabet, length, prefix, routingTag, nil
+}
+
+// codeBodyLength returns the number of generated characters in a code of a
+// format: the length minus the prefix and the routing tag, if any
+func codeBodyLength(length int32, prefix string, routingTag bool) int {
+	bodyLen := int(length) - utf8.RuneCountInString(prefix)
+	if routingTag {
+		bodyLen -= codetag.TagLength
+	}
+	return bodyLen
+}
+
+// maxCodesFor returns how many coupons a campaign can generate in a format
+func maxCodesFor(alphabet string, length int32, prefix string, routingTag bool) int64 {
+	if codeBodyLength(length, prefix, routingTag) < 1 {
+		return 0
+	}
+	return maxCodesInSpace(codeSpaceBits(alphabet, length, prefix, routingTag))
+}
+
+// maxCodesInSpace returns the largest number of codes n drawn from a space of
+// 2^spaceBits codes whose birthday bound n(n-1)/2 / space stays within
+// maxCollisionProbability, capped by the per-campaign coupon index space
+func maxCodesInSpace(spaceBits float64) int64 {
+	n := math.Floor((1 + math.Sqrt(1+8*math.Exp2(spaceBits)*maxCollisionProbability)) / 2)
+	if n >= maxCouponIndex {
+		return maxCouponIndex
+	}
+	return int64(n)
+}
+
+// codeSpaceBits returns log2 of the number of codes a format can produce. The
+// default format always starts with a digit and a Hangul syllable, which
+// narrows its space below alphabet^length, and the routing tag is fixed per
+// campaign, so it adds nothing.
+func codeSpaceBits(alphabet string, length int32, prefix string, routingTag bool) float64 {
+	if alphabet == defaultCodeAlphabet && length == defaultCodeLength && prefix == "" && !routingTag {
+		return math.Log2(10) + math.Log2(28) + float64(length-2)*math.Log2(38)
+	}
+	bodyLen := codeBodyLength(length, prefix, routingTag)
+	return float64(bodyLen) * math.Log2(float64(utf8.RuneCountInString(alphabet)))
+}
+
+// guessEntropyBits returns how many bits of work it takes to guess a valid
+// code of a format by trying random codes: log2 of the number of codes the
+// format can produce minus log2 of the couponCount codes issued from it.
+func guessEntropyBits(alphabet string, length int32, prefix string, routingTag bool, couponCount int32) float64 {
+	return codeSpaceBits(alphabet, length, prefix, routingTag) - math.Log2(float64(max(couponCount, 1)))
+}
+
+// checkCodeEntropy rejects code formats whose codes are too easy to guess
+// for the number of coupons generated in them
+func (s *CouponServer) checkCodeEntropy(alphabet string, length int32, prefix string, routingTag bool, couponCount int32) error {
+	if bits := guessEntropyBits(alphabet, length, prefix, routingTag, couponCount); bits < s.minCodeEntropy {
+		return fmt.Errorf("gives only %.1f bits of guessing entropy for %d coupons, at least %g required; use a longer code or a larger alphabet",
+			bits, couponCount, s.minCodeEntropy)
+	}
+	return nil
+}
+
+// isDefaultCodeFormat reports whether the campaign uses the default format
+func isDefaultCodeFormat(campaign *model.Campaign) bool {
+	return campaign.CodeAlphabet == defaultCodeAlphabet &&
+		campaign.CodeLength == defaultCodeLength &&
+		campaign.CodePrefix == "" &&
+		!campaign.CodeRoutingTag
+}
+
+// describeCodeFormat names the campaign's code format in error messages
+func describeCodeFormat(campaign *model.Campaign) string {
+	desc := fmt.Sprintf("alphabet of %d characters, length %d", utf8.RuneCountInString(campaign.CodeAlphabet), campaign.CodeLength)
+	if campaign.CodePrefix != "" {
+		desc += fmt.Sprintf(", prefix %q", campaign.CodePrefix)
+	}
+	if campaign.CodeRoutingTag {
+		desc += ", routing tag"
+	}
+	return desc
+}
+
+// codeCollisionError reports generated codes that collide, with each other or
+// with stored codes, which only happens when the format's code space is too
+// small for the campaign
+func codeCollisionError(campaign *model.Campaign) error {
+	return newServiceError(ErrInvalidArgument, fmt.Errorf(
+		"generated coupon codes collide in code format (%s); use a longer code or a larger alphabet",
+		describeCodeFormat(campaign)))
+}
+
+// generationFailure converts a generateCouponCodes error to a service error,
+// passing through errors that already carry a code
+func generationFailure(err error) error {
+	var serviceErr *ServiceError
+	if errors.As(err, &serviceErr) {
+		return err
+	}
+	return newServiceError(ErrInternal, fmt.Errorf("failed to generate coupon code: %w", err))
+}
+
+// storeFailure converts a CreatePregeneratedCoupons error to a service error
+func storeFailure(campaign *model.Campaign, err error) error {
+	if errors.Is(err, repository.ErrCouponCodeExists) {
+		return codeCollisionError(campaign)
+	}
+	return newServiceError(ErrDB, fmt.Errorf("failed to store coupons in DB: %w", err))
+}
+
+// encodeCustomCode renders an encrypted block as prefix + body in the
+// campaign's alphabet
+func encodeCustomCode(cipher [16]byte, campaign *model.Campaign) string {
+	pool := []rune(campaign.CodeAlphabet)
+	base := big.NewInt(int64(len(pool)))
+	bodyLen := codeBodyLength(campaign.CodeLength, campaign.CodePrefix, campaign.CodeRoutingTag)
+
+	v := new(big.Int).SetBytes(cipher[:])
+	mod := new(big.Int)
+	body := make([]rune, bodyLen)
+	for i := bodyLen - 1; i >= 0; i-- {
+		v.DivMod(v, base, mod)
+		body[i] = pool[mod.Int64()]
+	}
+
+	return campaign.CodePrefix + string(body)
+}
+
+// toProtoCodeFormat converts the campaign's format to its protobuf form
+func toProtoCodeFormat(campaign *model.Campaign) *couponv1.CodeFormat {
+	return &couponv1.CodeFormat{
+		Alphabet:   campaign.CodeAlphabet,
+		Length:     campaign.CodeLength,
+		Prefix:     campaign.CodePrefix,
+		RoutingTag: campaign.CodeRoutingTag,
+	}
+}
@@ -0,0 +1,91 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	couponv1 "github.com/kkkkikiki/coupon/gen/coupon/v1"
+	"github.com/kkkkikiki/coupon/internal/model"
+	"github.com/kkkkikiki/coupon/internal/repository"
+)
+
+func TestGenerateCouponCodesRejectsCollisions(t *testing.T) {
+	s := NewCouponServerWithClock(nil, testConfig(t, nil), newTestClock(time.Now()))
+
+	// Only 2^3 codes fit the format, so 20 must collide
+	campaign := &model.Campaign{ID: 1, CodeAlphabet: "ab", CodeLength: 4, CodePrefix: "X"}
+	_, err := s.generateCouponCodes(campaign, 0, 20)
+	wantCode(t, err, ErrInvalidArgument)
+	if !strings.Contains(err.Error(), `alphabet of 2 characters, length 4, prefix "X"`) {
+		t.Errorf("error %q doesn't name the code format", err)
+	}
+
+	// The wider default format doesn't collide
+	campaign = &model.Campaign{ID: 1, CodeAlphabet: defaultCodeAlphabet, CodeLength: defaultCodeLength}
+	codes, err := s.generateCouponCodes(campaign, 0, 1000)
+	if err != nil {
+		t.Fatalf("generateCouponCodes: %v", err)
+	}
+	if len(codes) != 1000 {
+		t.Errorf("got %d codes, want 1000", len(codes))
+	}
+}
+
+func TestGenerationFailure(t *testing.T) {
+	collision := codeCollisionError(&model.Campaign{CodeAlphabet: "ab", CodeLength: 2})
+	wantCode(t, generationFailure(collision), ErrInvalidArgument)
+	wantCode(t, generationFailure(&GenerationError{CampaignID: 1, Err: errors.New("cipher unavailable")}), ErrInternal)
+}
+
+func TestStoreFailure(t *testing.T) {
+	campaign := &model.Campaign{CodeAlphabet: "ab", CodeLength: 2}
+	wantCode(t, storeFailure(campaign, fmt.Errorf("failed to insert coupon batch: %w", repository.ErrCouponCodeExists)), ErrInvalidArgument)
+	wantCode(t, storeFailure(campaign, errors.New("failed to insert coupon batch: connection reset")), ErrDB)
+}
+
+func TestMaxCodesFor(t *testing.T) {
+	const alnum = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	tests := []struct {
+		name       string
+		alphabet   string
+		length     int32
+		prefix     string
+		routingTag bool
+		want       int64
+	}{
+		{"digits", "0123456789", 6, "", false, 141},
+		{"prefix shortens the body", "0123456789", 7, "AB", false, 45},
+		{"single position", "ab", 1, "", false, 1},
+		{"no body", "0123456789", 2, "AB", false, 0},
+		{"default format", defaultCodeAlphabet, defaultCodeLength, "", false, 4934332},
+		{"routing tag shortens the body", alnum, 16, "", true, 237534},
+		{"alphanumeric", alnum, 12, "", false, 307843510},
+		{"capped by the coupon index space", alnum, 16, "", false, maxCouponIndex},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := maxCodesFor(tt.alphabet, tt.length, tt.prefix, tt.routingTag)
+			if got != tt.want {
+				t.Errorf("maxCodesFor() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveCodeFormatCapacity(t *testing.T) {
+	format := &couponv1.CodeFormat{Alphabet: "0123456789", Length: 6}
+	if _, _, _, _, err := resolveCodeFormat(format, defaultCodeFormat(), 141); err != nil {
+		t.Errorf("141 coupons: %v", err)
+	}
+	if _, _, _, _, err := resolveCodeFormat(format, defaultCodeFormat(), 142); err == nil {
+		t.Error("142 coupons: got no error")
+	}
+
+	// The default format's space is narrower than its alphabet and length suggest
+	if _, _, _, _, err := resolveCodeFormat(nil, defaultCodeFormat(), 4934333); err == nil {
+		t.Error("default format over capacity: got no error")
+	}
+}
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+
+	"github.com/kkkkikiki/coupon/internal/config"
+	"github.com/kkkkikiki/coupon/internal/model"
+	"github.com/kkkkikiki/coupon/internal/repository"
+)
+
+// storedCodeLength is the length of a hashed code, matching the width of the
+// coupons.code column
+const storedCodeLength = 32
+
+// codeSecret returns the code secret in use, nil unless codes are stored
+// hashed
+func codeSecret(cfg config.AppConfig) []byte {
+	if cfg.CodeStorage != "hashed" {
+		return nil
+	}
+	return []byte(cfg.CodeSecret)
+}
+
+// hashedStorage reports whether coupon codes are stored as hashes
+// (APP_CODE_STORAGE=hashed)
+func (s *CouponServer) hashedStorage() bool {
+	return len(s.codeSecret) > 0
+}
+
+// storedCode returns the form a coupon code is stored and looked up in: the
+// code itself, or in hashed storage the first 24 bytes of its HMAC-SHA256
+// encoded as 32 base64url characters
+func (s *CouponServer) storedCode(code string) string {
+	if !s.hashedStorage() {
+		return code
+	}
+	mac := hmac.New(sha256.New, s.codeSecret)
+	mac.Write([]byte("code:" + code))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))[:storedCodeLength]
+}
+
+// storedCodes applies storedCode to each code
+func (s *CouponServer) storedCodes(codes []string) []string {
+	if !s.hashedStorage() {
+		return codes
+	}
+	stored := make([]string, len(codes))
+	for i, code := range codes {
+		stored[i] = s.storedCode(code)
+	}
+	return stored
+}
+
+// revealCodes returns the codes of a campaign's coupons, given in stored
+// form, for handing them out on issuance. In hashed storage the database
+// only knows each coupon's index, so the code is generated again from it.
+func (s *CouponServer) revealCodes(ctx context.Context, db repository.DBExecutor, campaign *model.Campaign, stored []string) ([]string, error) {
+	if !s.hashedStorage() || len(stored) == 0 {
+		return stored, nil
+	}
+
+	indexes, err := s.couponRepo.GetCodeIndexes(ctx, db, campaign.ID, stored)
+	if err != nil {
+		return nil, newServiceError(ErrDB, err)
+	}
+	codes := make([]string, len(stored))
+	for i, storedCode := range stored {
+		index, ok := indexes[storedCode]
+		if !ok {
+			return nil, newServiceError(ErrInternal, fmt.Errorf("coupon %s has no code index", storedCode))
+		}
+		code, err := s.generateSecureCoupon(campaign, uint64(index))
+		if err != nil {
+			return nil, newServiceError(ErrInternal, &GenerationError{CampaignID: campaign.ID, Index: uint64(index), Err: err})
+		}
+		codes[i] = code
+	}
+	return codes, nil
+}
+
+// hashedCampaignKey derives a campaign's code generation key from the code
+// secret, so codes can't be regenerated from the database alone
+func (s *CouponServer) hashedCampaignKey(campaignID int64) []byte {
+	mac := hmac.New(sha256.New, s.codeSecret)
+	mac.Write([]byte("campaign:" + strconv.FormatInt(campaignID, 10)))
+	return mac.Sum(nil)[:16]
+}
@@ -0,0 +1,93 @@
+//go:build integration
+
+package service
+
+import (
+	"context"
+	"testing"
+
+	"connectrpc.com/connect"
+
+	couponv1 "github.com/kkkkikiki/coupon/gen/coupon/v1"
+	"github.com/kkkkikiki/coupon/internal/config"
+)
+
+func TestHashedStorageRevealsIssuedCodes(t *testing.T) {
+	s, _ := newTestServer(t, func(cfg *config.Config) {
+		cfg.App.CodeStorage = "hashed"
+		cfg.App.CodeSecret = "test-secret"
+	})
+	campaign := createTestCampaign(t, s, 3, nil)
+
+	resp, err := issueTestCoupon(s, campaign.Id, "user-1")
+	if err != nil {
+		t.Fatalf("IssueCoupon: %v", err)
+	}
+	code := resp.Coupon.Code
+
+	// Only the hash is in the database
+	var stored []string
+	if err := testDB.Select(&stored, `SELECT code FROM coupons WHERE campaign_id = $1 ORDER BY code`, campaign.Id); err != nil {
+		t.Fatalf("select codes: %v", err)
+	}
+	found := false
+	for _, storedCode := range stored {
+		if storedCode == code {
+			t.Fatalf("issued code %q is stored in the clear", code)
+		}
+		found = found || storedCode == s.storedCode(code)
+	}
+	if !found {
+		t.Fatalf("stored codes %v don't include the hash of issued code %q", stored, code)
+	}
+
+	// Every stored hash is revealed as the code it was derived from
+	record, err := s.campaignRepo.GetCampaign(context.Background(), testDB, campaign.Id)
+	if err != nil {
+		t.Fatalf("GetCampaign: %v", err)
+	}
+	revealed, err := s.revealCodes(context.Background(), testDB, record, stored)
+	if err != nil {
+		t.Fatalf("revealCodes: %v", err)
+	}
+	for i, revealedCode := range revealed {
+		if got := s.storedCode(revealedCode); got != stored[i] {
+			t.Errorf("revealed code %q hashes to %q, want %q", revealedCode, got, stored[i])
+		}
+	}
+
+	// The issued code is looked up by its hash
+	got, err := s.GetCoupon(context.Background(), connect.NewRequest(&couponv1.GetCouponRequest{Code: code}))
+	if err != nil {
+		t.Fatalf("GetCoupon: %v", err)
+	}
+	if got.Msg.Coupon.Code != code || got.Msg.Coupon.CampaignId != campaign.Id {
+		t.Errorf("GetCoupon = %v, want code %q of campaign %d", got.Msg.Coupon, code, campaign.Id)
+	}
+}
+
+func TestHashedStoragePeeksCodes(t *testing.T) {
+	s, _ := newTestServer(t, func(cfg *config.Config) {
+		cfg.App.CodeStorage = "hashed"
+		cfg.App.CodeSecret = "test-secret"
+	})
+	ctx := context.Background()
+	campaign := createTestCampaign(t, s, 3, nil)
+
+	peeked, err := s.PeekAvailableCoupons(ctx, connect.NewRequest(&couponv1.PeekAvailableCouponsRequest{CampaignId: campaign.Id, Limit: 1}))
+	if err != nil {
+		t.Fatalf("PeekAvailableCoupons: %v", err)
+	}
+	if len(peeked.Msg.Codes) != 1 {
+		t.Fatalf("peeked %d codes, want 1", len(peeked.Msg.Codes))
+	}
+
+	// The peeked code is the one issued next, in the form handed out
+	issued, err := issueTestCoupon(s, campaign.Id, "")
+	if err != nil {
+		t.Fatalf("IssueCoupon: %v", err)
+	}
+	if peeked.Msg.Codes[0] != issued.Coupon.Code {
+		t.Errorf("peeked %q, then issued %q", peeked.Msg.Codes[0], issued.Coupon.Code)
+	}
+}
@@ -0,0 +1,80 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/kkkkikiki/coupon/internal/config"
+	"github.com/kkkkikiki/coupon/internal/model"
+)
+
+// hashedTestServer returns a server storing codes hashed with secret
+func hashedTestServer(t *testing.T, secret string) *CouponServer {
+	t.Helper()
+
+	return NewCouponServerWithClock(nil, testConfig(t, func(cfg *config.Config) {
+		cfg.App.CodeStorage = "hashed"
+		cfg.App.CodeSecret = secret
+	}), newTestClock(time.Now()))
+}
+
+func TestStoredCode(t *testing.T) {
+	raw := NewCouponServerWithClock(nil, testConfig(t, nil), newTestClock(time.Now()))
+	if got := raw.storedCode("ABC123"); got != "ABC123" {
+		t.Errorf("raw storedCode = %q, want the code itself", got)
+	}
+
+	// Pinned value: stored hashes must match across releases
+	hashed := hashedTestServer(t, "test-secret")
+	const want = "fngenpnTfKEXGmdCRMD85EMMgV4XkArt"
+	if got := hashed.storedCode("ABC123"); got != want {
+		t.Errorf("hashed storedCode = %q, want %q", got, want)
+	}
+	if got := len(hashed.storedCode("가나다라마바사아자차")); got != storedCodeLength {
+		t.Errorf("hashed code is %d characters, want %d", got, storedCodeLength)
+	}
+
+	if other := hashedTestServer(t, "other-secret").storedCode("ABC123"); other == want {
+		t.Error("storedCode doesn't depend on the code secret")
+	}
+	if hashed.storedCode("ABC124") == want {
+		t.Error("storedCode doesn't depend on the code")
+	}
+}
+
+func TestStoredCodes(t *testing.T) {
+	codes := []string{"A", "B"}
+
+	raw := NewCouponServerWithClock(nil, testConfig(t, nil), newTestClock(time.Now()))
+	if got := raw.storedCodes(codes); !slices.Equal(got, codes) {
+		t.Errorf("raw storedCodes = %v, want %v", got, codes)
+	}
+
+	hashed := hashedTestServer(t, "test-secret")
+	want := []string{hashed.storedCode("A"), hashed.storedCode("B")}
+	if got := hashed.storedCodes(codes); !slices.Equal(got, want) {
+		t.Errorf("hashed storedCodes = %v, want %v", got, want)
+	}
+}
+
+func TestRevealCodesWithoutHashing(t *testing.T) {
+	raw := NewCouponServerWithClock(nil, testConfig(t, nil), newTestClock(time.Now()))
+	codes := []string{"A", "B"}
+
+	// Raw codes are returned as stored, without a database round trip
+	got, err := raw.revealCodes(context.Background(), nil, &model.Campaign{ID: 1}, codes)
+	if err != nil {
+		t.Fatalf("revealCodes: %v", err)
+	}
+	if !slices.Equal(got, codes) {
+		t.Errorf("revealCodes = %v, want %v", got, codes)
+	}
+
+	// So are no codes at all in hashed storage
+	got, err = hashedTestServer(t, "test-secret").revealCodes(context.Background(), nil, &model.Campaign{ID: 1}, nil)
+	if err != nil || len(got) != 0 {
+		t.Errorf("revealCodes(nil) = %v, %v, want no codes", got, err)
+	}
+}
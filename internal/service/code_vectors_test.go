@@ -0,0 +1,77 @@
+package service
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kkkkikiki/coupon/internal/config"
+	"github.com/kkkkikiki/coupon/internal/model"
+)
+
+var updateGolden = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// codeVectorCount is the number of coupon indexes pinned per format
+const codeVectorCount = 16
+
+// TestCodeGenerationVectors pins the codes generated for a fixed campaign, so
+// any change to key derivation, the cipher or the encoding fails here. Run
+// with -update only when the change is intended; codes already handed out
+// stop matching their campaigns otherwise.
+func TestCodeGenerationVectors(t *testing.T) {
+	const campaignID = 42
+
+	formats := []struct {
+		name     string
+		campaign *model.Campaign
+	}{
+		{"default", &model.Campaign{ID: campaignID, CodeAlphabet: defaultCodeAlphabet, CodeLength: defaultCodeLength}},
+		{"ascii", &model.Campaign{ID: campaignID, CodeAlphabet: "ABCDEFGHJKLMNPQRSTUVWXYZ23456789", CodeLength: 12, CodePrefix: "GO-"}},
+	}
+	storages := []struct {
+		name   string
+		secret string
+	}{
+		{"raw", ""},
+		{"hashed", "golden-secret"},
+	}
+
+	var got strings.Builder
+	for _, storage := range storages {
+		s := NewCouponServerWithClock(nil, testConfig(t, func(cfg *config.Config) {
+			if storage.secret != "" {
+				cfg.App.CodeStorage = "hashed"
+				cfg.App.CodeSecret = storage.secret
+			}
+		}), newTestClock(time.Now()))
+
+		for _, format := range formats {
+			fmt.Fprintf(&got, "# %s storage, %s format\n", storage.name, format.name)
+			for index := range uint64(codeVectorCount) {
+				code, err := s.generateSecureCoupon(format.campaign, index)
+				if err != nil {
+					t.Fatalf("generateSecureCoupon(%s, %s, %d): %v", storage.name, format.name, index, err)
+				}
+				fmt.Fprintf(&got, "%d %s\n", index, code)
+			}
+		}
+	}
+
+	path := filepath.Join("testdata", "code_vectors.golden")
+	if *updateGolden {
+		if err := os.WriteFile(path, []byte(got.String()), 0o644); err != nil {
+			t.Fatalf("write %s: %v", path, err)
+		}
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read %s: %v", path, err)
+	}
+	if got.String() != string(want) {
+		t.Errorf("generated codes differ from %s:\n%s", path, got.String())
+	}
+}
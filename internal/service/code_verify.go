@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"connectrpc.com/connect"
+
+	couponv1 "github.com/kkkkikiki/coupon/gen/coupon/v1"
+	"github.com/kkkkikiki/coupon/internal/model"
+	"github.com/kkkkikiki/coupon/internal/repository"
+)
+
+const (
+	// verifyBatchSize is the number of coupons VerifyCampaignCodes loads at once
+	verifyBatchSize = 1000
+	// maxVerifyReported caps the codes and indexes listed in a verification
+	// report
+	maxVerifyReported = 100
+)
+
+// VerifyCampaignCodes checks that each coupon of a campaign regenerates from
+// its recorded index and that no index was used twice, which would mean the
+// generator handed out the same code twice. Coupons moved in by
+// TransferCoupons, and coupons kept by a RegenerateCoupons that changed the
+// code format, were generated differently and show up as mismatched.
+func (s *CouponServer) VerifyCampaignCodes(
+	ctx context.Context,
+	req *connect.Request[couponv1.VerifyCampaignCodesRequest],
+) (*connect.Response[couponv1.VerifyCampaignCodesResponse], error) {
+	var campaign *model.Campaign
+	res := &couponv1.VerifyCampaignCodesResponse{}
+	err := s.guardDB(ctx, func() error {
+		var err error
+		campaign, err = s.campaignRepo.GetCampaign(ctx, s.postgres, req.Msg.CampaignId)
+		if err != nil {
+			if errors.Is(err, repository.ErrCampaignNotFound) {
+				return newServiceError(ErrCampaignNotFound, err)
+			}
+			return newServiceError(ErrDB, fmt.Errorf("failed to get campaign: %w", err))
+		}
+
+		res.DuplicateIndexes, err = s.couponRepo.DuplicateCodeIndexes(ctx, s.postgres, campaign.ID, maxVerifyReported)
+		if err != nil {
+			return newServiceError(ErrDB, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Scan in batches so the campaign is never held in memory at once
+	var after string
+	for {
+		var batch []model.CodeIndex
+		err := s.guardDB(ctx, func() error {
+			var err error
+			batch, err = s.couponRepo.ListCodeIndexes(ctx, s.postgres, campaign.ID, after, verifyBatchSize)
+			if err != nil {
+				return newServiceError(ErrDB, err)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, coupon := range batch {
+			if err := s.verifyCode(campaign, coupon, res); err != nil {
+				return nil, newServiceError(ErrInternal, err)
+			}
+		}
+		if len(batch) < verifyBatchSize {
+			break
+		}
+		after = batch[len(batch)-1].Code
+	}
+
+	if res.MismatchedCount > 0 || len(res.DuplicateIndexes) > 0 {
+		log.Printf("Campaign %d code verification found %d mismatched codes and %d duplicate indexes",
+			campaign.ID, res.MismatchedCount, len(res.DuplicateIndexes))
+	}
+	return connect.NewResponse(res), nil
+}
+
+// verifyCode checks one coupon against the code generated from its index and
+// records the outcome in res
+func (s *CouponServer) verifyCode(campaign *model.Campaign, coupon model.CodeIndex, res *couponv1.VerifyCampaignCodesResponse) error {
+	res.CheckedCount++
+	if coupon.Index == nil {
+		res.UnindexedCount++
+		return nil
+	}
+
+	code, err := s.generateSecureCoupon(campaign, uint64(*coupon.Index))
+	if err != nil {
+		return &GenerationError{CampaignID: campaign.ID, Index: uint64(*coupon.Index), Err: err}
+	}
+	if s.storedCode(code) != coupon.Code {
+		res.MismatchedCount++
+		if len(res.MismatchedCodes) < maxVerifyReported {
+			res.MismatchedCodes = append(res.MismatchedCodes, coupon.Code)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+//go:build integration
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+
+	"connectrpc.com/connect"
+
+	couponv1 "github.com/kkkkikiki/coupon/gen/coupon/v1"
+	"github.com/kkkkikiki/coupon/internal/config"
+)
+
+func TestCreateIssueGetCampaign(t *testing.T) {
+	s, _ := newTestServer(t, func(cfg *config.Config) {
+		cfg.App.RemainingCacheTTL = 0
+	})
+	campaign := createTestCampaign(t, s, 3, nil)
+	if campaign.AvailableCoupons != 3 {
+		t.Fatalf("created campaign has %d coupons, want 3", campaign.AvailableCoupons)
+	}
+
+	var issued []string
+	for i := range 3 {
+		resp, err := issueTestCoupon(s, campaign.Id, fmt.Sprintf("user-%d", i))
+		if err != nil {
+			t.Fatalf("IssueCoupon %d: %v", i, err)
+		}
+		issued = append(issued, resp.Coupon.Code)
+	}
+
+	got, err := s.GetCampaign(context.Background(), connect.NewRequest(&couponv1.GetCampaignRequest{
+		Campaign: &couponv1.GetCampaignRequest_CampaignId{CampaignId: campaign.Id},
+	}))
+	if err != nil {
+		t.Fatalf("GetCampaign: %v", err)
+	}
+	codes := slices.Clone(got.Msg.Campaign.IssuedCouponCodes)
+	slices.Sort(codes)
+	slices.Sort(issued)
+	if !slices.Equal(codes, issued) {
+		t.Errorf("GetCampaign issued codes = %v, want %v", codes, issued)
+	}
+	if got.Msg.State != couponv1.CampaignState_CAMPAIGN_STATE_ENDED {
+		t.Errorf("state of a sold-out campaign = %v, want ENDED", got.Msg.State)
+	}
+}
+
+func TestConcurrentIssuanceNeverDoublesUp(t *testing.T) {
+	const (
+		coupons  = 50
+		requests = 200
+	)
+	s, _ := newTestServer(t, nil)
+	campaign := createTestCampaign(t, s, coupons, nil)
+
+	// Every request races for the same rows; SKIP LOCKED must hand each
+	// coupon to exactly one of them
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		codes   = make(map[string]string)
+		soldOut int
+		failed  []error
+	)
+	for i := range requests {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			userID := fmt.Sprintf("user-%d", i)
+			resp, err := issueTestCoupon(s, campaign.Id, userID)
+
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err == nil:
+				if other, ok := codes[resp.Coupon.Code]; ok {
+					failed = append(failed, fmt.Errorf("code %s issued to %s and %s", resp.Coupon.Code, other, userID))
+				}
+				codes[resp.Coupon.Code] = userID
+			case errorCodeOf(err) == ErrSoldOut:
+				soldOut++
+			default:
+				failed = append(failed, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	for _, err := range failed {
+		t.Error(err)
+	}
+	if len(codes) != coupons {
+		t.Errorf("%d coupons issued, want %d", len(codes), coupons)
+	}
+	if soldOut != requests-coupons {
+		t.Errorf("%d requests sold out, want %d", soldOut, requests-coupons)
+	}
+
+	var issued int
+	if err := testDB.Get(&issued, `SELECT COUNT(*) FROM coupons WHERE campaign_id = $1 AND status = 'issued'`, campaign.Id); err != nil {
+		t.Fatalf("count issued coupons: %v", err)
+	}
+	if issued != coupons {
+		t.Errorf("%d coupons marked issued, want %d", issued, coupons)
+	}
+}
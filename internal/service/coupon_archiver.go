@@ -0,0 +1,129 @@
+package service
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/kkkkikiki/coupon/internal/metrics"
+)
+
+const (
+	// archiveInterval is how often finished campaigns are looked for
+	archiveInterval = time.Hour
+	// archiveCampaignBatch caps the campaigns archived per round
+	archiveCampaignBatch = 100
+	// archiveBatchSize caps the coupons moved per statement so archiving a
+	// large campaign doesn't hold one long transaction
+	archiveBatchSize = 1000
+)
+
+// CouponArchiver periodically moves the coupons of finished campaigns from
+// the coupons table to archived_coupons, keeping the hot table and its
+// indexes to campaigns that can still issue. A campaign is finished once all
+// its coupons are redeemed, expired or revoked and none was issued for the
+// server's archive delay. Lookups by code still find archived coupons.
+type CouponArchiver struct {
+	server *CouponServer
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewCouponArchiver creates an archiver for server's campaigns
+func NewCouponArchiver(server *CouponServer) *CouponArchiver {
+	return &CouponArchiver{server: server}
+}
+
+// Start launches the archive loop. It does nothing when archival is disabled.
+func (a *CouponArchiver) Start() {
+	if a.server.archiveAfter <= 0 {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	a.cancel = cancel
+
+	a.wg.Add(1)
+	go func() {
+		defer a.wg.Done()
+
+		ticker := time.NewTicker(archiveInterval)
+		defer ticker.Stop()
+
+		for {
+			a.archive(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop stops the archive loop and waits for it to exit
+func (a *CouponArchiver) Stop() {
+	if a.cancel == nil {
+		return
+	}
+	a.cancel()
+	a.wg.Wait()
+}
+
+// archive archives one round of finished campaigns
+func (a *CouponArchiver) archive(ctx context.Context) {
+	before := a.server.clock.Now().Add(-a.server.archiveAfter)
+
+	var campaignIDs []int64
+	err := a.server.guardDB(ctx, func() error {
+		var err error
+		campaignIDs, err = a.server.couponRepo.FindArchivableCampaigns(ctx, a.server.postgres, before, archiveCampaignBatch)
+		return err
+	})
+	if err != nil {
+		if ctx.Err() == nil {
+			log.Printf("Failed to find campaigns to archive: %v", err)
+		}
+		return
+	}
+
+	for _, campaignID := range campaignIDs {
+		if !a.archiveCampaign(ctx, campaignID) {
+			return
+		}
+	}
+}
+
+// archiveCampaign moves a finished campaign's coupons in batches until none
+// are left. It returns false when archiving should stop for this round.
+func (a *CouponArchiver) archiveCampaign(ctx context.Context, campaignID int64) bool {
+	var total int64
+	defer func() {
+		if total > 0 {
+			log.Printf("Archived %d coupons of campaign %d", total, campaignID)
+		}
+	}()
+
+	for ctx.Err() == nil {
+		var moved int64
+		err := a.server.guardDB(ctx, func() error {
+			var err error
+			moved, err = a.server.couponRepo.ArchiveCoupons(ctx, a.server.postgres, campaignID, archiveBatchSize, a.server.clock.Now())
+			return err
+		})
+		if err != nil {
+			if ctx.Err() == nil {
+				log.Printf("Failed to archive coupons of campaign %d: %v", campaignID, err)
+			}
+			return false
+		}
+		total += moved
+		metrics.RecordArchived(moved)
+		if moved < archiveBatchSize {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,89 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+
+	"connectrpc.com/connect"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	couponv1 "github.com/kkkkikiki/coupon/gen/coupon/v1"
+	"github.com/kkkkikiki/coupon/internal/model"
+	"github.com/kkkkikiki/coupon/internal/repository"
+)
+
+// couponTokenVersion prefixes signed coupon token messages so the format can
+// change without ambiguity
+const couponTokenVersion = "v1"
+
+// GetCouponPayload returns the code of an issued coupon with a signed token
+// that scanners can verify without calling the service
+func (s *CouponServer) GetCouponPayload(
+	ctx context.Context,
+	req *connect.Request[couponv1.GetCouponPayloadRequest],
+) (*connect.Response[couponv1.GetCouponPayloadResponse], error) {
+	campaignID, err := s.codeScope(req.Msg.CampaignId, req.Msg.Code)
+	if err != nil {
+		return nil, err
+	}
+	if len(s.signingSecret) == 0 {
+		return nil, newServiceError(ErrFailedPrecondition, fmt.Errorf("coupon signing is not configured"))
+	}
+	if err := s.checkLookup(ctx, req.Peer(), req.Header(), campaignID); err != nil {
+		return nil, err
+	}
+
+	var coupon *model.Coupon
+	err = s.guardDB(ctx, func() error {
+		var err error
+		coupon, err = s.couponRepo.GetCoupon(ctx, s.postgres, campaignID, s.storedCode(req.Msg.Code))
+		if err != nil {
+			if errors.Is(err, repository.ErrCouponNotFound) {
+				s.lookupGuard.failed(campaignID)
+				return newServiceError(ErrCouponNotFound, err)
+			}
+			return newServiceError(ErrDB, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	// The stored code may be a hash; sign the code asked for
+	coupon.Code = req.Msg.Code
+
+	if coupon.Status != model.CouponStatusIssued {
+		return nil, newServiceError(ErrFailedPrecondition, fmt.Errorf("coupon is %s, only issued coupons have a payload", coupon.Status))
+	}
+
+	payload := &couponv1.GetCouponPayloadResponse{
+		Code:       coupon.Code,
+		CampaignId: coupon.CampaignID,
+		Token:      signCouponToken(s.signingSecret, coupon),
+	}
+	if coupon.ExpiresAt != nil {
+		payload.ExpiresAt = timestamppb.New(*coupon.ExpiresAt)
+	}
+
+	return connect.NewResponse(payload), nil
+}
+
+// signCouponToken builds "<message>.<signature>" as documented on
+// GetCouponPayloadResponse
+func signCouponToken(secret []byte, coupon *model.Coupon) string {
+	var expiresAt int64
+	if coupon.ExpiresAt != nil {
+		expiresAt = coupon.ExpiresAt.Unix()
+	}
+	message := fmt.Sprintf("%s\n%s\n%d\n%d", couponTokenVersion, coupon.Code, coupon.CampaignID, expiresAt)
+
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(message))
+
+	return base64.RawURLEncoding.EncodeToString([]byte(message)) + "." +
+		base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
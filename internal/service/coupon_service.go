@@ -2,35 +2,164 @@ package service
 
 import (
 	"context"
-	"crypto/aes"
 	"encoding/binary"
+	"errors"
 	"fmt"
+	"log"
+	"strconv"
+	"sync"
 	"time"
 
 	"connectrpc.com/connect"
+	"google.golang.org/protobuf/types/known/durationpb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/sony/gobreaker"
 
 	couponv1 "github.com/kkkkikiki/coupon/gen/coupon/v1"
+	"github.com/kkkkikiki/coupon/internal/codetag"
+	"github.com/kkkkikiki/coupon/internal/config"
+	"github.com/kkkkikiki/coupon/internal/interceptor"
 	"github.com/kkkkikiki/coupon/internal/metrics"
 	"github.com/kkkkikiki/coupon/internal/model"
 	"github.com/kkkkikiki/coupon/internal/repository"
 )
 
+const (
+	// defaultDryRunSamples is the number of sample codes returned by a dry-run create
+	defaultDryRunSamples = 10
+	// maxDryRunSamples caps the sample codes a dry-run create may request
+	maxDryRunSamples = 100
+	// maxIssuedSample caps the issued codes GetCampaign samples
+	maxIssuedSample = 100
+
+	// defaultRecentWindow is the lookback of the recent issuance count in
+	// campaign stats
+	defaultRecentWindow = time.Hour
+	// maxRecentWindow caps the lookback so the count stays an index range scan
+	// over recent rows
+	maxRecentWindow = 24 * time.Hour
+)
+
 // CouponServer implements the coupon service
 type CouponServer struct {
 	postgres     *sqlx.DB
 	campaignRepo *repository.CampaignRepository
 	couponRepo   *repository.CouponRepository
+	cooldownRepo *repository.CooldownRepository
+	idemRepo     *repository.IdempotencyRepository
+	breaker      *gobreaker.CircuitBreaker
+
+	// clock is the source of the current time
+	clock Clock
+
+	// issueLimiter rate limits IssueCoupon per campaign
+	issueLimiter *campaignLimiter
+
+	// lookupGuard rate limits coupon lookups by code per source
+	lookupGuard *lookupGuard
+
+	// clientIPHeader names the header carrying the client IP set by a
+	// trusted proxy; empty uses the connection's peer address
+	clientIPHeader string
+
+	// remaining caches GetRemaining counts per campaign
+	remaining *remainingCache
+
+	// maxPageSize caps the page size of list RPCs; larger requests are
+	// silently clamped
+	maxPageSize int
+
+	// userCooldown is the minimum time between two issuances to the same
+	// user across all campaigns; zero disables the check
+	userCooldown time.Duration
+
+	// idempotencyTTL is how long an idempotency key replays its coupon;
+	// older keys are rejected as expired. Zero keeps keys forever.
+	idempotencyTTL time.Duration
+
+	// defaultCouponTTL is how long after issuance a coupon without an
+	// explicit expiry expires; zero means no expiry
+	defaultCouponTTL time.Duration
+
+	// archiveAfter is how long after its last issuance a finished campaign's
+	// coupons are archived; zero disables archival
+	archiveAfter time.Duration
+
+	// signingSecret keys the HMAC of coupon payload tokens; empty disables
+	// GetCouponPayload
+	signingSecret []byte
+
+	// exportCodes selects how coupon codes appear in the issuance export
+	exportCodes string
+
+	// minCodeEntropy is the minimum guessing entropy, in bits, of a
+	// campaign's code format for its number of coupons
+	minCodeEntropy float64
+
+	// maxRequestCoupons caps the coupons generated by one BatchCreateCampaigns
+	// call, bounding the size of its transaction
+	maxRequestCoupons int64
+
+	// campaignKeys caches the AES cipher of each campaign's key
+	campaignKeys *campaignKeyCache
+
+	// codeSecret keys code generation and stored code hashes; set only with
+	// hashed code storage
+	codeSecret []byte
+
+	// writeTimeout is the server write timeout; streaming handlers grant it
+	// to each write instead of to the whole response
+	writeTimeout time.Duration
+
+	// scopedCodes is set when codes are only unique per campaign, so lookups
+	// by code need a campaign ID
+	scopedCodes bool
+
+	// slugIDs caches campaign IDs resolved from slugs (string -> int64)
+	slugIDs sync.Map
+
+	// activations is set by NewActivationScheduler; nil when no scheduler runs
+	activations *ActivationScheduler
+
+	// stats counts issuance calls for Stats
+	stats issueStats
 }
 
 // NewCouponServer creates a new CouponServer instance
-func NewCouponServer(postgres *sqlx.DB) *CouponServer {
+func NewCouponServer(postgres *sqlx.DB, cfg *config.Config) *CouponServer {
+	return NewCouponServerWithClock(postgres, cfg, realClock{})
+}
+
+// NewCouponServerWithClock creates a CouponServer reading the current time
+// from clock
+func NewCouponServerWithClock(postgres *sqlx.DB, cfg *config.Config, clock Clock) *CouponServer {
 	return &CouponServer{
-		postgres:     postgres,
-		campaignRepo: repository.NewCampaignRepository(),
-		couponRepo:   repository.NewCouponRepository(),
+		postgres:          postgres,
+		campaignRepo:      repository.NewCampaignRepository(),
+		couponRepo:        repository.NewCouponRepository(),
+		cooldownRepo:      repository.NewCooldownRepository(),
+		idemRepo:          repository.NewIdempotencyRepository(),
+		breaker:           newDBBreaker(cfg.Database),
+		clock:             clock,
+		issueLimiter:      newCampaignLimiter(cfg.App.PerCampaignRPS, cfg.App.PerCampaignBurst, clock),
+		lookupGuard:       newLookupGuard(cfg.App.LookupRPS, cfg.App.LookupBurst, cfg.App.LookupFailureThreshold, clock),
+		clientIPHeader:    cfg.App.ClientIPHeader,
+		remaining:         newRemainingCache(time.Duration(cfg.App.RemainingCacheTTL)*time.Millisecond, clock),
+		userCooldown:      time.Duration(cfg.App.UserCooldown) * time.Second,
+		maxPageSize:       cfg.App.MaxPageSize,
+		idempotencyTTL:    time.Duration(cfg.App.IdempotencyKeyTTL) * time.Second,
+		defaultCouponTTL:  time.Duration(cfg.App.DefaultCouponTTL) * time.Second,
+		archiveAfter:      time.Duration(cfg.App.ArchiveAfter) * time.Second,
+		signingSecret:     []byte(cfg.App.CouponSigningSecret),
+		exportCodes:       cfg.App.ExportCodes,
+		writeTimeout:      time.Duration(cfg.Server.WriteTimeout) * time.Second,
+		minCodeEntropy:    cfg.App.MinCodeEntropyBits,
+		maxRequestCoupons: cfg.App.MaxRequestCoupons,
+		codeSecret:        codeSecret(cfg.App),
+		campaignKeys:      newCampaignKeyCache(campaignKeyCacheSize),
+		scopedCodes:       cfg.App.CodeNamespace == "campaign",
 	}
 }
 
@@ -39,76 +168,214 @@ func (s *CouponServer) CreateCampaign(
 	ctx context.Context,
 	req *connect.Request[couponv1.CreateCampaignRequest],
 ) (*connect.Response[couponv1.CreateCampaignResponse], error) {
-	// Create campaign model
-	campaign := &model.Campaign{
-		AvailableCoupons: req.Msg.AvailableCoupons,
-		StartDate:        req.Msg.StartDate.AsTime(),
+	// Report every invalid field at once
+	var v validator
+	campaign, allocations := s.campaignFromRequest(&v, req.Msg)
+	if err := v.err(); err != nil {
+		return nil, err
 	}
+	couponCount := campaign.AvailableCoupons
 
-	// Start transaction
-	tx, err := s.postgres.BeginTxx(ctx, nil)
-	if err != nil {
-		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("failed to begin transaction: %w", err))
+	requestID := req.Msg.RequestId
+	if req.Msg.DryRun {
+		requestID = ""
 	}
-	defer tx.Rollback()
 
-	// Create campaign in database (this will set campaign.ID)
-	if err := s.campaignRepo.CreateCampaign(tx, campaign); err != nil {
-		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("failed to create campaign: %w", err))
-	}
+	var sampleCodes []string
+	var replayed bool
+	err := s.guardDB(ctx, func() error {
+		// Start transaction
+		tx, err := s.postgres.BeginTxx(ctx, nil)
+		if err != nil {
+			return newServiceError(ErrDB, fmt.Errorf("failed to begin transaction: %w", err))
+		}
+		defer tx.Rollback()
 
-	// Pre-generate all coupon codes using the generated campaign ID
-	couponCodes := make([]string, 0, int(req.Msg.AvailableCoupons))
-	for i := 0; i < int(req.Msg.AvailableCoupons); i++ {
-		// Use campaign ID + coupon index for unique generation
-		code, err := s.generateSecureCoupon(campaign.ID, uint64(i))
+		// Create campaign in database (this will set campaign.ID)
+		created, err := s.campaignRepo.CreateCampaign(ctx, tx, campaign, requestID, s.clock.Now())
 		if err != nil {
-			return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("failed to generate coupon code: %w", err))
+			if errors.Is(err, repository.ErrCampaignSlugExists) {
+				return newServiceError(ErrAlreadyExists, err)
+			}
+			return newServiceError(ErrDB, fmt.Errorf("failed to create campaign: %w", err))
 		}
-		couponCodes = append(couponCodes, code)
-	}
 
-	// Store coupons in DB only (DB-centric approach)
-	if err := s.couponRepo.CreatePregeneratedCoupons(tx, campaign.ID, couponCodes); err != nil {
-		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("failed to store coupons in DB: %w", err))
-	}
+		// A retry of an already completed request returns the same campaign.
+		// The unique insert waits for a concurrent first attempt to finish.
+		if !created {
+			existing, err := s.campaignRepo.GetCampaignByRequestID(ctx, tx, requestID)
+			if err != nil {
+				return newServiceError(ErrDB, err)
+			}
+			campaign, replayed = existing, true
+			return nil
+		}
+
+		// Dry run: preview the first few codes, then let the deferred
+		// rollback discard the campaign row without committing
+		if req.Msg.DryRun {
+			sampleCodes, err = s.generateCouponCodes(campaign, 0, dryRunSampleSize(req.Msg, couponCount))
+			if err != nil {
+				return generationFailure(err)
+			}
+			campaign.ID = 0
+			return nil
+		}
 
-	// Commit transaction
-	if err := tx.Commit(); err != nil {
-		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("failed to commit transaction: %w", err))
+		// Pre-generate all coupon codes using the generated campaign ID and
+		// store them in DB only (DB-centric approach)
+		if err := s.storeCoupons(ctx, tx, campaign, 0, allocations); err != nil {
+			return err
+		}
+
+		// Commit transaction
+		if err := tx.Commit(); err != nil {
+			return newServiceError(ErrDB, fmt.Errorf("failed to commit transaction: %w", err))
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !req.Msg.DryRun && !replayed {
+		s.rescanActivations()
 	}
 
 	// Convert to protobuf response
-	protoCampaign := &couponv1.Campaign{
-		Id:                campaign.ID,
-		AvailableCoupons:  campaign.AvailableCoupons,
-		StartDate:         timestamppb.New(campaign.StartDate),
-		IssuedCouponCodes: []string{}, // Initially empty
-	}
+	protoCampaign := toProtoCampaign(campaign, []string{}) // Initially no issued codes
 
 	res := connect.NewResponse(&couponv1.CreateCampaignResponse{
-		Campaign: protoCampaign,
+		Campaign:    protoCampaign,
+		SampleCodes: sampleCodes,
+		Replayed:    replayed,
 	})
 
 	return res, nil
 }
 
-// generateSecureCoupon generates AES-encrypted coupon code (always 10 characters)
-func (s *CouponServer) generateSecureCoupon(campaignID int64, couponIndex uint64) (string, error) {
-	// "읽기 편한" 28자 + 숫자 10개 = 38문자
-	digits := []rune("0123456789")
-	hanguls := []rune("가나다라마바사아자차카타파하거너더러머버서어저처커터퍼허")
-	pool := append(digits, hanguls...) // 38 rune
-	base := uint64(len(pool))          // 38
+// campaignFromRequest validates the fields of a create request, recording
+// violations in v, and returns the campaign to create with the coupons to
+// generate for it. The result is only meaningful when v has no violations.
+func (s *CouponServer) campaignFromRequest(v *validator, msg *couponv1.CreateCampaignRequest) (*model.Campaign, []poolAllocation) {
+	// Zero coupons is a valid (immediately sold-out) campaign; negative is not
+	v.check(msg.AvailableCoupons >= 0, "available_coupons", "must not be negative")
+	v.check(msg.StartDate != nil, "start_date", "is required")
+	v.check(msg.DiscountValue >= 0, "discount_value", "must not be negative")
+	v.check(msg.Budget >= 0, "budget", "must not be negative")
+	v.check(msg.Budget <= 0 || msg.DiscountValue != 0, "budget", "requires a discount_value")
+	v.check(msg.PerUserLimit >= 0, "per_user_limit", "must not be negative")
+	v.add("max_issue_rps", validateMaxIssueRPS(msg.MaxIssueRps))
+	v.add("coupon_metadata", validateMetadata(msg.CouponMetadata))
+	v.add("slug", validateSlug(msg.Slug))
+	shuffleSeed, err := resolveShuffleSeed(msg.IssuanceOrder, msg.ShuffleSeed)
+	v.add("shuffle_seed", err)
+	allocations := validatePools(v, msg.Pools, msg.AvailableCoupons, s.clock.Now())
+	couponCount := int32(allocatedCount(allocations))
+
+	// Resolve the campaign's code format (defaults for unset fields)
+	alphabet, length, prefix, routingTag, err := resolveCodeFormat(msg.CodeFormat, defaultCodeFormat(), couponCount)
+	v.add("code_format", err)
+	if err == nil {
+		v.add("code_format", s.checkCodeEntropy(alphabet, length, prefix, routingTag, couponCount))
+	}
+
+	campaign := &model.Campaign{
+		AvailableCoupons: couponCount,
+		StartDate:        msg.StartDate.AsTime(),
+		CodeAlphabet:     alphabet,
+		CodeLength:       length,
+		CodePrefix:       prefix,
+		CodeRoutingTag:   routingTag,
+		NextCodeIndex:    int64(couponCount),
+		DiscountValue:    msg.DiscountValue,
+		Budget:           msg.Budget,
+		PerUserLimit:     msg.PerUserLimit,
+		MaxIssueRPS:      msg.MaxIssueRps,
+		ShuffleSeed:      shuffleSeed,
+		ReservationOrder: reservationOrders[msg.IssuanceOrder],
+	}
+	if len(msg.CouponMetadata) > 0 {
+		campaign.CouponMetadata = model.Metadata(msg.CouponMetadata)
+	}
+	if msg.Slug != "" {
+		campaign.Slug = &msg.Slug
+	}
+	v.add("issuance_window", applyIssuanceWindow(campaign, msg.IssuanceWindow))
+	return campaign, allocations
+}
 
+// dryRunSampleSize returns how many sample codes a dry-run create of
+// couponCount coupons generates
+func dryRunSampleSize(req *couponv1.CreateCampaignRequest, couponCount int32) int {
+	n := int(req.SampleSize)
+	if n <= 0 {
+		n = defaultDryRunSamples
+	}
+	if n > maxDryRunSamples {
+		n = maxDryRunSamples
+	}
+	if n > int(couponCount) {
+		n = int(couponCount)
+	}
+	return n
+}
+
+// GenerationError reports the coupon index whose code could not be generated
+type GenerationError struct {
+	CampaignID int64
+	Index      uint64
+	Err        error
+}
+
+func (e *GenerationError) Error() string {
+	return fmt.Sprintf("campaign %d coupon index %d: %v", e.CampaignID, e.Index, e.Err)
+}
+
+func (e *GenerationError) Unwrap() error {
+	return e.Err
+}
+
+// generateCouponCodes generates codes for coupon indexes [start, start+count)
+// of a campaign. A failure is returned as a *GenerationError, and codes that
+// collide within the batch as an InvalidArgument naming the code format.
+func (s *CouponServer) generateCouponCodes(campaign *model.Campaign, start uint64, count int) ([]string, error) {
+	couponCodes := make([]string, 0, count)
+	seen := make(map[string]struct{}, count)
+
+	// Recorded once per batch to keep the per-code overhead out of the loop
+	begin := s.clock.Now()
+	defer func() {
+		metrics.RecordCodeGeneration(len(couponCodes), s.clock.Now().Sub(begin).Seconds())
+	}()
+
+	for i := 0; i < count; i++ {
+		// Use campaign ID + coupon index for unique generation
+		index := start + uint64(i)
+		code, err := s.generateSecureCoupon(campaign, index)
+		if err != nil {
+			return nil, &GenerationError{CampaignID: campaign.ID, Index: index, Err: err}
+		}
+		if _, ok := seen[code]; ok {
+			return nil, codeCollisionError(campaign)
+		}
+		seen[code] = struct{}{}
+		couponCodes = append(couponCodes, code)
+	}
+	return couponCodes, nil
+}
+
+// generateSecureCoupon generates an AES-encrypted coupon code in the campaign's
+// code format. The default format is always 10 characters: a digit, a Hangul
+// syllable and an 8-character body.
+func (s *CouponServer) generateSecureCoupon(campaign *model.Campaign, couponIndex uint64) (string, error) {
 	// Campaign ID + Coupon Index로 고유한 시퀀스 생성
-	seq := s.createUniqueSequence(campaignID, couponIndex)
+	seq := s.createUniqueSequence(campaign.ID, couponIndex)
 
-	// AES 키 생성 (캠페인별 고정 키)
-	key := s.generateCampaignKey(campaignID)
-	block, err := aes.NewCipher(key)
+	// AES 키 (캠페인별 고정 키, 캐시됨)
+	block, err := s.campaignCipher(campaign.ID)
 	if err != nil {
-		return "", fmt.Errorf("failed to create AES cipher: %w", err)
+		return "", err
 	}
 
 	// ① 128-bit 평문: 상위 64bit 0, 하위 64bit = seq
@@ -119,6 +386,27 @@ func (s *CouponServer) generateSecureCoupon(campaignID int64, couponIndex uint64
 	var cipher [16]byte
 	block.Encrypt(cipher[:], plain[:])
 
+	// 캠페인별 커스텀 포맷: prefix + 알파벳 본문 (+ 라우팅 태그)
+	if !isDefaultCodeFormat(campaign) {
+		code := encodeCustomCode(cipher, campaign)
+		if campaign.CodeRoutingTag {
+			// The tag is the same for every code of the campaign, so
+			// uniqueness within it rests on the body alone
+			tag, err := codetag.Tag(campaign.ID)
+			if err != nil {
+				return "", err
+			}
+			code += tag
+		}
+		return code, nil
+	}
+
+	// "읽기 편한" 28자 + 숫자 10개 = 38문자
+	digits := []rune("0123456789")
+	hanguls := []rune("가나다라마바사아자차카타파하거너더러머버서어저처커터퍼허")
+	pool := append(digits, hanguls...) // 38 rune
+	base := uint64(len(pool))          // 38
+
 	// ③ 필수 문자인 앞 2글자
 	digit := digits[cipher[0]%10]                  // 숫자
 	hang := hanguls[cipher[1]%uint8(len(hanguls))] // 한글
@@ -153,6 +441,10 @@ func (s *CouponServer) campaignIDToSequence(campaignID int64) uint64 {
 
 // generateCampaignKey generates a deterministic AES key for campaign
 func (s *CouponServer) generateCampaignKey(campaignID int64) []byte {
+	if s.hashedStorage() {
+		return s.hashedCampaignKey(campaignID)
+	}
+
 	// 캠페인별 고정 키 생성 (16바이트)
 	key := make([]byte, 16)
 	hash := s.campaignIDToSequence(campaignID)
@@ -164,30 +456,56 @@ func (s *CouponServer) generateCampaignKey(campaignID int64) []byte {
 	return key
 }
 
-// GetCampaign gets campaign information including all issued coupon codes
+// GetCampaign gets campaign information including all issued coupon codes, or
+// a random sample of them when sample_size is set
 func (s *CouponServer) GetCampaign(
 	ctx context.Context,
 	req *connect.Request[couponv1.GetCampaignRequest],
 ) (*connect.Response[couponv1.GetCampaignResponse], error) {
-	// Get campaign with issued coupon codes from database
-	campaign, couponCodes, err := s.campaignRepo.GetCampaignWithCoupons(s.postgres, req.Msg.CampaignId)
+	if req.Msg.IncludeDeleted && !interceptor.IsAdmin(ctx) {
+		return nil, newServiceError(ErrPermissionDenied, fmt.Errorf("include_deleted requires the admin API key"))
+	}
+	if req.Msg.SampleSize < 0 {
+		return nil, newServiceError(ErrInvalidArgument, fmt.Errorf("sample_size must not be negative"))
+	}
+	campaignID, err := s.resolveCampaignID(ctx, req.Msg.GetCampaignId(), req.Msg.GetSlug())
 	if err != nil {
-		if err.Error() == "campaign not found" {
-			return nil, connect.NewError(connect.CodeNotFound, err)
+		return nil, err
+	}
+
+	// Get campaign with issued coupon codes from database
+	var campaign *model.Campaign
+	var couponCodes []string
+	var remaining int32
+	err = s.guardDB(ctx, func() error {
+		var err error
+		campaign, couponCodes, err = s.campaignRepo.GetCampaignWithCoupons(ctx, s.postgres, campaignID, req.Msg.IncludeDeleted,
+			min(int(req.Msg.SampleSize), maxIssuedSample))
+		if err != nil {
+			if errors.Is(err, repository.ErrCampaignNotFound) {
+				return newServiceError(ErrCampaignNotFound, err)
+			}
+			return newServiceError(ErrDB, fmt.Errorf("failed to get campaign: %w", err))
+		}
+
+		// The state needs the available count of live campaigns; share
+		// GetRemaining's cache
+		if campaign.DeletedAt != nil {
+			return nil
 		}
-		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("failed to get campaign: %w", err))
+		remaining, err = s.countRemaining(ctx, campaignID)
+		return err
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	// Convert to protobuf response
-	protoCampaign := &couponv1.Campaign{
-		Id:                campaign.ID,
-		AvailableCoupons:  campaign.AvailableCoupons,
-		StartDate:         timestamppb.New(campaign.StartDate),
-		IssuedCouponCodes: couponCodes,
-	}
+	protoCampaign := toProtoCampaign(campaign, couponCodes)
 
 	res := connect.NewResponse(&couponv1.GetCampaignResponse{
 		Campaign: protoCampaign,
+		State:    campaignState(campaign, remaining, s.clock.Now()),
 	})
 
 	return res, nil
@@ -199,67 +517,728 @@ func (s *CouponServer) IssueCoupon(
 	req *connect.Request[couponv1.IssueCouponRequest],
 ) (*connect.Response[couponv1.IssueCouponResponse], error) {
 	// Start timing for metrics
-	start := time.Now()
+	start := s.clock.Now()
 	result := "failed"
 
 	// Defer metric recording to ensure it's always called
+	done := s.stats.begin()
 	defer func() {
-		duration := time.Since(start).Seconds()
+		duration := s.clock.Now().Sub(start).Seconds()
 		metrics.RecordIssueCouponDuration(result, duration)
+		done(result == "success")
 	}()
 
-	// Get campaign from database for initial checks
-	campaign, err := s.campaignRepo.GetCampaign(s.postgres, req.Msg.CampaignId)
+	campaignID, err := s.resolveCampaignID(ctx, req.Msg.GetCampaignId(), req.Msg.GetSlug())
+	if err != nil {
+		return nil, err
+	}
+
+	// Reject over-limit requests before they reach the database
+	if ok, retryAfter := s.issueLimiter.allow(campaignID); !ok {
+		return nil, newRetryableError("campaign rate limit exceeded", retryAfter)
+	}
+
+	var couponCode, pool string
+	var expiresAt *time.Time
+	var replayed bool
+	err = s.guardDB(ctx, func() error {
+		// A retry of an already completed request returns the same coupon
+		if key := req.Msg.IdempotencyKey; key != "" {
+			code, found, err := s.lookupIdempotentIssue(ctx, key, campaignID)
+			if err != nil {
+				return err
+			}
+			if found {
+				couponCode, replayed = code, true
+				return nil
+			}
+		}
+
+		// Get campaign from database for initial checks
+		campaign, err := s.campaignRepo.GetCampaign(ctx, s.postgres, campaignID)
+		if err != nil {
+			if errors.Is(err, repository.ErrCampaignNotFound) {
+				return newServiceError(ErrCampaignNotFound, err)
+			}
+			return newServiceError(ErrDB, fmt.Errorf("failed to get campaign: %w", err))
+		}
+		// Pick up rate changes made through UpdateCampaign on any replica
+		s.issueLimiter.setRate(campaign.ID, campaign.MaxIssueRPS)
+
+		// A campaign created without coupons is sold out from the start
+		if campaign.AvailableCoupons == 0 {
+			return newSoldOutError(campaignID, couponv1.SoldOutReason_SOLD_OUT_REASON_NO_COUPONS, 0)
+		}
+
+		// Fast path for budgets already spent; the authoritative check is the
+		// conditional charge inside the transaction
+		if budgetExhausted(campaign) {
+			return newSoldOutError(campaignID, couponv1.SoldOutReason_SOLD_OUT_REASON_BUDGET_EXHAUSTED,
+				s.remainingBestEffort(ctx, campaignID))
+		}
+
+		// Check if campaign has started
+		now := s.clock.Now()
+		if now.Before(campaign.StartDate) {
+			return newServiceError(ErrNotStarted, fmt.Errorf("campaign has not started yet"))
+		}
+		if err := checkIssuanceWindow(campaign, now); err != nil {
+			return err
+		}
+
+		// DB-centric approach: Use DB as single source of truth
+		// Start transaction for atomic coupon reservation
+		tx, err := s.postgres.BeginTxx(ctx, nil)
+		if err != nil {
+			return newServiceError(ErrDB, fmt.Errorf("failed to begin transaction: %w", err))
+		}
+		defer tx.Rollback()
+
+		// Enforce the per-user cooldown before reserving. The claim is part of
+		// the transaction, so a failed issuance doesn't start a cooldown.
+		if s.userCooldown > 0 && req.Msg.UserId != "" {
+			retryAfter, err := s.cooldownRepo.ClaimIssueCooldown(ctx, tx, req.Msg.UserId, now, s.userCooldown)
+			if err != nil {
+				return newServiceError(ErrDB, fmt.Errorf("failed to check user cooldown: %w", err))
+			}
+			if retryAfter > 0 {
+				return newRetryableError("issuance cooldown active", retryAfter)
+			}
+		}
+
+		if campaign.PerUserLimit > 0 {
+			allowance, err := s.userAllowance(ctx, tx, campaign, req.Msg.UserId)
+			if err != nil {
+				return err
+			}
+			if allowance == 0 {
+				return newPerUserLimitError(campaign)
+			}
+		}
+
+		// Reserve an available coupon directly from DB (atomic operation)
+		code, codePool, err := s.couponRepo.ReserveAvailableCoupon(ctx, tx, campaignID, campaign.ReservationOrder, req.Msg.Pool, now)
+		if err != nil {
+			if errors.Is(err, repository.ErrNoAvailableCoupons) {
+				return newSoldOutError(campaignID, couponv1.SoldOutReason_SOLD_OUT_REASON_NO_COUPONS, 0)
+			}
+			return newServiceError(ErrDB, fmt.Errorf("failed to reserve coupon: %w", err))
+		}
+
+		// Charge the coupon's value against the budget. Done after reserving so
+		// sold-out requests never touch the campaign row.
+		if campaign.Budget > 0 {
+			charged, err := s.campaignRepo.ChargeBudget(ctx, tx, campaignID, 1)
+			if err != nil {
+				return newServiceError(ErrDB, err)
+			}
+			if charged == 0 {
+				tx.Rollback()
+				return newSoldOutError(campaignID, couponv1.SoldOutReason_SOLD_OUT_REASON_BUDGET_EXHAUSTED,
+					s.remainingBestEffort(ctx, campaignID))
+			}
+		}
+
+		// Mark the reserved coupon as issued
+		expiresAt, err = s.couponRepo.MarkCouponAsIssued(ctx, tx, campaignID, code, req.Msg.UserId, s.clock.Now(), s.defaultCouponTTL)
+		if err != nil {
+			return markIssuedError(code, err)
+		}
+
+		if key := req.Msg.IdempotencyKey; key != "" {
+			saved, err := s.idemRepo.SaveIssuedCoupon(ctx, tx, key, campaignID, code, s.clock.Now())
+			if err != nil {
+				return newServiceError(ErrDB, err)
+			}
+			if !saved {
+				// A concurrent request with the same key won; release our
+				// coupon and return theirs
+				tx.Rollback()
+				code, found, err := s.lookupIdempotentIssue(ctx, key, campaignID)
+				if err != nil {
+					return err
+				}
+				if !found {
+					return newServiceError(ErrAborted, fmt.Errorf("concurrent request with the same idempotency key did not complete"))
+				}
+				couponCode, replayed = code, true
+				return nil
+			}
+		}
+
+		// In hashed storage the code only exists in this response
+		revealed, err := s.revealCodes(ctx, tx, campaign, []string{code})
+		if err != nil {
+			return err
+		}
+
+		// Commit DB transaction - this guarantees consistency
+		if err := tx.Commit(); err != nil {
+			return newServiceError(ErrDB, fmt.Errorf("failed to commit transaction: %w", err))
+		}
+		couponCode, pool = revealed[0], codePool
+
+		return nil
+	})
+	if err == nil && replayed && s.hashedStorage() {
+		// Replays know the coupon only in its stored form
+		err = s.guardDB(ctx, func() error {
+			campaign, err := s.campaignRepo.GetCampaign(ctx, s.postgres, campaignID)
+			if err != nil {
+				return newServiceError(ErrDB, fmt.Errorf("failed to get campaign: %w", err))
+			}
+			revealed, err := s.revealCodes(ctx, s.postgres, campaign, []string{couponCode})
+			if err != nil {
+				return err
+			}
+			couponCode = revealed[0]
+			return nil
+		})
+	}
+	if err != nil {
+		if req.Msg.ReportOutcome {
+			if res := issueOutcomeResponse(err); res != nil {
+				return connect.NewResponse(res), nil
+			}
+		}
+		return nil, err
+	}
+	result = "success"
+
+	// Create protobuf response
+	coupon := &couponv1.Coupon{
+		Code:       couponCode,
+		CampaignId: campaignID,
+		Status:     couponv1.CouponStatus_COUPON_STATUS_ISSUED,
+		Pool:       pool,
+	}
+	if expiresAt != nil && !replayed {
+		coupon.ExpiresAt = timestamppb.New(*expiresAt)
+	}
+
+	res := connect.NewResponse(&couponv1.IssueCouponResponse{
+		Coupon:   coupon,
+		Replayed: replayed,
+		Outcome:  couponv1.IssueOutcome_ISSUE_OUTCOME_ISSUED,
+	})
+
+	return res, nil
+}
+
+// GetCampaignStats returns coupon counts for a campaign
+func (s *CouponServer) GetCampaignStats(
+	ctx context.Context,
+	req *connect.Request[couponv1.GetCampaignStatsRequest],
+) (*connect.Response[couponv1.GetCampaignStatsResponse], error) {
+	window, err := recentWindow(req.Msg.RecentWindow)
 	if err != nil {
-		if err.Error() == "campaign not found" {
-			return nil, connect.NewError(connect.CodeNotFound, err)
+		return nil, newServiceError(ErrInvalidArgument, err)
+	}
+
+	var stats *model.CampaignStats
+	err = s.guardDB(ctx, func() error {
+		var err error
+		_, stats, err = s.campaignRepo.GetCampaignStats(ctx, s.postgres, req.Msg.CampaignId, s.clock.Now(), window)
+		if err != nil {
+			if errors.Is(err, repository.ErrCampaignNotFound) {
+				return newServiceError(ErrCampaignNotFound, err)
+			}
+			return newServiceError(ErrDB, fmt.Errorf("failed to get campaign stats: %w", err))
 		}
-		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("failed to get campaign: %w", err))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res := connect.NewResponse(&couponv1.GetCampaignStatsResponse{
+		Stats: &couponv1.CampaignStats{
+			CampaignId:     req.Msg.CampaignId,
+			TotalCount:     stats.Total,
+			AvailableCount: stats.Available,
+			IssuedCount:    stats.Issued,
+			RevokedCount:   stats.Revoked,
+
+			RecentlyIssuedCount: stats.RecentlyIssued,
+			RecentWindow:        durationpb.New(window),
+			Pools:               toProtoPoolStats(stats.Pools),
+		},
+	})
+
+	return res, nil
+}
+
+// recentWindow resolves the lookback of the recent issuance count, applying
+// the default and clamping to maxRecentWindow
+func recentWindow(d *durationpb.Duration) (time.Duration, error) {
+	if d == nil {
+		return defaultRecentWindow, nil
+	}
+	if err := d.CheckValid(); err != nil {
+		return 0, fmt.Errorf("invalid recent_window: %w", err)
 	}
 
-	// Check if campaign has started
-	now := time.Now()
-	if now.Before(campaign.StartDate) {
-		return nil, connect.NewError(connect.CodeFailedPrecondition, fmt.Errorf("campaign has not started yet"))
+	window := d.AsDuration()
+	if window <= 0 {
+		return 0, fmt.Errorf("recent_window must be positive")
 	}
+	return min(window, maxRecentWindow), nil
+}
+
+// rescanActivations tells the activation scheduler, if any, that start
+// dates may have changed
+func (s *CouponServer) rescanActivations() {
+	if s.activations != nil {
+		s.activations.Rescan()
+	}
+}
 
-	// DB-centric approach: Use DB as single source of truth
-	// Start transaction for atomic coupon reservation
-	tx, err := s.postgres.BeginTxx(ctx, nil)
+// remainingBestEffort returns the number of coupons left for error details,
+// preferring the GetRemaining cache. Errors yield 0 since the caller is
+// already failing the request.
+func (s *CouponServer) remainingBestEffort(ctx context.Context, campaignID int64) int32 {
+	if count, ok := s.remaining.get(campaignID); ok {
+		return count
+	}
+	count, err := s.campaignRepo.CountAvailableCoupons(ctx, s.postgres, campaignID, s.clock.Now())
 	if err != nil {
-		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("failed to begin transaction: %w", err))
+		return 0
 	}
-	defer tx.Rollback()
+	s.remaining.set(campaignID, count)
+	return count
+}
 
-	// Reserve an available coupon directly from DB (atomic operation)
-	couponCode, err := s.couponRepo.ReserveAvailableCoupon(tx, req.Msg.CampaignId)
+// countRemaining returns the number of available coupons of a live campaign,
+// preferring the GetRemaining cache. Call it within guardDB.
+func (s *CouponServer) countRemaining(ctx context.Context, campaignID int64) (int32, error) {
+	if count, ok := s.remaining.get(campaignID); ok {
+		return count, nil
+	}
+	count, err := s.campaignRepo.CountAvailableCoupons(ctx, s.postgres, campaignID, s.clock.Now())
 	if err != nil {
-		if err.Error() == "no available coupons" {
-			return nil, connect.NewError(connect.CodeResourceExhausted, fmt.Errorf("no more coupons available"))
+		if errors.Is(err, repository.ErrCampaignNotFound) {
+			return 0, newServiceError(ErrCampaignNotFound, err)
 		}
-		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("failed to reserve coupon: %w", err))
+		return 0, newServiceError(ErrDB, err)
 	}
+	s.remaining.set(campaignID, count)
+	return count, nil
+}
 
-	// Mark the reserved coupon as issued
-	if err := s.couponRepo.MarkCouponAsIssued(tx, couponCode); err != nil {
-		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("failed to mark coupon as issued: %w", err))
+// lookupIdempotentIssue returns the coupon previously issued for an
+// idempotency key. Reusing a key for a different campaign, or replaying a key
+// older than the idempotency TTL, is rejected.
+func (s *CouponServer) lookupIdempotentIssue(ctx context.Context, key string, campaignID int64) (string, bool, error) {
+	record, err := s.idemRepo.GetIssuedCoupon(ctx, s.postgres, key)
+	if err != nil {
+		if errors.Is(err, repository.ErrIdempotencyKeyAbsent) {
+			return "", false, nil
+		}
+		return "", false, newServiceError(ErrDB, err)
 	}
+	if record.CampaignID != campaignID {
+		return "", false, newServiceError(ErrInvalidArgument,
+			fmt.Errorf("idempotency key was already used for campaign %d", record.CampaignID))
+	}
+	if s.idempotencyTTL > 0 && s.clock.Now().Sub(record.CreatedAt) > s.idempotencyTTL {
+		return "", false, newServiceError(ErrFailedPrecondition, fmt.Errorf("idempotency key expired"))
+	}
+	return record.CouponCode, true, nil
+}
+
+// GetRemaining returns the number of coupons left in a campaign, served from
+// a short-lived cache so frequent polling doesn't translate into DB load
+func (s *CouponServer) GetRemaining(
+	ctx context.Context,
+	req *connect.Request[couponv1.GetRemainingRequest],
+) (*connect.Response[couponv1.GetRemainingResponse], error) {
+	count, ok := s.remaining.get(req.Msg.CampaignId)
+	metrics.RecordGetRemaining(ok)
 
-	// Commit DB transaction - this guarantees consistency
-	if err := tx.Commit(); err != nil {
-		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("failed to commit transaction: %w", err))
+	if !ok {
+		err := s.guardDB(ctx, func() error {
+			var err error
+			count, err = s.campaignRepo.CountAvailableCoupons(ctx, s.postgres, req.Msg.CampaignId, s.clock.Now())
+			if err != nil {
+				if errors.Is(err, repository.ErrCampaignNotFound) {
+					return newServiceError(ErrCampaignNotFound, err)
+				}
+				return newServiceError(ErrDB, err)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		s.remaining.set(req.Msg.CampaignId, count)
 	}
-	result = "success"
 
-	// Create protobuf response
-	coupon := &couponv1.Coupon{
-		Code:       couponCode,
-		CampaignId: req.Msg.CampaignId,
+	res := connect.NewResponse(&couponv1.GetRemainingResponse{
+		AvailableCount: count,
+	})
+
+	return res, nil
+}
+
+// SearchCoupons finds coupons of a campaign whose code starts with a prefix
+func (s *CouponServer) SearchCoupons(
+	ctx context.Context,
+	req *connect.Request[couponv1.SearchCouponsRequest],
+) (*connect.Response[couponv1.SearchCouponsResponse], error) {
+	if req.Msg.Prefix == "" {
+		return nil, newServiceError(ErrInvalidArgument, fmt.Errorf("prefix is required"))
+	}
+	if s.hashedStorage() {
+		return nil, newServiceError(ErrFailedPrecondition, fmt.Errorf("prefix search is unavailable with hashed code storage"))
 	}
 
-	res := connect.NewResponse(&couponv1.IssueCouponResponse{
-		Coupon: coupon,
+	page, err := s.resolvePage(req.Msg.Page)
+	if err != nil {
+		return nil, err
+	}
+
+	var coupons []model.Coupon
+	err = s.guardDB(ctx, func() error {
+		var err error
+		coupons, err = s.couponRepo.SearchCouponsByPrefix(ctx, s.postgres, req.Msg.CampaignId, req.Msg.Prefix, page)
+		if err != nil {
+			return newServiceError(ErrDB, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	coupons, pageRes := trimPage(coupons, page, func(c model.Coupon) string { return c.Code })
+
+	res := connect.NewResponse(&couponv1.SearchCouponsResponse{
+		Coupons: toProtoCouponResults(coupons),
+		Page:    pageRes,
+	})
+
+	return res, nil
+}
+
+// GetCoupon gets a single coupon by its code
+func (s *CouponServer) GetCoupon(
+	ctx context.Context,
+	req *connect.Request[couponv1.GetCouponRequest],
+) (*connect.Response[couponv1.GetCouponResponse], error) {
+	campaignID, err := s.codeScope(req.Msg.CampaignId, req.Msg.Code)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkLookup(ctx, req.Peer(), req.Header(), campaignID); err != nil {
+		return nil, err
+	}
+	var coupon *model.Coupon
+	err = s.guardDB(ctx, func() error {
+		var err error
+		coupon, err = s.couponRepo.GetCoupon(ctx, s.postgres, campaignID, s.storedCode(req.Msg.Code))
+		if err != nil {
+			if errors.Is(err, repository.ErrCouponNotFound) {
+				s.lookupGuard.failed(campaignID)
+				return newServiceError(ErrCouponNotFound, err)
+			}
+			return newServiceError(ErrDB, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	// The stored code may be a hash; answer with the code asked for
+	coupon.Code = req.Msg.Code
+
+	res := connect.NewResponse(&couponv1.GetCouponResponse{
+		Coupon: toProtoCouponResult(coupon),
 	})
 
 	return res, nil
 }
+
+// ValidateCoupon returns a coupon with its campaign's redemption fields, so a
+// redemption check needs a single round trip
+func (s *CouponServer) ValidateCoupon(
+	ctx context.Context,
+	req *connect.Request[couponv1.ValidateCouponRequest],
+) (*connect.Response[couponv1.ValidateCouponResponse], error) {
+	campaignID, err := s.codeScope(req.Msg.CampaignId, req.Msg.Code)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkLookup(ctx, req.Peer(), req.Header(), campaignID); err != nil {
+		return nil, err
+	}
+	var coupon *model.CouponWithCampaign
+	err = s.guardDB(ctx, func() error {
+		var err error
+		coupon, err = s.couponRepo.GetCouponWithCampaign(ctx, s.postgres, campaignID, s.storedCode(req.Msg.Code))
+		if err != nil {
+			if errors.Is(err, repository.ErrCouponNotFound) {
+				s.lookupGuard.failed(campaignID)
+				return newServiceError(ErrCouponNotFound, err)
+			}
+			return newServiceError(ErrDB, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	// The stored code may be a hash; answer with the code asked for
+	coupon.Code = req.Msg.Code
+
+	now := s.clock.Now()
+	started := !now.Before(coupon.StartDate)
+	expired := coupon.ExpiresAt != nil && !now.Before(*coupon.ExpiresAt)
+
+	res := connect.NewResponse(&couponv1.ValidateCouponResponse{
+		Coupon: toProtoCouponResult(&coupon.Coupon),
+		Campaign: &couponv1.CouponCampaign{
+			Id:            coupon.CampaignID,
+			DiscountValue: coupon.DiscountValue,
+			StartDate:     timestamppb.New(coupon.StartDate),
+			Started:       started,
+		},
+		Redeemable: coupon.Status == model.CouponStatusIssued && !expired && started,
+	})
+
+	return res, nil
+}
+
+// ListCampaigns lists campaigns ordered by ID
+func (s *CouponServer) ListCampaigns(
+	ctx context.Context,
+	req *connect.Request[couponv1.ListCampaignsRequest],
+) (*connect.Response[couponv1.ListCampaignsResponse], error) {
+	if req.Msg.IncludeDeleted && !interceptor.IsAdmin(ctx) {
+		return nil, newServiceError(ErrPermissionDenied, fmt.Errorf("include_deleted requires the admin API key"))
+	}
+
+	page, err := s.resolvePage(req.Msg.Page)
+	if err != nil {
+		return nil, err
+	}
+
+	var campaigns []model.Campaign
+	err = s.guardDB(ctx, func() error {
+		var err error
+		campaigns, err = s.campaignRepo.ListCampaigns(ctx, s.postgres, page, req.Msg.IncludeDeleted)
+		if err != nil {
+			if errors.Is(err, repository.ErrInvalidPageCursor) {
+				return newServiceError(ErrInvalidArgument, fmt.Errorf("invalid page token"))
+			}
+			return newServiceError(ErrDB, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	campaigns, pageRes := trimPage(campaigns, page, func(c model.Campaign) string { return strconv.FormatInt(c.ID, 10) })
+
+	protoCampaigns := make([]*couponv1.Campaign, 0, len(campaigns))
+	for i := range campaigns {
+		protoCampaigns = append(protoCampaigns, toProtoCampaign(&campaigns[i], nil))
+	}
+
+	res := connect.NewResponse(&couponv1.ListCampaignsResponse{
+		Campaigns: protoCampaigns,
+		Page:      pageRes,
+	})
+
+	return res, nil
+}
+
+// ListCoupons lists the coupons of a campaign ordered by code
+func (s *CouponServer) ListCoupons(
+	ctx context.Context,
+	req *connect.Request[couponv1.ListCouponsRequest],
+) (*connect.Response[couponv1.ListCouponsResponse], error) {
+	status := model.CouponStatus(req.Msg.Status)
+	if req.Msg.CouponStatus != couponv1.CouponStatus_COUPON_STATUS_UNSPECIFIED {
+		var ok bool
+		if status, ok = fromProtoCouponStatus(req.Msg.CouponStatus); !ok {
+			return nil, newServiceError(ErrInvalidArgument, fmt.Errorf("invalid coupon_status filter %v", req.Msg.CouponStatus))
+		}
+	} else if _, ok := couponStatuses[status]; !ok && status != "" {
+		return nil, newServiceError(ErrInvalidArgument, fmt.Errorf("invalid status filter %q", req.Msg.Status))
+	}
+
+	page, err := s.resolvePage(req.Msg.Page)
+	if err != nil {
+		return nil, err
+	}
+
+	var coupons []model.Coupon
+	err = s.guardDB(ctx, func() error {
+		var err error
+		coupons, err = s.couponRepo.ListCoupons(ctx, s.postgres, req.Msg.CampaignId, status,
+			req.Msg.MetadataKey, req.Msg.MetadataValue, page)
+		if err != nil {
+			return newServiceError(ErrDB, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	coupons, pageRes := trimPage(coupons, page, func(c model.Coupon) string { return c.Code })
+
+	res := connect.NewResponse(&couponv1.ListCouponsResponse{
+		Coupons: toProtoCouponResults(coupons),
+		Page:    pageRes,
+	})
+
+	return res, nil
+}
+
+// toProtoCampaign converts a campaign to its protobuf form
+func toProtoCampaign(campaign *model.Campaign, issuedCodes []string) *couponv1.Campaign {
+	protoCampaign := &couponv1.Campaign{
+		Id:                campaign.ID,
+		AvailableCoupons:  campaign.AvailableCoupons,
+		StartDate:         timestamppb.New(campaign.StartDate),
+		IssuedCouponCodes: issuedCodes,
+		CodeFormat:        toProtoCodeFormat(campaign),
+		DiscountValue:     campaign.DiscountValue,
+		Budget:            campaign.Budget,
+		IssuedValue:       campaign.IssuedValue,
+		PerUserLimit:      campaign.PerUserLimit,
+		MaxIssueRps:       campaign.MaxIssueRPS,
+		IssuanceOrder:     toProtoIssuanceOrder(campaign),
+		IssuanceWindow:    toProtoIssuanceWindow(campaign),
+	}
+	if campaign.Slug != nil {
+		protoCampaign.Slug = *campaign.Slug
+	}
+	if campaign.ShuffleSeed != nil {
+		protoCampaign.ShuffleSeed = *campaign.ShuffleSeed
+	}
+	if campaign.DeletedAt != nil {
+		protoCampaign.DeletedAt = timestamppb.New(*campaign.DeletedAt)
+	}
+	return protoCampaign
+}
+
+// toProtoPoolStats converts per-pool counts to their protobuf form
+func toProtoPoolStats(pools []model.PoolStats) []*couponv1.PoolStats {
+	result := make([]*couponv1.PoolStats, 0, len(pools))
+	for _, pool := range pools {
+		result = append(result, &couponv1.PoolStats{
+			Pool:           pool.Pool,
+			TotalCount:     pool.Total,
+			AvailableCount: pool.Available,
+			IssuedCount:    pool.Issued,
+			RevokedCount:   pool.Revoked,
+		})
+	}
+	return result
+}
+
+// toProtoCouponResults converts coupons to their protobuf list form
+func toProtoCouponResults(coupons []model.Coupon) []*couponv1.CouponSearchResult {
+	results := make([]*couponv1.CouponSearchResult, 0, len(coupons))
+	for _, coupon := range coupons {
+		results = append(results, toProtoCouponResult(&coupon))
+	}
+	return results
+}
+
+// toProtoCouponResult converts a coupon to its protobuf form
+func toProtoCouponResult(coupon *model.Coupon) *couponv1.CouponSearchResult {
+	result := &couponv1.CouponSearchResult{
+		Code:       coupon.Code,
+		Status:     string(coupon.Status),
+		Metadata:   coupon.Metadata,
+		CampaignId: coupon.CampaignID,
+
+		CouponStatus: toProtoCouponStatus(coupon.Status),
+		Pool:         coupon.Pool,
+	}
+	// Only coupons that were handed to a user have a meaningful issued_at
+	if coupon.Status != model.CouponStatusAvailable && coupon.Status != model.CouponStatusReserved {
+		result.IssuedAt = timestamppb.New(coupon.IssuedAt)
+	}
+	if coupon.ExpiresAt != nil {
+		result.ExpiresAt = timestamppb.New(*coupon.ExpiresAt)
+	}
+	return result
+}
+
+// issueOutcomeResponse returns the IssueCoupon response reporting err as an
+// outcome, or nil when err is not a sold-out or not-started error
+func issueOutcomeResponse(err error) *couponv1.IssueCouponResponse {
+	var serviceErr *ServiceError
+	if !errors.As(err, &serviceErr) {
+		return nil
+	}
+	switch serviceErr.Code {
+	case ErrSoldOut:
+		info, _ := findDetail[*couponv1.SoldOutInfo](serviceErr)
+		return &couponv1.IssueCouponResponse{Outcome: couponv1.IssueOutcome_ISSUE_OUTCOME_SOLD_OUT, SoldOut: info}
+	case ErrNotStarted:
+		return &couponv1.IssueCouponResponse{Outcome: couponv1.IssueOutcome_ISSUE_OUTCOME_NOT_STARTED}
+	case ErrOutsideIssuanceWindow:
+		info, _ := findDetail[*couponv1.IssuanceWindowInfo](serviceErr)
+		return &couponv1.IssueCouponResponse{Outcome: couponv1.IssueOutcome_ISSUE_OUTCOME_NOT_STARTED, IssuanceWindow: info}
+	}
+	return nil
+}
+
+// newSoldOutError builds a ResourceExhausted error carrying a SoldOutInfo
+// detail so clients can render the sold-out state without parsing messages
+func newSoldOutError(campaignID int64, reason couponv1.SoldOutReason, remaining int32) *ServiceError {
+	message := "no more coupons available"
+	if reason == couponv1.SoldOutReason_SOLD_OUT_REASON_BUDGET_EXHAUSTED {
+		message = "campaign budget exhausted"
+	}
+
+	return newServiceError(ErrSoldOut, errors.New(message), &couponv1.SoldOutInfo{
+		CampaignId: campaignID,
+		Remaining:  remaining,
+		Reason:     reason,
+	})
+}
+
+// codeScope returns the campaign a lookup by code is limited to. When codes
+// are only unique per campaign and no campaign ID is given, the campaign is
+// taken from the code's routing tag; without one the lookup is rejected.
+func (s *CouponServer) codeScope(campaignID int64, code string) (int64, error) {
+	if !s.scopedCodes || campaignID != 0 {
+		return campaignID, nil
+	}
+	if tagged, ok := codetag.DecodeCampaign(code); ok {
+		return tagged, nil
+	}
+	return 0, newServiceError(ErrInvalidArgument, fmt.Errorf("campaign_id is required to look up coupons by code"))
+}
+
+// markIssuedError maps a failure to mark a reserved coupon as issued. The
+// coupon was locked by the reservation, so anything but a database error means
+// the reservation path is broken and is logged loudly.
+func markIssuedError(code string, err error) *ServiceError {
+	if transitionErr := newTransitionError(err); transitionErr != nil {
+		log.Printf("ERROR: reserved coupon %s could not be issued: %v", code, err)
+		return transitionErr
+	}
+	if errors.Is(err, repository.ErrCouponNotFound) {
+		log.Printf("ERROR: reserved coupon %s vanished before issuance", code)
+		return newServiceError(ErrCouponNotFound, err)
+	}
+	return newServiceError(ErrDB, fmt.Errorf("failed to mark coupon as issued: %w", err))
+}
+
+// newTransitionError maps a rejected coupon status change to FailedPrecondition.
+// It returns nil when err is not a *model.TransitionError.
+func newTransitionError(err error) *ServiceError {
+	var transitionErr *model.TransitionError
+	if !errors.As(err, &transitionErr) {
+		return nil
+	}
+	return newServiceError(ErrFailedPrecondition, transitionErr)
+}
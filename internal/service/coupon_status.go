@@ -0,0 +1,33 @@
+package service
+
+import (
+	couponv1 "github.com/kkkkikiki/coupon/gen/coupon/v1"
+	"github.com/kkkkikiki/coupon/internal/model"
+)
+
+// couponStatuses maps stored coupon statuses to their protobuf values
+var couponStatuses = map[model.CouponStatus]couponv1.CouponStatus{
+	model.CouponStatusAvailable: couponv1.CouponStatus_COUPON_STATUS_AVAILABLE,
+	model.CouponStatusIssued:    couponv1.CouponStatus_COUPON_STATUS_ISSUED,
+	model.CouponStatusReserved:  couponv1.CouponStatus_COUPON_STATUS_RESERVED,
+	model.CouponStatusRedeemed:  couponv1.CouponStatus_COUPON_STATUS_REDEEMED,
+	model.CouponStatusExpired:   couponv1.CouponStatus_COUPON_STATUS_EXPIRED,
+	model.CouponStatusRevoked:   couponv1.CouponStatus_COUPON_STATUS_REVOKED,
+}
+
+// toProtoCouponStatus converts a stored status; unknown values map to
+// UNSPECIFIED
+func toProtoCouponStatus(status model.CouponStatus) couponv1.CouponStatus {
+	return couponStatuses[status]
+}
+
+// fromProtoCouponStatus converts a protobuf status. ok is false for
+// UNSPECIFIED and unknown values.
+func fromProtoCouponStatus(status couponv1.CouponStatus) (model.CouponStatus, bool) {
+	for stored, proto := range couponStatuses {
+		if proto == status {
+			return stored, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,26 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/kkkkikiki/coupon/internal/model"
+	"github.com/kkkkikiki/coupon/internal/repository"
+)
+
+func TestMarkIssuedError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want ErrorCode
+	}{
+		{"already issued", &model.TransitionError{From: model.CouponStatusIssued, To: model.CouponStatusIssued}, ErrFailedPrecondition},
+		{"unknown code", repository.ErrCouponNotFound, ErrCouponNotFound},
+		{"database error", errors.New("connection reset"), ErrDB},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wantCode(t, markIssuedError("CODE", tt.err), tt.want)
+		})
+	}
+}
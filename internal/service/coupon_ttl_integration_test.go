@@ -0,0 +1,61 @@
+//go:build integration
+
+package service
+
+import (
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	couponv1 "github.com/kkkkikiki/coupon/gen/coupon/v1"
+	"github.com/kkkkikiki/coupon/internal/config"
+)
+
+func TestDefaultCouponTTLYieldsToExplicitExpiry(t *testing.T) {
+	const ttl = time.Hour
+	s, clock := newTestServer(t, func(cfg *config.Config) {
+		cfg.App.DefaultCouponTTL = int(ttl / time.Second)
+	})
+	explicit := clock.Now().Add(48 * time.Hour).Truncate(time.Second)
+	campaign := createTestCampaign(t, s, 2, func(req *couponv1.CreateCampaignRequest) {
+		req.Pools = []*couponv1.CouponPool{
+			{Name: "explicit", Count: 1, ExpiresAt: timestamppb.New(explicit)},
+			{Name: "default", Count: 1},
+		}
+	})
+
+	expiries := make(map[string]time.Time)
+	for range 2 {
+		resp, err := issueTestCoupon(s, campaign.Id, "")
+		if err != nil {
+			t.Fatalf("IssueCoupon: %v", err)
+		}
+		if resp.Coupon.ExpiresAt == nil {
+			t.Fatalf("coupon of pool %q has no expiry", resp.Coupon.Pool)
+		}
+		expiries[resp.Coupon.Pool] = resp.Coupon.ExpiresAt.AsTime()
+	}
+
+	if got := expiries["explicit"]; !got.Equal(explicit) {
+		t.Errorf("explicit expiry = %v, want the pool's %v", got, explicit)
+	}
+	if got, want := expiries["default"], clock.Now().Add(ttl); !got.Equal(want) {
+		t.Errorf("default expiry = %v, want issuance plus the TTL, %v", got, want)
+	}
+}
+
+func TestZeroDefaultCouponTTLNeverExpires(t *testing.T) {
+	s, _ := newTestServer(t, func(cfg *config.Config) {
+		cfg.App.DefaultCouponTTL = 0
+	})
+	campaign := createTestCampaign(t, s, 1, nil)
+
+	resp, err := issueTestCoupon(s, campaign.Id, "")
+	if err != nil {
+		t.Fatalf("IssueCoupon: %v", err)
+	}
+	if resp.Coupon.ExpiresAt != nil {
+		t.Errorf("coupon expires at %v, want no expiry", resp.Coupon.ExpiresAt.AsTime())
+	}
+}
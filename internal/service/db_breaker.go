@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/sony/gobreaker"
+
+	"github.com/kkkkikiki/coupon/internal/config"
+	"github.com/kkkkikiki/coupon/internal/metrics"
+)
+
+// newDBBreaker creates the circuit breaker guarding DB access.
+// Returns nil when the breaker is disabled.
+func newDBBreaker(cfg config.DatabaseConfig) *gobreaker.CircuitBreaker {
+	if cfg.BreakerFailures <= 0 {
+		return nil
+	}
+
+	threshold := uint32(cfg.BreakerFailures)
+	return gobreaker.NewCircuitBreaker(gobreaker.Settings{
+		Name:        "postgres",
+		MaxRequests: uint32(cfg.BreakerHalfOpenMax),
+		Timeout:     time.Duration(cfg.BreakerOpenTimeout) * time.Second,
+		ReadyToTrip: func(counts gobreaker.Counts) bool {
+			return counts.ConsecutiveFailures >= threshold
+		},
+		// Business outcomes (not found, sold out, ...) are not DB failures
+		IsSuccessful: func(err error) bool {
+			return err == nil || !isDBFailure(err)
+		},
+		OnStateChange: func(name string, from, to gobreaker.State) {
+			log.Printf("Circuit breaker %s: %s -> %s", name, from, to)
+			metrics.RecordDBBreakerState(int(to))
+		},
+	})
+}
+
+// isDBFailure reports whether err indicates the database is unhealthy.
+// Errors that aren't ServiceErrors come straight from the repositories.
+// Failures after the caller's context ended are the caller's, so a burst of
+// client disconnects can't open the breaker.
+func isDBFailure(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var serviceErr *ServiceError
+	if !errors.As(err, &serviceErr) {
+		return true
+	}
+	switch serviceErr.Code {
+	case ErrDB, ErrDBUnavailable:
+		return true
+	default:
+		return false
+	}
+}
+
+// guardDB runs fn through the DB circuit breaker, fast-failing with
+// ErrDBUnavailable while the breaker is open. ctx is the context fn runs
+// queries with.
+func (s *CouponServer) guardDB(ctx context.Context, fn func() error) error {
+	if s.breaker == nil {
+		return fn()
+	}
+
+	_, err := s.breaker.Execute(func() (interface{}, error) {
+		err := fn()
+		if err != nil && ctx.Err() != nil {
+			// The driver doesn't always wrap the context's error, e.g. for
+			// a statement canceled mid-query
+			return nil, &canceledError{err: err, cause: ctx.Err()}
+		}
+		return nil, err
+	})
+	if errors.Is(err, gobreaker.ErrOpenState) || errors.Is(err, gobreaker.ErrTooManyRequests) {
+		return newServiceError(ErrDBUnavailable, fmt.Errorf("database temporarily unavailable: %w", err))
+	}
+	var canceled *canceledError
+	if errors.As(err, &canceled) {
+		return canceled.err
+	}
+	return err
+}
+
+// canceledError is an error fn returned after the caller's context ended
+type canceledError struct {
+	err   error
+	cause error // The context's error
+}
+
+func (e *canceledError) Error() string {
+	return e.err.Error()
+}
+
+func (e *canceledError) Unwrap() []error {
+	return []error{e.err, e.cause}
+}
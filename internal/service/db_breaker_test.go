@@ -0,0 +1,67 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/sony/gobreaker"
+
+	"github.com/kkkkikiki/coupon/internal/config"
+)
+
+func TestIsDBFailure(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"repository error", errors.New("failed to reserve coupon: connection reset"), true},
+		{"db error", newServiceError(ErrDB, errors.New("failed to begin transaction")), true},
+		{"breaker open", newServiceError(ErrDBUnavailable, gobreaker.ErrOpenState), true},
+		{"sold out", newSoldOutError(1, 0, 0), false},
+		{"not found", newServiceError(ErrCampaignNotFound, errors.New("campaign not found")), false},
+		{"canceled", newServiceError(ErrDB, fmt.Errorf("failed to begin transaction: %w", context.Canceled)), false},
+		{"deadline exceeded", fmt.Errorf("failed to reserve coupon: %w", context.DeadlineExceeded), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isDBFailure(tt.err); got != tt.want {
+				t.Errorf("isDBFailure(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGuardDBIgnoresCanceledCallers(t *testing.T) {
+	s := &CouponServer{breaker: newDBBreaker(config.DatabaseConfig{
+		BreakerFailures:    3,
+		BreakerOpenTimeout: 60,
+		BreakerHalfOpenMax: 1,
+	})}
+
+	// The driver reports a statement canceled mid-query without wrapping
+	// the context's error
+	queryErr := newServiceError(ErrDB, errors.New("pq: canceling statement due to user request"))
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	for i := 0; i < 10; i++ {
+		err := s.guardDB(canceled, func() error { return queryErr })
+		if err != queryErr {
+			t.Fatalf("guardDB returned %v, want the query error unchanged", err)
+		}
+	}
+	if state := s.breaker.State(); state != gobreaker.StateClosed {
+		t.Fatalf("breaker %v after canceled callers, want closed", state)
+	}
+
+	ctx := context.Background()
+	for i := 0; i < 3; i++ {
+		s.guardDB(ctx, func() error { return queryErr })
+	}
+	err := s.guardDB(ctx, func() error { return nil })
+	if code := errorCodeOf(err); code != ErrDBUnavailable {
+		t.Fatalf("guardDB after 3 failures = %v (%s), want %s", err, code, ErrDBUnavailable)
+	}
+}
@@ -0,0 +1,50 @@
+//go:build integration
+
+package service
+
+import (
+	"context"
+	"testing"
+
+	"connectrpc.com/connect"
+
+	couponv1 "github.com/kkkkikiki/coupon/gen/coupon/v1"
+)
+
+func deleteTestCampaign(s *CouponServer, campaignID int64, force bool) error {
+	_, err := s.DeleteCampaign(context.Background(), connect.NewRequest(&couponv1.DeleteCampaignRequest{
+		CampaignId: campaignID,
+		Force:      force,
+	}))
+	return err
+}
+
+func TestDeleteCampaignGuard(t *testing.T) {
+	s, _ := newTestServer(t, nil)
+
+	unused := createTestCampaign(t, s, 3, nil)
+	if err := deleteTestCampaign(s, unused.Id, false); err != nil {
+		t.Errorf("deleting a campaign without handed out coupons: %v", err)
+	}
+	wantCode(t, deleteTestCampaign(s, unused.Id, false), ErrCampaignNotFound)
+
+	used := createTestCampaign(t, s, 3, nil)
+	if _, err := issueTestCoupon(s, used.Id, ""); err != nil {
+		t.Fatalf("IssueCoupon: %v", err)
+	}
+	err := deleteTestCampaign(s, used.Id, false)
+	wantCode(t, err, ErrFailedPrecondition)
+
+	// The rejected delete left the campaign issuing
+	if _, err := issueTestCoupon(s, used.Id, ""); err != nil {
+		t.Errorf("IssueCoupon after a rejected delete: %v", err)
+	}
+
+	if err := deleteTestCampaign(s, used.Id, true); err != nil {
+		t.Fatalf("forced delete: %v", err)
+	}
+	_, err = issueTestCoupon(s, used.Id, "")
+	wantCode(t, err, ErrCampaignNotFound)
+
+	wantCode(t, deleteTestCampaign(s, 1<<40, true), ErrCampaignNotFound)
+}
@@ -0,0 +1,58 @@
+//go:build integration
+
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"connectrpc.com/connect"
+
+	couponv1 "github.com/kkkkikiki/coupon/gen/coupon/v1"
+)
+
+func TestEmptyCampaignLifecycle(t *testing.T) {
+	s, _ := newTestServer(t, nil)
+	ctx := context.Background()
+
+	campaign := createTestCampaign(t, s, 0, nil)
+	if campaign.Id == 0 || campaign.AvailableCoupons != 0 {
+		t.Fatalf("created campaign %v, want an ID and no coupons", campaign)
+	}
+
+	got, err := s.GetCampaign(ctx, connect.NewRequest(&couponv1.GetCampaignRequest{
+		Campaign: &couponv1.GetCampaignRequest_CampaignId{CampaignId: campaign.Id},
+	}))
+	if err != nil {
+		t.Fatalf("GetCampaign: %v", err)
+	}
+	if got.Msg.Campaign.AvailableCoupons != 0 || len(got.Msg.Campaign.IssuedCouponCodes) != 0 {
+		t.Errorf("GetCampaign = %v, want no coupons", got.Msg.Campaign)
+	}
+
+	stats, err := s.GetCampaignStats(ctx, connect.NewRequest(&couponv1.GetCampaignStatsRequest{CampaignId: campaign.Id}))
+	if err != nil {
+		t.Fatalf("GetCampaignStats: %v", err)
+	}
+	if st := stats.Msg.Stats; st.TotalCount != 0 || st.AvailableCount != 0 || st.IssuedCount != 0 {
+		t.Errorf("stats total=%d available=%d issued=%d, want all 0", st.TotalCount, st.AvailableCount, st.IssuedCount)
+	}
+
+	_, err = issueTestCoupon(s, campaign.Id, "")
+	wantCode(t, err, ErrSoldOut)
+	var serviceErr *ServiceError
+	errors.As(err, &serviceErr)
+	info, ok := findDetail[*couponv1.SoldOutInfo](serviceErr)
+	if !ok || info.CampaignId != campaign.Id || info.Remaining != 0 {
+		t.Errorf("SoldOutInfo = %v, want campaign %d with nothing remaining", info, campaign.Id)
+	}
+
+	remaining, err := s.GetRemaining(ctx, connect.NewRequest(&couponv1.GetRemainingRequest{CampaignId: campaign.Id}))
+	if err != nil {
+		t.Fatalf("GetRemaining: %v", err)
+	}
+	if remaining.Msg.AvailableCount != 0 {
+		t.Errorf("GetRemaining = %d, want 0", remaining.Msg.AvailableCount)
+	}
+}
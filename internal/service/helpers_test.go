@@ -0,0 +1,40 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sethvargo/go-envconfig"
+
+	"github.com/kkkkikiki/coupon/internal/config"
+)
+
+// testConfig returns the default configuration, changed by configure when
+// given. Lookup throttling is off so tests may repeat lookups freely.
+func testConfig(t *testing.T, configure func(*config.Config)) *config.Config {
+	t.Helper()
+
+	var cfg config.Config
+	if err := envconfig.ProcessWith(context.Background(), &envconfig.Config{
+		Target:   &cfg,
+		Lookuper: envconfig.MapLookuper(nil),
+	}); err != nil {
+		t.Fatalf("failed to load default config: %v", err)
+	}
+	cfg.App.LookupRPS = 0
+	if configure != nil {
+		configure(&cfg)
+	}
+	return &cfg
+}
+
+// wantCode fails t unless err is a ServiceError with code
+func wantCode(t *testing.T, err error, code ErrorCode) {
+	t.Helper()
+	if err == nil {
+		t.Fatalf("got no error, want %s", code)
+	}
+	if got := errorCodeOf(err); got != code {
+		t.Fatalf("got %s (%v), want %s", got, err, code)
+	}
+}
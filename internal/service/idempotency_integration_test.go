@@ -0,0 +1,96 @@
+//go:build integration
+
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+
+	couponv1 "github.com/kkkkikiki/coupon/gen/coupon/v1"
+	"github.com/kkkkikiki/coupon/internal/config"
+)
+
+// issueWithKey issues a coupon of campaignID under an idempotency key
+func issueWithKey(s *CouponServer, campaignID int64, key string) (*couponv1.IssueCouponResponse, error) {
+	resp, err := s.IssueCoupon(context.Background(), connect.NewRequest(&couponv1.IssueCouponRequest{
+		Campaign:       &couponv1.IssueCouponRequest_CampaignId{CampaignId: campaignID},
+		IdempotencyKey: key,
+	}))
+	if err != nil {
+		return nil, err
+	}
+	return resp.Msg, nil
+}
+
+func TestIssueReplayIsMarked(t *testing.T) {
+	s, _ := newTestServer(t, nil)
+	campaign := createTestCampaign(t, s, 2, nil)
+
+	first, err := issueWithKey(s, campaign.Id, "replay-marked")
+	if err != nil {
+		t.Fatalf("first IssueCoupon: %v", err)
+	}
+	if first.Replayed {
+		t.Error("first IssueCoupon is marked replayed")
+	}
+
+	second, err := issueWithKey(s, campaign.Id, "replay-marked")
+	if err != nil {
+		t.Fatalf("replayed IssueCoupon: %v", err)
+	}
+	if !second.Replayed {
+		t.Error("replayed IssueCoupon isn't marked replayed")
+	}
+	if second.Coupon.Code != first.Coupon.Code {
+		t.Errorf("replay returned %q, want %q", second.Coupon.Code, first.Coupon.Code)
+	}
+
+	// Without a key every call issues a fresh coupon
+	fresh, err := issueTestCoupon(s, campaign.Id, "")
+	if err != nil {
+		t.Fatalf("IssueCoupon without key: %v", err)
+	}
+	if fresh.Replayed || fresh.Coupon.Code == first.Coupon.Code {
+		t.Errorf("IssueCoupon without key = %v, want a fresh coupon", fresh)
+	}
+}
+
+func TestIssueReplayExpiry(t *testing.T) {
+	s, clock := newTestServer(t, func(cfg *config.Config) {
+		cfg.App.IdempotencyKeyTTL = 60
+	})
+	campaign := createTestCampaign(t, s, 2, nil)
+	issuedAt := clock.Now()
+
+	first, err := issueWithKey(s, campaign.Id, "replay-expiry")
+	if err != nil {
+		t.Fatalf("IssueCoupon: %v", err)
+	}
+
+	// The key replays up to and including the TTL
+	clock.Set(issuedAt.Add(time.Minute))
+	replay, err := issueWithKey(s, campaign.Id, "replay-expiry")
+	if err != nil {
+		t.Fatalf("IssueCoupon at the TTL: %v", err)
+	}
+	if !replay.Replayed || replay.Coupon.Code != first.Coupon.Code {
+		t.Errorf("IssueCoupon at the TTL = %v, want a replay of %q", replay, first.Coupon.Code)
+	}
+
+	clock.Set(issuedAt.Add(time.Minute + time.Millisecond))
+	_, err = issueWithKey(s, campaign.Id, "replay-expiry")
+	wantCode(t, err, ErrFailedPrecondition)
+
+	// Pruning removes the expired key, after which it issues afresh
+	NewIdempotencyPruner(s).prune(context.Background())
+	fresh, err := issueWithKey(s, campaign.Id, "replay-expiry")
+	if err != nil {
+		t.Fatalf("IssueCoupon after pruning: %v", err)
+	}
+	if fresh.Replayed || fresh.Coupon.Code == first.Coupon.Code {
+		t.Errorf("IssueCoupon after pruning = %v, want a fresh coupon", fresh)
+	}
+}
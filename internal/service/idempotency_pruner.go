@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+const (
+	// idempotencyPruneInterval is how often expired idempotency keys are pruned
+	idempotencyPruneInterval = 10 * time.Minute
+	// idempotencyPruneBatch caps the keys deleted per statement so pruning a
+	// large backlog doesn't hold one long transaction
+	idempotencyPruneBatch = 1000
+)
+
+// IdempotencyPruner periodically deletes idempotency keys older than the
+// server's idempotency TTL
+type IdempotencyPruner struct {
+	server *CouponServer
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewIdempotencyPruner creates a pruner for server's idempotency keys
+func NewIdempotencyPruner(server *CouponServer) *IdempotencyPruner {
+	return &IdempotencyPruner{server: server}
+}
+
+// Start launches the prune loop. It does nothing when keys never expire.
+func (p *IdempotencyPruner) Start() {
+	if p.server.idempotencyTTL <= 0 {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	p.cancel = cancel
+
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+
+		ticker := time.NewTicker(idempotencyPruneInterval)
+		defer ticker.Stop()
+
+		for {
+			p.prune(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop stops the prune loop and waits for it to exit
+func (p *IdempotencyPruner) Stop() {
+	if p.cancel == nil {
+		return
+	}
+	p.cancel()
+	p.wg.Wait()
+}
+
+// prune deletes expired keys in batches until none are left
+func (p *IdempotencyPruner) prune(ctx context.Context) {
+	before := p.server.clock.Now().Add(-p.server.idempotencyTTL)
+
+	var total int64
+	for ctx.Err() == nil {
+		var deleted int64
+		err := p.server.guardDB(ctx, func() error {
+			var err error
+			deleted, err = p.server.idemRepo.DeleteExpiredKeys(ctx, p.server.postgres, before, idempotencyPruneBatch)
+			return err
+		})
+		if err != nil {
+			if ctx.Err() == nil {
+				log.Printf("Failed to prune expired idempotency keys: %v", err)
+			}
+			return
+		}
+		total += deleted
+		if deleted < idempotencyPruneBatch {
+			break
+		}
+	}
+
+	if total > 0 {
+		log.Printf("Pruned %d expired idempotency keys", total)
+	}
+}
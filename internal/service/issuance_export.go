@@ -0,0 +1,147 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/kkkkikiki/coupon/internal/model"
+	"github.com/kkkkikiki/coupon/internal/repository"
+)
+
+// exportBatchSize is the number of events fetched per keyset page while
+// streaming an export
+const exportBatchSize = 1000
+
+// Code export modes, set with APP_EXPORT_CODES
+const (
+	exportCodesHash = "hash" // code_hash only (default)
+	exportCodesOmit = "omit" // neither code nor code_hash
+	exportCodesRaw  = "raw"  // code and code_hash
+)
+
+// issuanceExportRecord is one NDJSON line of the issuance export
+type issuanceExportRecord struct {
+	Code       string    `json:"code,omitempty"`
+	CodeHash   string    `json:"code_hash,omitempty"`
+	CampaignID int64     `json:"campaign_id"`
+	Status     string    `json:"status"`
+	IssuedAt   time.Time `json:"issued_at"`
+	UserID     *string   `json:"user_id"`
+}
+
+// ExportIssuances streams issued coupons as newline-delimited JSON for
+// analytics. It pages through the coupons table by keyset, flushing after each
+// page, so memory use doesn't grow with the export size.
+//
+// Query parameters: campaign_id (optional, all campaigns when unset) and since
+// (optional RFC 3339 time, inclusive). code_hash is the hex SHA-256 of the
+// coupon code; whether it or the raw code is included depends on
+// APP_EXPORT_CODES. The server write timeout applies to each page rather
+// than to the whole export, so large exports keep going as long as the
+// client keeps reading; use since to resume a cut-off export.
+func (s *CouponServer) ExportIssuances(w http.ResponseWriter, r *http.Request) {
+	var campaignID int64
+	if v := r.URL.Query().Get("campaign_id"); v != "" {
+		id, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || id <= 0 {
+			http.Error(w, `{"status":"error","message":"invalid campaign_id"}`, http.StatusBadRequest)
+			return
+		}
+		campaignID = id
+	}
+	var since time.Time
+	if v := r.URL.Query().Get("since"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			http.Error(w, `{"status":"error","message":"invalid since"}`, http.StatusBadRequest)
+			return
+		}
+		since = t
+	}
+
+	ctx := r.Context()
+	rc := http.NewResponseController(w)
+	encoder := json.NewEncoder(w)
+	page := repository.Page{Limit: exportBatchSize}
+	started := false
+
+	for {
+		var events []model.IssuanceEvent
+		err := s.guardDB(ctx, func() error {
+			var err error
+			events, err = s.couponRepo.ListIssuanceEvents(ctx, s.postgres, campaignID, since, page)
+			return err
+		})
+		if err != nil {
+			if !started {
+				http.Error(w, `{"status":"error","message":"export failed"}`, http.StatusServiceUnavailable)
+			}
+			// Once streaming, the truncated body is the only signal left
+			if ctx.Err() == nil {
+				log.Printf("Issuance export failed: %v", err)
+			}
+			return
+		}
+
+		s.extendWriteDeadline(rc)
+		if !started {
+			w.Header().Set("Content-Type", "application/x-ndjson")
+			w.WriteHeader(http.StatusOK)
+			started = true
+		}
+		for i := range events {
+			if err := encoder.Encode(s.exportRecord(&events[i])); err != nil {
+				return
+			}
+		}
+		if err := rc.Flush(); err != nil && !errors.Is(err, http.ErrNotSupported) {
+			return
+		}
+
+		if len(events) < page.Limit {
+			return
+		}
+		page.After = repository.IssuanceCursor(&events[len(events)-1])
+	}
+}
+
+// extendWriteDeadline gives the next write of a streaming response a full
+// write timeout. http.Server's WriteTimeout covers the whole response, which
+// would cut off a long stream that is still making progress; unary handlers
+// never call this and keep the configured timeout.
+func (s *CouponServer) extendWriteDeadline(rc *http.ResponseController) {
+	if s.writeTimeout <= 0 {
+		return
+	}
+	err := rc.SetWriteDeadline(time.Now().Add(s.writeTimeout))
+	if err != nil && !errors.Is(err, http.ErrNotSupported) {
+		log.Printf("Failed to extend write deadline: %v", err)
+	}
+}
+
+// exportRecord converts an event, hashing or dropping its code per the
+// configured export mode
+func (s *CouponServer) exportRecord(event *model.IssuanceEvent) issuanceExportRecord {
+	record := issuanceExportRecord{
+		CampaignID: event.CampaignID,
+		Status:     string(event.Status),
+		IssuedAt:   event.IssuedAt,
+		UserID:     event.UserID,
+	}
+	switch s.exportCodes {
+	case exportCodesOmit:
+	case exportCodesRaw:
+		record.Code = event.Code
+		fallthrough
+	default:
+		sum := sha256.Sum256([]byte(event.Code))
+		record.CodeHash = hex.EncodeToString(sum[:])
+	}
+	return record
+}
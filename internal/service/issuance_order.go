@@ -0,0 +1,59 @@
+package service
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+
+	couponv1 "github.com/kkkkikiki/coupon/gen/coupon/v1"
+	"github.com/kkkkikiki/coupon/internal/model"
+)
+
+// reservationOrders maps each issuance order to the reservation order stored
+// for it. Shuffled campaigns reserve by created_at behind their sort keys.
+var reservationOrders = map[couponv1.IssuanceOrder]model.ReservationOrder{
+	couponv1.IssuanceOrder_ISSUANCE_ORDER_UNSPECIFIED: model.ReservationOrderCreatedAt,
+	couponv1.IssuanceOrder_ISSUANCE_ORDER_CREATED:     model.ReservationOrderCreatedAt,
+	couponv1.IssuanceOrder_ISSUANCE_ORDER_SHUFFLED:    model.ReservationOrderCreatedAt,
+	couponv1.IssuanceOrder_ISSUANCE_ORDER_EXPIRES_AT:  model.ReservationOrderExpiresAt,
+	couponv1.IssuanceOrder_ISSUANCE_ORDER_PRIORITY:    model.ReservationOrderPriority,
+}
+
+// resolveShuffleSeed returns the shuffle seed stored for a new campaign: nil
+// for unshuffled orders, the requested seed for a shuffled campaign, or a
+// random one when the request leaves it 0
+func resolveShuffleSeed(order couponv1.IssuanceOrder, seed int64) (*int64, error) {
+	switch order {
+	case couponv1.IssuanceOrder_ISSUANCE_ORDER_SHUFFLED:
+		for seed == 0 {
+			var b [8]byte
+			if _, err := rand.Read(b[:]); err != nil {
+				return nil, fmt.Errorf("failed to generate seed: %w", err)
+			}
+			seed = int64(binary.BigEndian.Uint64(b[:]))
+		}
+		return &seed, nil
+	default:
+		if _, ok := reservationOrders[order]; !ok {
+			return nil, fmt.Errorf("unknown issuance_order %v", order)
+		}
+		if seed != 0 {
+			return nil, fmt.Errorf("requires issuance_order SHUFFLED")
+		}
+		return nil, nil
+	}
+}
+
+// toProtoIssuanceOrder returns the issuance order of a campaign
+func toProtoIssuanceOrder(campaign *model.Campaign) couponv1.IssuanceOrder {
+	switch {
+	case campaign.ShuffleSeed != nil:
+		return couponv1.IssuanceOrder_ISSUANCE_ORDER_SHUFFLED
+	case campaign.ReservationOrder == model.ReservationOrderExpiresAt:
+		return couponv1.IssuanceOrder_ISSUANCE_ORDER_EXPIRES_AT
+	case campaign.ReservationOrder == model.ReservationOrderPriority:
+		return couponv1.IssuanceOrder_ISSUANCE_ORDER_PRIORITY
+	default:
+		return couponv1.IssuanceOrder_ISSUANCE_ORDER_CREATED
+	}
+}
@@ -0,0 +1,74 @@
+//go:build integration
+
+package service
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+
+	couponv1 "github.com/kkkkikiki/coupon/gen/coupon/v1"
+	"github.com/kkkkikiki/coupon/internal/repository"
+)
+
+func TestIssuedCodesOrderIsStableOnTies(t *testing.T) {
+	s, clock := newTestServer(t, nil)
+	ctx := context.Background()
+
+	const n = 50
+	campaign := createTestCampaign(t, s, n, nil)
+	for range n {
+		if _, err := issueTestCoupon(s, campaign.Id, ""); err != nil {
+			t.Fatalf("IssueCoupon: %v", err)
+		}
+	}
+	// Every coupon issued in the same instant, as under load
+	issuedAt := clock.Now()
+	if _, err := testDB.Exec(`UPDATE coupons SET issued_at = $1 WHERE campaign_id = $2`, issuedAt, campaign.Id); err != nil {
+		t.Fatalf("failed to tie issued_at: %v", err)
+	}
+
+	getCodes := func() []string {
+		t.Helper()
+		resp, err := s.GetCampaign(ctx, connect.NewRequest(&couponv1.GetCampaignRequest{
+			Campaign: &couponv1.GetCampaignRequest_CampaignId{CampaignId: campaign.Id},
+		}))
+		if err != nil {
+			t.Fatalf("GetCampaign: %v", err)
+		}
+		return resp.Msg.Campaign.IssuedCouponCodes
+	}
+	listing := getCodes()
+	if len(listing) != n {
+		t.Fatalf("GetCampaign listed %d codes, want %d", len(listing), n)
+	}
+	for range 3 {
+		if again := getCodes(); !slices.Equal(again, listing) {
+			t.Fatalf("GetCampaign order changed between calls:\n%v\n%v", listing, again)
+		}
+	}
+
+	// Keyset pages split inside the tie must neither skip nor repeat codes,
+	// and follow the listing's order
+	var paged []string
+	page := repository.Page{Limit: 7}
+	for {
+		events, err := s.couponRepo.ListIssuanceEvents(ctx, testDB, campaign.Id, time.Time{}, page)
+		if err != nil {
+			t.Fatalf("ListIssuanceEvents: %v", err)
+		}
+		for _, event := range events {
+			paged = append(paged, event.Code)
+		}
+		if len(events) < page.Limit {
+			break
+		}
+		page.After = repository.IssuanceCursor(&events[len(events)-1])
+	}
+	if !slices.Equal(paged, listing) {
+		t.Errorf("paged issuance events differ from the listing:\n%v\n%v", paged, listing)
+	}
+}
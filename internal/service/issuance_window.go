@@ -0,0 +1,169 @@
+package service
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	couponv1 "github.com/kkkkikiki/coupon/gen/coupon/v1"
+	"github.com/kkkkikiki/coupon/internal/model"
+)
+
+// locations caches loaded time zones by name
+var locations sync.Map
+
+// loadLocation returns the named time zone, loading it once
+func loadLocation(name string) (*time.Location, error) {
+	if loc, ok := locations.Load(name); ok {
+		return loc.(*time.Location), nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, err
+	}
+	locations.Store(name, loc)
+	return loc, nil
+}
+
+// applyIssuanceWindow validates a requested issuance window and sets it on
+// campaign; nil leaves the campaign always open
+func applyIssuanceWindow(campaign *model.Campaign, window *couponv1.IssuanceWindow) error {
+	if window == nil {
+		campaign.WindowTimeZone = "UTC"
+		return nil
+	}
+
+	start, err := parseTimeOfDay(window.StartTime)
+	if err != nil {
+		return fmt.Errorf("start_time %w", err)
+	}
+	end, err := parseTimeOfDay(window.EndTime)
+	if err != nil {
+		return fmt.Errorf("end_time %w", err)
+	}
+	if start == end {
+		return fmt.Errorf("start_time and end_time must differ")
+	}
+
+	timeZone := window.TimeZone
+	if timeZone == "" {
+		timeZone = "UTC"
+	}
+	if _, err := loadLocation(timeZone); err != nil {
+		return fmt.Errorf("unknown time_zone %q", timeZone)
+	}
+
+	var days int16
+	for _, day := range window.Days {
+		if day < 1 || day > 7 {
+			return fmt.Errorf("days must be ISO weekdays from 1 (Monday) to 7 (Sunday), got %d", day)
+		}
+		days |= 1 << (day - 1)
+	}
+
+	campaign.WindowStart, campaign.WindowEnd = &start, &end
+	campaign.WindowTimeZone, campaign.WindowDays = timeZone, days
+	return nil
+}
+
+// parseTimeOfDay parses "HH:MM" into minutes after midnight
+func parseTimeOfDay(value string) (int32, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("must be a time of day as HH:MM, got %q", value)
+	}
+	return int32(t.Hour()*60 + t.Minute()), nil
+}
+
+// toProtoIssuanceWindow converts a campaign's window to its protobuf form,
+// nil when the campaign has none
+func toProtoIssuanceWindow(campaign *model.Campaign) *couponv1.IssuanceWindow {
+	if campaign.WindowStart == nil || campaign.WindowEnd == nil {
+		return nil
+	}
+	window := &couponv1.IssuanceWindow{
+		StartTime: formatTimeOfDay(*campaign.WindowStart),
+		EndTime:   formatTimeOfDay(*campaign.WindowEnd),
+		TimeZone:  campaign.WindowTimeZone,
+	}
+	for day := int32(1); day <= 7; day++ {
+		if campaign.WindowDays&(1<<(day-1)) != 0 {
+			window.Days = append(window.Days, day)
+		}
+	}
+	return window
+}
+
+// formatTimeOfDay formats minutes after midnight as "HH:MM"
+func formatTimeOfDay(minutes int32) string {
+	return fmt.Sprintf("%02d:%02d", minutes/60, minutes%60)
+}
+
+// checkIssuanceWindow rejects issuance at now outside the campaign's daily
+// window with FailedPrecondition and the next opening as an
+// IssuanceWindowInfo detail
+func checkIssuanceWindow(campaign *model.Campaign, now time.Time) error {
+	open, next, err := issuanceWindowOpen(campaign, now)
+	if err != nil {
+		return newServiceError(ErrInternal, err)
+	}
+	if open {
+		return nil
+	}
+
+	return newServiceError(ErrOutsideIssuanceWindow,
+		fmt.Errorf("campaign is outside its issuance window, next window starts at %s", next.Format(time.RFC3339)),
+		&couponv1.IssuanceWindowInfo{
+			CampaignId:      campaign.ID,
+			NextWindowStart: timestamppb.New(next),
+		})
+}
+
+// issuanceWindowOpen reports whether the campaign's daily window is open at
+// now and, when it isn't, when it opens next. Campaigns without a window are
+// always open. A window spanning midnight belongs to the day it opens on.
+func issuanceWindowOpen(campaign *model.Campaign, now time.Time) (bool, time.Time, error) {
+	if campaign.WindowStart == nil || campaign.WindowEnd == nil {
+		return true, time.Time{}, nil
+	}
+	loc, err := loadLocation(campaign.WindowTimeZone)
+	if err != nil {
+		return false, time.Time{}, fmt.Errorf("campaign %d window time zone: %w", campaign.ID, err)
+	}
+
+	start, end := int(*campaign.WindowStart), int(*campaign.WindowEnd)
+	local := now.In(loc)
+	// Starting yesterday covers a window that opened yesterday and spans
+	// midnight; a week ahead always reaches the next active day
+	for offset := -1; offset <= 7; offset++ {
+		day := time.Date(local.Year(), local.Month(), local.Day()+offset, 0, 0, 0, 0, loc)
+		if !windowActiveOn(campaign.WindowDays, day.Weekday()) {
+			continue
+		}
+		opens := time.Date(day.Year(), day.Month(), day.Day(), 0, start, 0, 0, loc)
+		closes := time.Date(day.Year(), day.Month(), day.Day(), 0, end, 0, 0, loc)
+		if end < start {
+			closes = time.Date(day.Year(), day.Month(), day.Day()+1, 0, end, 0, 0, loc)
+		}
+		if now.Before(opens) {
+			return false, opens, nil
+		}
+		if now.Before(closes) {
+			return true, time.Time{}, nil
+		}
+	}
+	return false, time.Time{}, fmt.Errorf("campaign %d has no active window day", campaign.ID)
+}
+
+// windowActiveOn reports whether a window with the given day mask opens on
+// weekday; an empty mask opens every day
+func windowActiveOn(days int16, weekday time.Weekday) bool {
+	if days == 0 {
+		return true
+	}
+	// Bit 0 is Monday, bit 6 Sunday
+	bit := (int(weekday) + 6) % 7
+	return days&(1<<bit) != 0
+}
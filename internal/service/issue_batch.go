@@ -0,0 +1,154 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"connectrpc.com/connect"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	couponv1 "github.com/kkkkikiki/coupon/gen/coupon/v1"
+
+	"github.com/kkkkikiki/coupon/internal/repository"
+)
+
+// IssueBatch issues up to quantity coupons of a campaign to one user in a
+// single transaction. The per-user limit, the remaining stock and the budget
+// are applied to the batch as a whole; when any of them runs out, the call
+// grants as many coupons as possible and reports the granted count.
+func (s *CouponServer) IssueBatch(
+	ctx context.Context,
+	req *connect.Request[couponv1.IssueBatchRequest],
+) (_ *connect.Response[couponv1.IssueBatchResponse], err error) {
+	done := s.stats.begin()
+	defer func() { done(err == nil) }()
+
+	if req.Msg.UserId == "" {
+		return nil, newServiceError(ErrInvalidArgument, fmt.Errorf("user_id is required"))
+	}
+	// The upper bound is enforced by the request limits interceptor
+	if req.Msg.Quantity < 1 {
+		return nil, newServiceError(ErrInvalidArgument, fmt.Errorf("quantity must be at least 1"))
+	}
+
+	if ok, retryAfter := s.issueLimiter.allow(req.Msg.CampaignId); !ok {
+		return nil, newRetryableError("campaign rate limit exceeded", retryAfter)
+	}
+
+	var codes, revealed []string
+	var expiries map[string]time.Time
+	err = s.guardDB(ctx, func() error {
+		campaign, err := s.campaignRepo.GetCampaign(ctx, s.postgres, req.Msg.CampaignId)
+		if err != nil {
+			if errors.Is(err, repository.ErrCampaignNotFound) {
+				return newServiceError(ErrCampaignNotFound, err)
+			}
+			return newServiceError(ErrDB, fmt.Errorf("failed to get campaign: %w", err))
+		}
+		// Pick up rate changes made through UpdateCampaign on any replica
+		s.issueLimiter.setRate(campaign.ID, campaign.MaxIssueRPS)
+		now := s.clock.Now()
+		if now.Before(campaign.StartDate) {
+			return newServiceError(ErrNotStarted, fmt.Errorf("campaign has not started yet"))
+		}
+		if err := checkIssuanceWindow(campaign, now); err != nil {
+			return err
+		}
+
+		tx, err := s.postgres.BeginTxx(ctx, nil)
+		if err != nil {
+			return newServiceError(ErrDB, fmt.Errorf("failed to begin transaction: %w", err))
+		}
+		defer tx.Rollback()
+
+		// One cooldown claim covers the whole batch
+		if s.userCooldown > 0 {
+			retryAfter, err := s.cooldownRepo.ClaimIssueCooldown(ctx, tx, req.Msg.UserId, now, s.userCooldown)
+			if err != nil {
+				return newServiceError(ErrDB, fmt.Errorf("failed to check user cooldown: %w", err))
+			}
+			if retryAfter > 0 {
+				return newRetryableError("issuance cooldown active", retryAfter)
+			}
+		}
+
+		want := req.Msg.Quantity
+		if campaign.PerUserLimit > 0 {
+			allowance, err := s.userAllowance(ctx, tx, campaign, req.Msg.UserId)
+			if err != nil {
+				return err
+			}
+			if allowance == 0 {
+				return newPerUserLimitError(campaign)
+			}
+			want = min(want, allowance)
+		}
+
+		reserved, err := s.couponRepo.ReserveAvailableCoupons(ctx, tx, campaign.ID, campaign.ReservationOrder, want, now)
+		if err != nil {
+			return newServiceError(ErrDB, err)
+		}
+		if len(reserved) == 0 {
+			return newSoldOutError(campaign.ID, couponv1.SoldOutReason_SOLD_OUT_REASON_NO_COUPONS, 0)
+		}
+
+		// Coupons beyond what the budget covers stay available; their row
+		// locks are released at commit
+		if campaign.Budget > 0 {
+			charged, err := s.campaignRepo.ChargeBudget(ctx, tx, campaign.ID, int32(len(reserved)))
+			if err != nil {
+				return newServiceError(ErrDB, err)
+			}
+			if charged == 0 {
+				tx.Rollback()
+				return newSoldOutError(campaign.ID, couponv1.SoldOutReason_SOLD_OUT_REASON_BUDGET_EXHAUSTED,
+					s.remainingBestEffort(ctx, campaign.ID))
+			}
+			reserved = reserved[:charged]
+		}
+
+		expiries, err = s.couponRepo.MarkCouponsAsIssued(ctx, tx, campaign.ID, reserved, req.Msg.UserId, s.clock.Now(), s.defaultCouponTTL)
+		if err != nil {
+			return markIssuedError(fmt.Sprintf("batch of %d", len(reserved)), err)
+		}
+
+		// In hashed storage the codes only exist in this response
+		revealed, err = s.revealCodes(ctx, tx, campaign, reserved)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Commit(); err != nil {
+			return newServiceError(ErrDB, fmt.Errorf("failed to commit transaction: %w", err))
+		}
+		codes = reserved
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	coupons := make([]*couponv1.Coupon, 0, len(codes))
+	for i, code := range codes {
+		coupon := &couponv1.Coupon{
+			Code:       revealed[i],
+			CampaignId: req.Msg.CampaignId,
+			Status:     couponv1.CouponStatus_COUPON_STATUS_ISSUED,
+		}
+		if expiresAt, ok := expiries[code]; ok {
+			coupon.ExpiresAt = timestamppb.New(expiresAt)
+		}
+		coupons = append(coupons, coupon)
+	}
+
+	res := connect.NewResponse(&couponv1.IssueBatchResponse{
+		Coupons:        coupons,
+		RequestedCount: req.Msg.Quantity,
+		GrantedCount:   int32(len(codes)),
+	})
+
+	return res, nil
+}
@@ -0,0 +1,72 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	couponv1 "github.com/kkkkikiki/coupon/gen/coupon/v1"
+)
+
+func TestIssueOutcomeResponse(t *testing.T) {
+	next := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+	window := &couponv1.IssuanceWindowInfo{CampaignId: 7, NextWindowStart: timestamppb.New(next)}
+
+	for _, tc := range []struct {
+		name string
+		err  error
+		want *couponv1.IssueCouponResponse
+	}{
+		{
+			name: "sold out",
+			err:  newSoldOutError(7, couponv1.SoldOutReason_SOLD_OUT_REASON_NO_COUPONS, 0),
+			want: &couponv1.IssueCouponResponse{
+				Outcome: couponv1.IssueOutcome_ISSUE_OUTCOME_SOLD_OUT,
+				SoldOut: &couponv1.SoldOutInfo{CampaignId: 7, Reason: couponv1.SoldOutReason_SOLD_OUT_REASON_NO_COUPONS},
+			},
+		},
+		{
+			name: "budget exhausted",
+			err:  newSoldOutError(7, couponv1.SoldOutReason_SOLD_OUT_REASON_BUDGET_EXHAUSTED, 3),
+			want: &couponv1.IssueCouponResponse{
+				Outcome: couponv1.IssueOutcome_ISSUE_OUTCOME_SOLD_OUT,
+				SoldOut: &couponv1.SoldOutInfo{CampaignId: 7, Remaining: 3, Reason: couponv1.SoldOutReason_SOLD_OUT_REASON_BUDGET_EXHAUSTED},
+			},
+		},
+		{
+			name: "not started",
+			err:  newServiceError(ErrNotStarted, errors.New("campaign has not started yet")),
+			want: &couponv1.IssueCouponResponse{Outcome: couponv1.IssueOutcome_ISSUE_OUTCOME_NOT_STARTED},
+		},
+		{
+			name: "outside the issuance window",
+			err:  newServiceError(ErrOutsideIssuanceWindow, errors.New("campaign is outside its issuance window"), window),
+			want: &couponv1.IssueCouponResponse{Outcome: couponv1.IssueOutcome_ISSUE_OUTCOME_NOT_STARTED, IssuanceWindow: window},
+		},
+		{
+			name: "wrapped",
+			err:  fmt.Errorf("issue: %w", newServiceError(ErrNotStarted, errors.New("campaign has not started yet"))),
+			want: &couponv1.IssueCouponResponse{Outcome: couponv1.IssueOutcome_ISSUE_OUTCOME_NOT_STARTED},
+		},
+		{name: "other service error", err: newServiceError(ErrRateLimited, errors.New("slow down"))},
+		{name: "plain error", err: errors.New("connection reset")},
+		{name: "no error"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got := issueOutcomeResponse(tc.err)
+			if tc.want == nil {
+				if got != nil {
+					t.Fatalf("issueOutcomeResponse = %v, want nil", got)
+				}
+				return
+			}
+			if !proto.Equal(got, tc.want) {
+				t.Errorf("issueOutcomeResponse = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
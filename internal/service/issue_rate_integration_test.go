@@ -0,0 +1,44 @@
+//go:build integration
+
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+
+	couponv1 "github.com/kkkkikiki/coupon/gen/coupon/v1"
+)
+
+func TestUpdateCampaignRateTakesEffect(t *testing.T) {
+	s, clock := newTestServer(t, nil)
+	campaign := createTestCampaign(t, s, 10, func(req *couponv1.CreateCampaignRequest) {
+		req.MaxIssueRps = 1
+	})
+
+	// The replica learns the campaign's rate on its first issuance, which
+	// leaves the bucket's one token for the second
+	for i := range 2 {
+		if _, err := issueTestCoupon(s, campaign.Id, ""); err != nil {
+			t.Fatalf("IssueCoupon %d: %v", i, err)
+		}
+	}
+	_, err := issueTestCoupon(s, campaign.Id, "")
+	wantCode(t, err, ErrRateLimited)
+
+	_, err = s.UpdateCampaign(context.Background(), connect.NewRequest(&couponv1.UpdateCampaignRequest{
+		CampaignId:  campaign.Id,
+		MaxIssueRps: 100,
+	}))
+	if err != nil {
+		t.Fatalf("UpdateCampaign: %v", err)
+	}
+
+	// At 1/s the next token is a second away; at 100/s it's 10ms
+	clock.Advance(10 * time.Millisecond)
+	if _, err := issueTestCoupon(s, campaign.Id, ""); err != nil {
+		t.Errorf("IssueCoupon after raising the rate: %v", err)
+	}
+}
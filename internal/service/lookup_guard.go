@@ -0,0 +1,187 @@
+package service
+
+import (
+	"context"
+	"log"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"connectrpc.com/connect"
+	"golang.org/x/time/rate"
+
+	"github.com/kkkkikiki/coupon/internal/interceptor"
+	"github.com/kkkkikiki/coupon/internal/metrics"
+)
+
+const (
+	// lookupFailureWindow is the window failed lookups are counted in
+	lookupFailureWindow = time.Minute
+	// lookupSpikeCost is the number of tokens a lookup takes while its
+	// campaign sees a failure spike, cutting every source's rate by as much
+	lookupSpikeCost = 4
+	// lookupIdleTTL is how long an idle source keeps its token bucket
+	lookupIdleTTL = 10 * time.Minute
+	// lookupPruneSize is the number of tracked sources or campaigns above
+	// which idle entries are pruned
+	lookupPruneSize = 10000
+)
+
+// lookupGuard limits coupon lookups by code (ValidateCoupon, GetCoupon,
+// GetCouponPayload) per source, so codes can't be brute-forced by spamming
+// lookups. Lookups of unknown codes are counted per campaign; while a
+// campaign's count within lookupFailureWindow exceeds the threshold, lookups
+// of that campaign cost lookupSpikeCost tokens.
+type lookupGuard struct {
+	rps       rate.Limit
+	burst     int
+	threshold int
+	clock     Clock
+
+	mu       sync.Mutex
+	sources  map[string]*sourceBucket
+	failures map[int64]*failureWindow
+}
+
+type sourceBucket struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+type failureWindow struct {
+	start time.Time
+	count int
+}
+
+// newLookupGuard creates a guard allowing rps lookups per source with the
+// given burst. A non-positive rps disables the per-source limit; a
+// non-positive threshold disables spike detection.
+func newLookupGuard(rps float64, burst, threshold int, clock Clock) *lookupGuard {
+	return &lookupGuard{
+		rps: toLimit(rps),
+		// A bucket smaller than the spike cost could never admit a lookup
+		burst:     max(burst, lookupSpikeCost),
+		threshold: threshold,
+		clock:     clock,
+		sources:   make(map[string]*sourceBucket),
+		failures:  make(map[int64]*failureWindow),
+	}
+}
+
+// allow takes the tokens for one lookup of a campaign (0 when unknown) from
+// source's bucket. When they are not available it returns false and the time
+// until they are.
+func (g *lookupGuard) allow(source string, campaignID int64) (bool, time.Duration) {
+	if g.rps == rate.Inf {
+		return true, 0
+	}
+	now := g.clock.Now()
+	cost := 1
+	if g.spiking(campaignID, now) {
+		cost = lookupSpikeCost
+	}
+
+	g.mu.Lock()
+	bucket, ok := g.sources[source]
+	if !ok {
+		if len(g.sources) >= lookupPruneSize {
+			g.pruneSources(now)
+		}
+		bucket = &sourceBucket{limiter: rate.NewLimiter(g.rps, g.burst)}
+		g.sources[source] = bucket
+	}
+	bucket.lastSeen = now
+	g.mu.Unlock()
+
+	reservation := bucket.limiter.ReserveN(now, cost)
+	if delay := reservation.DelayFrom(now); delay > 0 {
+		reservation.CancelAt(now)
+		metrics.RecordLookupThrottled()
+		return false, delay
+	}
+	return true, 0
+}
+
+// failed records a lookup of an unknown code for a campaign (0 when
+// unknown), logging when the campaign starts seeing a spike
+func (g *lookupGuard) failed(campaignID int64) {
+	metrics.RecordLookupFailure()
+	if g.threshold <= 0 {
+		return
+	}
+	now := g.clock.Now()
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	window, ok := g.failures[campaignID]
+	if !ok || now.Sub(window.start) >= lookupFailureWindow {
+		if !ok && len(g.failures) >= lookupPruneSize {
+			g.pruneFailures(now)
+		}
+		window = &failureWindow{start: now}
+		g.failures[campaignID] = window
+	}
+	window.count++
+	if window.count == g.threshold+1 {
+		log.Printf("Coupon lookup failure spike on campaign %d: more than %d unknown codes within %v, tightening lookup limits",
+			campaignID, g.threshold, lookupFailureWindow)
+	}
+}
+
+// spiking reports whether a campaign's failed lookups exceed the threshold
+// within the current window
+func (g *lookupGuard) spiking(campaignID int64, now time.Time) bool {
+	if g.threshold <= 0 {
+		return false
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	window, ok := g.failures[campaignID]
+	return ok && now.Sub(window.start) < lookupFailureWindow && window.count > g.threshold
+}
+
+// pruneSources drops buckets of sources idle for lookupIdleTTL; g.mu is held
+func (g *lookupGuard) pruneSources(now time.Time) {
+	for source, bucket := range g.sources {
+		if now.Sub(bucket.lastSeen) >= lookupIdleTTL {
+			delete(g.sources, source)
+		}
+	}
+}
+
+// pruneFailures drops expired failure windows; g.mu is held
+func (g *lookupGuard) pruneFailures(now time.Time) {
+	for campaignID, window := range g.failures {
+		if now.Sub(window.start) >= lookupFailureWindow {
+			delete(g.failures, campaignID)
+		}
+	}
+}
+
+// checkLookup applies the lookup limit to a request for a campaign's coupon.
+// Admin callers are exempt.
+func (s *CouponServer) checkLookup(ctx context.Context, peer connect.Peer, header http.Header, campaignID int64) error {
+	if interceptor.IsAdmin(ctx) {
+		return nil
+	}
+	if ok, retryAfter := s.lookupGuard.allow(s.lookupSource(peer, header), campaignID); !ok {
+		return newRetryableError("too many coupon lookups", retryAfter)
+	}
+	return nil
+}
+
+// lookupSource identifies the client of a request: the configured client IP
+// header when set by a trusted proxy, otherwise the peer's IP
+func (s *CouponServer) lookupSource(peer connect.Peer, header http.Header) string {
+	if s.clientIPHeader != "" {
+		if v := strings.TrimSpace(header.Get(s.clientIPHeader)); v != "" {
+			return v
+		}
+	}
+	if host, _, err := net.SplitHostPort(peer.Addr); err == nil {
+		return host
+	}
+	return peer.Addr
+}
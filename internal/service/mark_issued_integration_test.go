@@ -0,0 +1,33 @@
+//go:build integration
+
+package service
+
+import (
+	"context"
+	"testing"
+)
+
+func TestMarkIssuedErrors(t *testing.T) {
+	s, _ := newTestServer(t, nil)
+	ctx := context.Background()
+	campaign := createTestCampaign(t, s, 2, nil)
+
+	resp, err := issueTestCoupon(s, campaign.Id, "")
+	if err != nil {
+		t.Fatalf("IssueCoupon: %v", err)
+	}
+
+	// Marking an issued coupon again is a precondition failure
+	_, err = s.couponRepo.MarkCouponAsIssued(ctx, testDB, campaign.Id, resp.Coupon.Code, "", s.clock.Now(), 0)
+	if err == nil {
+		t.Fatal("marking an issued coupon again succeeded")
+	}
+	wantCode(t, markIssuedError(resp.Coupon.Code, err), ErrFailedPrecondition)
+
+	// An unknown code is not found
+	_, err = s.couponRepo.MarkCouponAsIssued(ctx, testDB, campaign.Id, "NO-SUCH-CODE", "", s.clock.Now(), 0)
+	if err == nil {
+		t.Fatal("marking an unknown code succeeded")
+	}
+	wantCode(t, markIssuedError("NO-SUCH-CODE", err), ErrCouponNotFound)
+}
@@ -0,0 +1,28 @@
+package service
+
+import (
+	"fmt"
+)
+
+const (
+	// maxMetadataEntries caps the number of metadata attributes per coupon
+	maxMetadataEntries = 32
+	// maxMetadataLength caps the length of each metadata key and value
+	maxMetadataLength = 256
+)
+
+// validateMetadata checks partner-supplied coupon metadata
+func validateMetadata(metadata map[string]string) error {
+	if len(metadata) > maxMetadataEntries {
+		return fmt.Errorf("metadata must have at most %d entries", maxMetadataEntries)
+	}
+	for key, value := range metadata {
+		if key == "" {
+			return fmt.Errorf("metadata keys must not be empty")
+		}
+		if len(key) > maxMetadataLength || len(value) > maxMetadataLength {
+			return fmt.Errorf("metadata keys and values must be at most %d bytes", maxMetadataLength)
+		}
+	}
+	return nil
+}
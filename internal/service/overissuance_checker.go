@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/kkkkikiki/coupon/internal/metrics"
+)
+
+const (
+	// overissuanceCheckInterval is how often a sample of campaigns is checked
+	overissuanceCheckInterval = time.Minute
+	// overissuanceSampleSize is the number of campaigns checked per round
+	overissuanceSampleSize = 10
+)
+
+// OverissuanceChecker is a tripwire for concurrency bugs in issuance: it
+// periodically compares the issued and total coupon counts of a random sample
+// of active campaigns and reports any campaign that issued more coupons than
+// it has. Every detection increments coupon_overissuance_detected_total,
+// which must always read zero.
+type OverissuanceChecker struct {
+	server *CouponServer
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewOverissuanceChecker creates a checker for server's campaigns
+func NewOverissuanceChecker(server *CouponServer) *OverissuanceChecker {
+	return &OverissuanceChecker{server: server}
+}
+
+// Start launches the check loop
+func (o *OverissuanceChecker) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	o.cancel = cancel
+
+	o.wg.Add(1)
+	go func() {
+		defer o.wg.Done()
+
+		ticker := time.NewTicker(overissuanceCheckInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				o.check(ctx)
+			}
+		}
+	}()
+}
+
+// Stop stops the check loop and waits for it to exit
+func (o *OverissuanceChecker) Stop() {
+	if o.cancel == nil {
+		return
+	}
+	o.cancel()
+	o.wg.Wait()
+}
+
+// check runs one sampled round
+func (o *OverissuanceChecker) check(ctx context.Context) {
+	err := o.server.guardDB(ctx, func() error {
+		overissued, err := o.server.campaignRepo.FindOverissued(ctx, o.server.postgres, overissuanceSampleSize, o.server.clock.Now())
+		if err != nil {
+			return err
+		}
+		for _, count := range overissued {
+			metrics.RecordOverissuance()
+			log.Printf("ERROR: OVER-ISSUANCE DETECTED: campaign %d has %d issued coupons but only %d in total",
+				count.CampaignID, count.Issued, count.Total)
+		}
+		return nil
+	})
+	if err != nil && ctx.Err() == nil {
+		log.Printf("Over-issuance check failed: %v", err)
+	}
+}
@@ -0,0 +1,46 @@
+package service
+
+import (
+	"encoding/base64"
+	"fmt"
+
+	couponv1 "github.com/kkkkikiki/coupon/gen/coupon/v1"
+	"github.com/kkkkikiki/coupon/internal/repository"
+)
+
+// defaultPageSize is the page size used when a list request doesn't set one
+const defaultPageSize = 20
+
+// resolvePage converts a PageRequest into a repository page, clamping the
+// page size to the configured maximum. The limit is one more than the page
+// size so trimPage can tell whether another page exists.
+func (s *CouponServer) resolvePage(req *couponv1.PageRequest) (repository.Page, error) {
+	pageSize := int(req.GetPageSize())
+	if pageSize <= 0 {
+		pageSize = defaultPageSize
+	}
+	if pageSize > s.maxPageSize {
+		pageSize = s.maxPageSize
+	}
+
+	after, err := base64.RawURLEncoding.DecodeString(req.GetPageToken())
+	if err != nil {
+		return repository.Page{}, newServiceError(ErrInvalidArgument, fmt.Errorf("invalid page token"))
+	}
+
+	return repository.Page{After: string(after), Limit: pageSize + 1}, nil
+}
+
+// trimPage drops the look-ahead row fetched by resolvePage and builds the
+// PageResponse, using key to derive the cursor from the last returned row
+func trimPage[T any](rows []T, page repository.Page, key func(T) string) ([]T, *couponv1.PageResponse) {
+	pageSize := page.Limit - 1
+	if len(rows) <= pageSize {
+		return rows, &couponv1.PageResponse{}
+	}
+
+	rows = rows[:pageSize]
+	return rows, &couponv1.PageResponse{
+		NextPageToken: base64.RawURLEncoding.EncodeToString([]byte(key(rows[pageSize-1]))),
+	}
+}
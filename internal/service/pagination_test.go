@@ -0,0 +1,66 @@
+package service
+
+import (
+	"encoding/base64"
+	"testing"
+
+	couponv1 "github.com/kkkkikiki/coupon/gen/coupon/v1"
+	"github.com/kkkkikiki/coupon/internal/repository"
+)
+
+func TestResolvePageClampsPageSize(t *testing.T) {
+	s := &CouponServer{maxPageSize: 100}
+	tests := []struct {
+		name     string
+		pageSize int32
+		want     int
+	}{
+		{"unset", 0, defaultPageSize},
+		{"negative", -5, defaultPageSize},
+		{"within the maximum", 50, 50},
+		{"at the maximum", 100, 100},
+		{"over the maximum", 101, 100},
+		{"absurd", 1 << 30, 100},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := s.resolvePage(&couponv1.PageRequest{PageSize: tt.pageSize})
+			if err != nil {
+				t.Fatalf("resolvePage: %v", err)
+			}
+			// The limit fetches one look-ahead row
+			if page.Limit != tt.want+1 {
+				t.Errorf("page limit = %d, want %d", page.Limit, tt.want+1)
+			}
+		})
+	}
+}
+
+func TestResolvePageToken(t *testing.T) {
+	s := &CouponServer{maxPageSize: 100}
+
+	page, err := s.resolvePage(&couponv1.PageRequest{PageToken: base64.RawURLEncoding.EncodeToString([]byte("CODE42"))})
+	if err != nil {
+		t.Fatalf("resolvePage: %v", err)
+	}
+	if page.After != "CODE42" {
+		t.Errorf("page after = %q, want CODE42", page.After)
+	}
+
+	_, err = s.resolvePage(&couponv1.PageRequest{PageToken: "not base64!"})
+	wantCode(t, err, ErrInvalidArgument)
+}
+
+func TestTrimPage(t *testing.T) {
+	key := func(s string) string { return s }
+
+	rows, resp := trimPage([]string{"a", "b", "c"}, repository.Page{Limit: 3}, key)
+	if len(rows) != 2 || resp.NextPageToken != base64.RawURLEncoding.EncodeToString([]byte("b")) {
+		t.Errorf("full page = %v, token %q; want [a b] continuing after b", rows, resp.NextPageToken)
+	}
+
+	rows, resp = trimPage([]string{"a", "b"}, repository.Page{Limit: 3}, key)
+	if len(rows) != 2 || resp.NextPageToken != "" {
+		t.Errorf("last page = %v, token %q; want [a b] and no token", rows, resp.NextPageToken)
+	}
+}
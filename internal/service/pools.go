@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"log"
+	"math"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	couponv1 "github.com/kkkkikiki/coupon/gen/coupon/v1"
+	"github.com/kkkkikiki/coupon/internal/model"
+)
+
+const (
+	// maxPools caps the number of pools of one campaign
+	maxPools = 16
+	// maxPoolNameLength caps the length of a pool name in bytes
+	maxPoolNameLength = 32
+)
+
+// poolAllocation is a number of coupons generated for one pool ("" for
+// coupons outside any pool) with the pool's priority and expiry
+type poolAllocation struct {
+	model.CouponGroup
+	count int
+}
+
+// validatePools records violations of the requested pools and returns the
+// allocations to generate, or a single unnamed allocation of
+// availableCoupons when no pools are requested. Pool expiries must be after
+// now.
+func validatePools(v *validator, pools []*couponv1.CouponPool, availableCoupons int32, now time.Time) []poolAllocation {
+	if len(pools) == 0 {
+		return []poolAllocation{{count: int(max(availableCoupons, 0))}}
+	}
+
+	v.check(len(pools) <= maxPools, "pools", "must have at most %d entries", maxPools)
+	allocations := make([]poolAllocation, 0, len(pools))
+	seen := make(map[string]bool, len(pools))
+	var total int64
+	for _, pool := range pools {
+		v.check(pool.Name != "" && len(pool.Name) <= maxPoolNameLength, "pools", "names must be 1 to %d bytes", maxPoolNameLength)
+		v.check(!seen[pool.Name], "pools", "duplicate pool %q", pool.Name)
+		v.check(pool.Count >= 0, "pools", "pool %q count must not be negative", pool.Name)
+		v.check(pool.ExpiresAt == nil || pool.ExpiresAt.AsTime().After(now), "pools", "pool %q expires_at must be in the future", pool.Name)
+		seen[pool.Name] = true
+		total += int64(max(pool.Count, 0))
+
+		group := model.CouponGroup{Pool: pool.Name, Priority: pool.Priority}
+		if pool.ExpiresAt != nil {
+			expiresAt := pool.ExpiresAt.AsTime()
+			group.ExpiresAt = &expiresAt
+		}
+		allocations = append(allocations, poolAllocation{CouponGroup: group, count: int(max(pool.Count, 0))})
+	}
+	v.check(total <= math.MaxInt32, "pools", "counts must add up to at most %d", math.MaxInt32)
+	v.check(availableCoupons == 0 || int64(availableCoupons) == total, "available_coupons", "must be 0 or the sum of the pool counts")
+	return allocations
+}
+
+// allocatedCount returns the total number of coupons of the allocations
+func allocatedCount(allocations []poolAllocation) int {
+	var total int
+	for _, allocation := range allocations {
+		total += allocation.count
+	}
+	return total
+}
+
+// poolAllocations turns per-group counts into allocations
+func poolAllocations(counts []model.CouponGroupCount) []poolAllocation {
+	allocations := make([]poolAllocation, 0, len(counts))
+	for _, count := range counts {
+		allocations = append(allocations, poolAllocation{CouponGroup: count.CouponGroup, count: int(count.Count)})
+	}
+	return allocations
+}
+
+// storeCoupons generates and stores the coupons of each allocation, using
+// consecutive coupon indexes from start
+func (s *CouponServer) storeCoupons(ctx context.Context, tx *sqlx.Tx, campaign *model.Campaign, start uint64, allocations []poolAllocation) error {
+	for _, allocation := range allocations {
+		couponCodes, err := s.generateCouponCodes(campaign, start, allocation.count)
+		if err != nil {
+			log.Printf("Coupon generation failed: %v", err)
+			return generationFailure(err)
+		}
+		if err := s.couponRepo.CreatePregeneratedCoupons(ctx, tx, campaign.ID, s.storedCodes(couponCodes), int64(start),
+			allocation.CouponGroup, campaign.CouponMetadata, campaign.ShuffleSeed, s.clock.Now()); err != nil {
+			return storeFailure(campaign, err)
+		}
+		start += uint64(allocation.count)
+	}
+	return nil
+}
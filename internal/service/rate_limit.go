@@ -0,0 +1,119 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	couponv1 "github.com/kkkkikiki/coupon/gen/coupon/v1"
+)
+
+// retryAfterHeader carries the retry hint in seconds on rate-limit errors
+const retryAfterHeader = "Retry-After"
+
+// maxIssueRPSLimit caps the per-campaign rate a campaign may configure
+const maxIssueRPSLimit = 1e6
+
+// campaignLimiter applies a token bucket per campaign to IssueCoupon. Each
+// campaign uses its own max_issue_rps when set and the server default
+// otherwise. A campaign's rate is only learned when an issuance loads the
+// campaign, so its first request on a replica is checked against the default.
+type campaignLimiter struct {
+	defaultRPS rate.Limit
+	burst      int
+	clock      Clock
+
+	mu       sync.Mutex
+	limiters map[int64]*rate.Limiter
+}
+
+// newCampaignLimiter creates a per-campaign limiter. A non-positive rps
+// leaves campaigns without their own max_issue_rps unlimited.
+func newCampaignLimiter(rps float64, burst int, clock Clock) *campaignLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &campaignLimiter{
+		defaultRPS: toLimit(rps),
+		burst:      burst,
+		clock:      clock,
+		limiters:   make(map[int64]*rate.Limiter),
+	}
+}
+
+// toLimit converts a configured rate to a limit, where 0 means unlimited
+func toLimit(rps float64) rate.Limit {
+	if rps <= 0 {
+		return rate.Inf
+	}
+	return rate.Limit(rps)
+}
+
+// limiter returns the campaign's token bucket, creating it at the default rate
+func (l *campaignLimiter) limiter(campaignID int64) *rate.Limiter {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	limiter, ok := l.limiters[campaignID]
+	if !ok {
+		limiter = rate.NewLimiter(l.defaultRPS, l.burst)
+		l.limiters[campaignID] = limiter
+	}
+	return limiter
+}
+
+// setRate applies a campaign's max_issue_rps to its live token bucket; 0
+// falls back to the default rate
+func (l *campaignLimiter) setRate(campaignID int64, rps float64) {
+	limit := l.defaultRPS
+	if rps > 0 {
+		limit = rate.Limit(rps)
+	}
+	limiter := l.limiter(campaignID)
+	if limiter.Limit() != limit {
+		// Tokens earned so far are settled at the old rate, on the same
+		// clock allow reads
+		limiter.SetLimitAt(l.clock.Now(), limit)
+	}
+}
+
+// validateMaxIssueRPS checks a campaign's max_issue_rps
+func validateMaxIssueRPS(rps float64) error {
+	if math.IsNaN(rps) || rps < 0 || rps > maxIssueRPSLimit {
+		return fmt.Errorf("must be between 0 and %g", float64(maxIssueRPSLimit))
+	}
+	return nil
+}
+
+// allow takes a token for the campaign. When none is available it returns
+// false and the time until the next token.
+func (l *campaignLimiter) allow(campaignID int64) (bool, time.Duration) {
+	limiter := l.limiter(campaignID)
+
+	now := l.clock.Now()
+	reservation := limiter.ReserveN(now, 1)
+	if delay := reservation.DelayFrom(now); delay > 0 {
+		// Give the token back; this request is rejected, not queued
+		reservation.CancelAt(now)
+		return false, delay
+	}
+	return true, 0
+}
+
+// newRetryableError builds a ResourceExhausted error for rejections that
+// clear up over time (rate limits, cooldowns). The delay is attached both as
+// a RetryInfo detail and as a Retry-After header in whole seconds. Sold-out
+// errors must not use this since they never recover.
+func newRetryableError(message string, retryAfter time.Duration) *ServiceError {
+	serviceErr := newServiceError(ErrRateLimited, fmt.Errorf("%s, retry in %s", message, retryAfter.Round(time.Millisecond)),
+		&couponv1.RetryInfo{RetryDelay: durationpb.New(retryAfter)})
+	serviceErr.Meta = http.Header{}
+	serviceErr.Meta.Set(retryAfterHeader, strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+	return serviceErr
+}
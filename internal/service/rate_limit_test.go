@@ -0,0 +1,56 @@
+package service
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCampaignLimiterRateUpdateTakesEffect(t *testing.T) {
+	clock := newTestClock(time.Now())
+	l := newCampaignLimiter(1, 1, clock)
+
+	if ok, _ := l.allow(1); !ok {
+		t.Fatal("first request was limited")
+	}
+	ok, retryAfter := l.allow(1)
+	if ok || retryAfter != time.Second {
+		t.Fatalf("second request = %v, retry in %v; want limited for 1s", ok, retryAfter)
+	}
+
+	// A raised rate refills the bucket faster from now on
+	l.setRate(1, 10)
+	clock.Advance(100 * time.Millisecond)
+	if ok, _ := l.allow(1); !ok {
+		t.Error("request 100ms after raising the rate to 10/s was limited")
+	}
+
+	// Clearing the rate falls back to the default
+	l.setRate(1, 0)
+	clock.Advance(100 * time.Millisecond)
+	if ok, _ := l.allow(1); ok {
+		t.Error("request 100ms after reverting to 1/s was allowed")
+	}
+
+	// Other campaigns keep the default rate
+	if ok, _ := l.allow(2); !ok {
+		t.Error("first request of another campaign was limited")
+	}
+}
+
+func TestCampaignLimiterUnlimitedByDefault(t *testing.T) {
+	l := newCampaignLimiter(0, 1, newTestClock(time.Now()))
+	for i := range 100 {
+		if ok, _ := l.allow(1); !ok {
+			t.Fatalf("request %d was limited without a rate", i)
+		}
+	}
+
+	// A campaign rate applies even when the default is unlimited
+	l.setRate(1, 1)
+	if ok, _ := l.allow(1); !ok {
+		t.Fatal("first request at 1/s was limited")
+	}
+	if ok, _ := l.allow(1); ok {
+		t.Error("second request at 1/s was allowed")
+	}
+}
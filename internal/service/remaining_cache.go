@@ -0,0 +1,64 @@
+package service
+
+import (
+	"sync"
+	"time"
+)
+
+// remainingCache holds recently counted available-coupon totals per campaign
+type remainingCache struct {
+	ttl   time.Duration
+	clock Clock
+
+	mu      sync.RWMutex
+	entries map[int64]remainingEntry
+}
+
+type remainingEntry struct {
+	count     int32
+	expiresAt time.Time
+}
+
+// newRemainingCache creates a cache whose entries live for ttl.
+// A zero ttl disables caching.
+func newRemainingCache(ttl time.Duration, clock Clock) *remainingCache {
+	return &remainingCache{
+		ttl:     ttl,
+		clock:   clock,
+		entries: make(map[int64]remainingEntry),
+	}
+}
+
+// get returns the cached count for a campaign if it hasn't expired
+func (c *remainingCache) get(campaignID int64) (int32, bool) {
+	if c.ttl <= 0 {
+		return 0, false
+	}
+
+	c.mu.RLock()
+	entry, ok := c.entries[campaignID]
+	c.mu.RUnlock()
+	if !ok || c.clock.Now().After(entry.expiresAt) {
+		return 0, false
+	}
+	return entry.count, true
+}
+
+// set stores a freshly counted value, dropping expired entries so the map
+// doesn't grow with every campaign ever polled
+func (c *remainingCache) set(campaignID int64, count int32) {
+	if c.ttl <= 0 {
+		return
+	}
+
+	now := c.clock.Now()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for id, entry := range c.entries {
+		if now.After(entry.expiresAt) {
+			delete(c.entries, id)
+		}
+	}
+	c.entries[campaignID] = remainingEntry{count: count, expiresAt: now.Add(c.ttl)}
+}
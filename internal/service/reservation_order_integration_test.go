@@ -0,0 +1,125 @@
+//go:build integration
+
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	couponv1 "github.com/kkkkikiki/coupon/gen/coupon/v1"
+	"github.com/kkkkikiki/coupon/internal/config"
+)
+
+// issuedPools issues n coupons of campaignID and returns their pools in
+// issuance order
+func issuedPools(t *testing.T, s *CouponServer, campaignID int64, n int) []string {
+	t.Helper()
+
+	pools := make([]string, 0, n)
+	for range n {
+		resp, err := issueTestCoupon(s, campaignID, "")
+		if err != nil {
+			t.Fatalf("IssueCoupon after %v: %v", pools, err)
+		}
+		pools = append(pools, resp.Coupon.Pool)
+	}
+	return pools
+}
+
+func TestExpiresAtOrderIssuesSoonestExpiringFirst(t *testing.T) {
+	s, clock := newTestServer(t, nil)
+	now := clock.Now()
+	campaign := createTestCampaign(t, s, 6, func(req *couponv1.CreateCampaignRequest) {
+		req.IssuanceOrder = couponv1.IssuanceOrder_ISSUANCE_ORDER_EXPIRES_AT
+		req.Pools = []*couponv1.CouponPool{
+			{Name: "never", Count: 2},
+			{Name: "late", Count: 2, ExpiresAt: timestamppb.New(now.Add(48 * time.Hour))},
+			{Name: "soon", Count: 2, ExpiresAt: timestamppb.New(now.Add(time.Hour))},
+		}
+	})
+
+	want := []string{"soon", "soon", "late", "late", "never", "never"}
+	got := issuedPools(t, s, campaign.Id, len(want))
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("pools issued %v, want %v", got, want)
+		}
+	}
+}
+
+func TestPriorityOrderIssuesHighestPriorityFirst(t *testing.T) {
+	s, _ := newTestServer(t, nil)
+	campaign := createTestCampaign(t, s, 6, func(req *couponv1.CreateCampaignRequest) {
+		req.IssuanceOrder = couponv1.IssuanceOrder_ISSUANCE_ORDER_PRIORITY
+		req.Pools = []*couponv1.CouponPool{
+			{Name: "low", Count: 2, Priority: -1},
+			{Name: "high", Count: 2, Priority: 10},
+			{Name: "default", Count: 2},
+		}
+	})
+
+	want := []string{"high", "high", "default", "default", "low", "low"}
+	got := issuedPools(t, s, campaign.Id, len(want))
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("pools issued %v, want %v", got, want)
+		}
+	}
+}
+
+func TestRemainingLeavesOutExpiredCoupons(t *testing.T) {
+	s, clock := newTestServer(t, func(cfg *config.Config) {
+		cfg.App.RemainingCacheTTL = 0
+	})
+	ctx := context.Background()
+	campaign := createTestCampaign(t, s, 3, func(req *couponv1.CreateCampaignRequest) {
+		req.Pools = []*couponv1.CouponPool{
+			{Name: "expiring", Count: 2, ExpiresAt: timestamppb.New(clock.Now().Add(time.Hour))},
+			{Name: "live", Count: 1},
+		}
+	})
+
+	remaining := func() int32 {
+		t.Helper()
+		resp, err := s.GetRemaining(ctx, connect.NewRequest(&couponv1.GetRemainingRequest{CampaignId: campaign.Id}))
+		if err != nil {
+			t.Fatalf("GetRemaining: %v", err)
+		}
+		return resp.Msg.AvailableCount
+	}
+	state := func() couponv1.CampaignState {
+		t.Helper()
+		resp, err := s.GetCampaign(ctx, connect.NewRequest(&couponv1.GetCampaignRequest{
+			Campaign: &couponv1.GetCampaignRequest_CampaignId{CampaignId: campaign.Id},
+		}))
+		if err != nil {
+			t.Fatalf("GetCampaign: %v", err)
+		}
+		return resp.Msg.State
+	}
+
+	if got := remaining(); got != 3 {
+		t.Errorf("remaining before the expiry = %d, want 3", got)
+	}
+	clock.Advance(time.Hour)
+	if got := remaining(); got != 1 {
+		t.Errorf("remaining after the expiry = %d, want 1", got)
+	}
+
+	if _, err := issueTestCoupon(s, campaign.Id, ""); err != nil {
+		t.Fatalf("IssueCoupon: %v", err)
+	}
+	// Only expired coupons are left, so the count agrees with IssueCoupon
+	if got := remaining(); got != 0 {
+		t.Errorf("remaining with only expired coupons = %d, want 0", got)
+	}
+	if got := state(); got != couponv1.CampaignState_CAMPAIGN_STATE_ENDED {
+		t.Errorf("state with only expired coupons = %v, want ENDED", got)
+	}
+	_, err := issueTestCoupon(s, campaign.Id, "")
+	wantCode(t, err, ErrSoldOut)
+}
@@ -0,0 +1,65 @@
+//go:build integration
+
+package service
+
+import (
+	"context"
+	"testing"
+
+	"connectrpc.com/connect"
+
+	couponv1 "github.com/kkkkikiki/coupon/gen/coupon/v1"
+)
+
+func TestRevokeCampaignCoupons(t *testing.T) {
+	s, _ := newTestServer(t, nil)
+	ctx := context.Background()
+
+	// More issued coupons than one revoke batch, and some left available
+	const issued = revokeBatchSize + 5
+	campaign := createTestCampaign(t, s, issued+10, nil)
+	codes := make([]string, 0, issued)
+	for range issued {
+		resp, err := issueTestCoupon(s, campaign.Id, "")
+		if err != nil {
+			t.Fatalf("IssueCoupon: %v", err)
+		}
+		codes = append(codes, resp.Coupon.Code)
+	}
+
+	revoked, err := s.RevokeCampaignCoupons(ctx, connect.NewRequest(&couponv1.RevokeCampaignCouponsRequest{CampaignId: campaign.Id}))
+	if err != nil {
+		t.Fatalf("RevokeCampaignCoupons: %v", err)
+	}
+	if revoked.Msg.RevokedCount != issued {
+		t.Errorf("revoked %d coupons, want %d", revoked.Msg.RevokedCount, issued)
+	}
+
+	stats, err := s.GetCampaignStats(ctx, connect.NewRequest(&couponv1.GetCampaignStatsRequest{CampaignId: campaign.Id}))
+	if err != nil {
+		t.Fatalf("GetCampaignStats: %v", err)
+	}
+	if st := stats.Msg.Stats; st.TotalCount != issued+10 || st.AvailableCount != 10 || st.IssuedCount != 0 || st.RevokedCount != issued {
+		t.Errorf("stats total=%d available=%d issued=%d revoked=%d, want %d/10/0/%d",
+			st.TotalCount, st.AvailableCount, st.IssuedCount, st.RevokedCount, issued+10, issued)
+	}
+
+	for _, code := range []string{codes[0], codes[issued-1]} {
+		resp, err := s.ValidateCoupon(ctx, connect.NewRequest(&couponv1.ValidateCouponRequest{Code: code, CampaignId: campaign.Id}))
+		if err != nil {
+			t.Fatalf("ValidateCoupon(%s): %v", code, err)
+		}
+		if resp.Msg.Redeemable || resp.Msg.Coupon.GetCouponStatus() != couponv1.CouponStatus_COUPON_STATUS_REVOKED {
+			t.Errorf("ValidateCoupon(%s) = %v, want a revoked, unredeemable coupon", code, resp.Msg)
+		}
+	}
+
+	// A second revoke finds nothing left to revoke
+	revoked, err = s.RevokeCampaignCoupons(ctx, connect.NewRequest(&couponv1.RevokeCampaignCouponsRequest{CampaignId: campaign.Id}))
+	if err != nil {
+		t.Fatalf("second RevokeCampaignCoupons: %v", err)
+	}
+	if revoked.Msg.RevokedCount != 0 {
+		t.Errorf("second revoke revoked %d coupons, want 0", revoked.Msg.RevokedCount)
+	}
+}
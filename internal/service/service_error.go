@@ -0,0 +1,125 @@
+package service
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"slices"
+
+	"connectrpc.com/connect"
+	"google.golang.org/protobuf/proto"
+
+	couponv1 "github.com/kkkkikiki/coupon/gen/coupon/v1"
+)
+
+// ErrorCode is the stable category of a ServiceError. Clients receive it as
+// the reason of an ErrorInfo detail, so a code must never change meaning.
+type ErrorCode string
+
+// Error codes returned by the service
+const (
+	ErrInvalidArgument       ErrorCode = "ERR_INVALID_ARGUMENT"        // Malformed or out-of-range request field
+	ErrPermissionDenied      ErrorCode = "ERR_PERMISSION_DENIED"       // Option restricted to the admin API key
+	ErrCampaignNotFound      ErrorCode = "ERR_CAMPAIGN_NOT_FOUND"      // No live campaign with the given ID or slug
+	ErrCouponNotFound        ErrorCode = "ERR_COUPON_NOT_FOUND"        // No coupon with the given code
+	ErrAlreadyExists         ErrorCode = "ERR_ALREADY_EXISTS"          // Slug or request ID already in use
+	ErrSoldOut               ErrorCode = "ERR_SOLD_OUT"                // No coupons or budget left
+	ErrNotStarted            ErrorCode = "ERR_NOT_STARTED"             // Before the campaign's start date
+	ErrOutsideIssuanceWindow ErrorCode = "ERR_OUTSIDE_ISSUANCE_WINDOW" // Outside the campaign's daily window
+	ErrPerUserLimit          ErrorCode = "ERR_PER_USER_LIMIT"          // User holds the campaign's maximum
+	ErrRateLimited           ErrorCode = "ERR_RATE_LIMITED"            // Rate limit or cooldown; retry later
+	ErrFailedPrecondition    ErrorCode = "ERR_FAILED_PRECONDITION"     // Operation not allowed in the current state
+	ErrAborted               ErrorCode = "ERR_ABORTED"                 // Lost a race with a concurrent request
+	ErrCanceled              ErrorCode = "ERR_CANCELED"                // Client went away mid-stream
+	ErrDB                    ErrorCode = "ERR_DB"                      // Database query failed
+	ErrDBUnavailable         ErrorCode = "ERR_DB_UNAVAILABLE"          // Circuit breaker open
+	ErrInternal              ErrorCode = "ERR_INTERNAL"                // Any other server-side failure
+)
+
+// connectCodes maps each error code to the connect code it is returned with
+var connectCodes = map[ErrorCode]connect.Code{
+	ErrInvalidArgument:       connect.CodeInvalidArgument,
+	ErrPermissionDenied:      connect.CodePermissionDenied,
+	ErrCampaignNotFound:      connect.CodeNotFound,
+	ErrCouponNotFound:        connect.CodeNotFound,
+	ErrAlreadyExists:         connect.CodeAlreadyExists,
+	ErrSoldOut:               connect.CodeResourceExhausted,
+	ErrNotStarted:            connect.CodeFailedPrecondition,
+	ErrOutsideIssuanceWindow: connect.CodeFailedPrecondition,
+	ErrPerUserLimit:          connect.CodeResourceExhausted,
+	ErrRateLimited:           connect.CodeResourceExhausted,
+	ErrFailedPrecondition:    connect.CodeFailedPrecondition,
+	ErrAborted:               connect.CodeAborted,
+	ErrCanceled:              connect.CodeCanceled,
+	ErrDB:                    connect.CodeInternal,
+	ErrDBUnavailable:         connect.CodeUnavailable,
+	ErrInternal:              connect.CodeInternal,
+}
+
+// ServiceError is the error returned by the service's handlers. It carries a
+// stable code instead of a connect code; ConnectError translates it, and the
+// error interceptor applies the translation to every handler's result.
+type ServiceError struct {
+	Code    ErrorCode
+	Err     error           // Cause; its message is the error message
+	Details []proto.Message // Error details such as SoldOutInfo or BadRequest
+	Meta    http.Header     // Response headers such as Retry-After
+}
+
+// newServiceError returns a ServiceError of code caused by err
+func newServiceError(code ErrorCode, err error, details ...proto.Message) *ServiceError {
+	return &ServiceError{Code: code, Err: err, Details: details}
+}
+
+func (e *ServiceError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ServiceError) Unwrap() error {
+	return e.Err
+}
+
+// findDetail returns the first detail of e of type T
+func findDetail[T proto.Message](e *ServiceError) (T, bool) {
+	for _, d := range e.Details {
+		if t, ok := d.(T); ok {
+			return t, true
+		}
+	}
+	var zero T
+	return zero, false
+}
+
+// ConnectError translates e to the connect error returned to clients: the
+// code's connect code, e's details plus an ErrorInfo with the code, and e's
+// headers
+func (e *ServiceError) ConnectError() *connect.Error {
+	code, ok := connectCodes[e.Code]
+	if !ok {
+		code = connect.CodeInternal
+	}
+
+	connectErr := connect.NewError(code, e.Err)
+	for _, msg := range append(slices.Clip(e.Details), &couponv1.ErrorInfo{Reason: string(e.Code)}) {
+		detail, err := connect.NewErrorDetail(msg)
+		if err != nil {
+			log.Printf("Failed to encode %T error detail: %v", msg, err)
+			continue
+		}
+		connectErr.AddDetail(detail)
+	}
+	for key, values := range e.Meta {
+		connectErr.Meta()[key] = values
+	}
+	return connectErr
+}
+
+// errorCodeOf returns the code of the ServiceError in err's chain, or
+// ErrInternal when there is none
+func errorCodeOf(err error) ErrorCode {
+	var serviceErr *ServiceError
+	if errors.As(err, &serviceErr) {
+		return serviceErr.Code
+	}
+	return ErrInternal
+}
@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"connectrpc.com/connect"
+
+	couponv1 "github.com/kkkkikiki/coupon/gen/coupon/v1"
+	"github.com/kkkkikiki/coupon/gen/coupon/v1/couponv1connect"
+	"github.com/kkkkikiki/coupon/internal/interceptor"
+)
+
+// soldOutHandler answers every IssueCoupon call with a sold-out error
+type soldOutHandler struct {
+	couponv1connect.UnimplementedCouponServiceHandler
+}
+
+func (soldOutHandler) IssueCoupon(ctx context.Context, req *connect.Request[couponv1.IssueCouponRequest]) (*connect.Response[couponv1.IssueCouponResponse], error) {
+	return nil, newSoldOutError(req.Msg.GetCampaignId(), couponv1.SoldOutReason_SOLD_OUT_REASON_NO_COUPONS, 0)
+}
+
+func TestSoldOutDetailReachesClient(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.Handle(couponv1connect.NewCouponServiceHandler(soldOutHandler{},
+		connect.WithInterceptors(interceptor.NewErrorInterceptor())))
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	for _, protocol := range []struct {
+		name string
+		opts []connect.ClientOption
+	}{
+		{"connect", nil},
+		{"grpc-web", []connect.ClientOption{connect.WithGRPCWeb()}},
+	} {
+		t.Run(protocol.name, func(t *testing.T) {
+			client := couponv1connect.NewCouponServiceClient(server.Client(), server.URL, protocol.opts...)
+			_, err := client.IssueCoupon(context.Background(), connect.NewRequest(&couponv1.IssueCouponRequest{
+				Campaign: &couponv1.IssueCouponRequest_CampaignId{CampaignId: 42},
+			}))
+
+			var connectErr *connect.Error
+			if !errors.As(err, &connectErr) {
+				t.Fatalf("IssueCoupon error = %v, want a connect error", err)
+			}
+			if connectErr.Code() != connect.CodeResourceExhausted {
+				t.Errorf("code = %v, want %v", connectErr.Code(), connect.CodeResourceExhausted)
+			}
+
+			var info *couponv1.SoldOutInfo
+			var reason string
+			for _, detail := range connectErr.Details() {
+				value, err := detail.Value()
+				if err != nil {
+					t.Fatalf("failed to decode %s detail: %v", detail.Type(), err)
+				}
+				switch value := value.(type) {
+				case *couponv1.SoldOutInfo:
+					info = value
+				case *couponv1.ErrorInfo:
+					reason = value.Reason
+				}
+			}
+			if info == nil {
+				t.Fatal("error has no SoldOutInfo detail")
+			}
+			if info.CampaignId != 42 || info.Remaining != 0 || info.Reason != couponv1.SoldOutReason_SOLD_OUT_REASON_NO_COUPONS {
+				t.Errorf("SoldOutInfo = %v, want campaign 42 with nothing remaining", info)
+			}
+			if reason != string(ErrSoldOut) {
+				t.Errorf("ErrorInfo reason = %q, want %q", reason, ErrSoldOut)
+			}
+		})
+	}
+}
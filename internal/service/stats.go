@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/kkkkikiki/coupon/internal/model"
+)
+
+// Stats is a snapshot of the server's in-process issuance counters. Counts
+// are per IssueCoupon or IssueBatch call and cover the server's lifetime.
+type Stats struct {
+	IssuesAttempted int64 // Calls received, including rejected ones
+	IssuesSucceeded int64 // Calls that returned coupons
+	IssuesFailed    int64 // Calls that returned an error
+	IssuesInFlight  int64 // Calls currently being handled
+}
+
+// issueStats backs Stats with lock-free counters
+type issueStats struct {
+	attempted atomic.Int64
+	succeeded atomic.Int64
+	failed    atomic.Int64
+	inFlight  atomic.Int64
+}
+
+// begin records the start of an issuance call. The returned function records
+// its outcome and must be called exactly once.
+func (st *issueStats) begin() func(ok bool) {
+	st.attempted.Add(1)
+	st.inFlight.Add(1)
+	return func(ok bool) {
+		st.inFlight.Add(-1)
+		if ok {
+			st.succeeded.Add(1)
+		} else {
+			st.failed.Add(1)
+		}
+	}
+}
+
+// Stats returns a snapshot of the issuance counters, independent of
+// Prometheus. The counters are read one by one, so a snapshot taken under
+// load may be off by the calls finishing meanwhile.
+func (s *CouponServer) Stats() Stats {
+	return Stats{
+		IssuesAttempted: s.stats.attempted.Load(),
+		IssuesSucceeded: s.stats.succeeded.Load(),
+		IssuesFailed:    s.stats.failed.Load(),
+		IssuesInFlight:  s.stats.inFlight.Load(),
+	}
+}
+
+// ActiveRemaining returns the available coupon count of up to limit most
+// recently started live campaigns, keyed by campaign ID. It backs the
+// per-campaign remaining metric computed at scrape time.
+func (s *CouponServer) ActiveRemaining(ctx context.Context, limit int) (map[int64]int64, error) {
+	var counts []model.RemainingCount
+	err := s.guardDB(ctx, func() error {
+		var err error
+		counts, err = s.campaignRepo.ActiveRemainingCounts(ctx, s.postgres, limit, s.clock.Now())
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	remaining := make(map[int64]int64, len(counts))
+	for _, count := range counts {
+		remaining[count.CampaignID] = count.Available
+	}
+	return remaining, nil
+}
@@ -0,0 +1,40 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/kkkkikiki/coupon/internal/model"
+)
+
+// userAllowance returns how many more coupons of the campaign the user may
+// receive. It locks the (campaign, user) pair until the transaction ends so
+// concurrent requests of the same user see each other's issuances.
+func (s *CouponServer) userAllowance(ctx context.Context, tx *sqlx.Tx, campaign *model.Campaign, userID string) (int32, error) {
+	if userID == "" {
+		return 0, newServiceError(ErrInvalidArgument, fmt.Errorf("user_id is required for campaigns with a per-user limit"))
+	}
+
+	if err := s.couponRepo.LockUserIssuance(ctx, tx, campaign.ID, userID); err != nil {
+		return 0, newServiceError(ErrDB, err)
+	}
+
+	issued, err := s.couponRepo.CountUserCoupons(ctx, tx, campaign.ID, userID)
+	if err != nil {
+		return 0, newServiceError(ErrDB, err)
+	}
+
+	if issued >= campaign.PerUserLimit {
+		return 0, nil
+	}
+	return campaign.PerUserLimit - issued, nil
+}
+
+// newPerUserLimitError reports that the user already holds the maximum number
+// of coupons of the campaign
+func newPerUserLimitError(campaign *model.Campaign) *ServiceError {
+	return newServiceError(ErrPerUserLimit,
+		fmt.Errorf("per-user limit of %d coupons reached for campaign %d", campaign.PerUserLimit, campaign.ID))
+}
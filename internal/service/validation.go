@@ -0,0 +1,55 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	couponv1 "github.com/kkkkikiki/coupon/gen/coupon/v1"
+)
+
+// validator collects field violations of a request so they can be reported
+// together instead of one per round trip
+type validator struct {
+	violations []*couponv1.BadRequest_FieldViolation
+}
+
+// check records a violation of field unless ok
+func (v *validator) check(ok bool, field, format string, args ...any) {
+	if !ok {
+		v.violations = append(v.violations, &couponv1.BadRequest_FieldViolation{
+			Field:       field,
+			Description: fmt.Sprintf(format, args...),
+		})
+	}
+}
+
+// add records err, if any, as a violation of field
+func (v *validator) add(field string, err error) {
+	if err != nil {
+		v.check(false, field, "%s", err.Error())
+	}
+}
+
+// merge records the violations of other under prefix, e.g. "campaigns[2]."
+func (v *validator) merge(prefix string, other *validator) {
+	for _, violation := range other.violations {
+		v.check(false, prefix+violation.Field, "%s", violation.Description)
+	}
+}
+
+// err returns an InvalidArgument error listing all violations with a
+// BadRequest detail, or nil when there are none
+func (v *validator) err() *ServiceError {
+	if len(v.violations) == 0 {
+		return nil
+	}
+
+	messages := make([]string, 0, len(v.violations))
+	for _, violation := range v.violations {
+		messages = append(messages, violation.Field+": "+violation.Description)
+	}
+	return newServiceError(ErrInvalidArgument, errors.New(strings.Join(messages, "; ")), &couponv1.BadRequest{
+		FieldViolations: v.violations,
+	})
+}
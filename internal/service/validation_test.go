@@ -0,0 +1,71 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	couponv1 "github.com/kkkkikiki/coupon/gen/coupon/v1"
+)
+
+// violatedFields returns the fields of the BadRequest detail of err, in order
+func violatedFields(t *testing.T, err *ServiceError) []string {
+	t.Helper()
+	badRequest, ok := findDetail[*couponv1.BadRequest](err)
+	if !ok {
+		t.Fatalf("error %v has no BadRequest detail", err)
+	}
+	fields := make([]string, 0, len(badRequest.FieldViolations))
+	for _, violation := range badRequest.FieldViolations {
+		fields = append(fields, violation.Field)
+	}
+	return fields
+}
+
+func TestValidatorAccumulates(t *testing.T) {
+	var v validator
+	if err := v.err(); err != nil {
+		t.Fatalf("empty validator returned %v", err)
+	}
+
+	v.check(true, "ok", "never recorded")
+	v.check(false, "count", "must be positive, got %d", -1)
+	v.add("name", nil)
+	v.add("slug", errors.New("is taken"))
+	var nested validator
+	nested.check(false, "start_date", "is required")
+	v.merge("campaigns[2].", &nested)
+
+	err := v.err()
+	wantCode(t, err, ErrInvalidArgument)
+	if want := "count: must be positive, got -1; slug: is taken; campaigns[2].start_date: is required"; err.Error() != want {
+		t.Errorf("message = %q, want %q", err.Error(), want)
+	}
+	fields := violatedFields(t, err)
+	if len(fields) != 3 || fields[0] != "count" || fields[1] != "slug" || fields[2] != "campaigns[2].start_date" {
+		t.Errorf("violated fields = %v, want [count slug campaigns[2].start_date]", fields)
+	}
+}
+
+func TestCampaignFromRequestReportsAllViolations(t *testing.T) {
+	s := NewCouponServerWithClock(nil, testConfig(t, nil), newTestClock(time.Now()))
+
+	var v validator
+	s.campaignFromRequest(&v, &couponv1.CreateCampaignRequest{
+		AvailableCoupons: -1,
+		DiscountValue:    -100,
+		PerUserLimit:     -2,
+	})
+	err := v.err()
+	wantCode(t, err, ErrInvalidArgument)
+
+	got := make(map[string]bool)
+	for _, field := range violatedFields(t, err) {
+		got[field] = true
+	}
+	for _, field := range []string{"available_coupons", "start_date", "discount_value", "per_user_limit"} {
+		if !got[field] {
+			t.Errorf("violation of %s not reported; got %v", field, got)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package service
+
+import (
+	"bufio"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// throttledStream serves chunks lines, one per interval, extending the write
+// deadline before each when extend is set
+func throttledStream(s *CouponServer, chunks int, interval time.Duration, extend bool) http.Handler {
+	return WithResponseController(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rc := r.Context().Value(responseControllerKey{}).(*http.ResponseController)
+		for i := range chunks {
+			if extend {
+				s.extendWriteDeadline(rc)
+			}
+			if _, err := fmt.Fprintf(w, "chunk %d\n", i); err != nil {
+				return
+			}
+			if err := rc.Flush(); err != nil {
+				return
+			}
+			time.Sleep(interval)
+		}
+	}))
+}
+
+// readLines returns how many lines of the response at url arrive
+func readLines(t *testing.T, url string) int {
+	t.Helper()
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatalf("GET: %v", err)
+	}
+	defer resp.Body.Close()
+
+	lines := 0
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		lines++
+	}
+	return lines
+}
+
+func TestStreamOutlivesWriteTimeout(t *testing.T) {
+	const (
+		writeTimeout = 200 * time.Millisecond
+		chunks       = 6
+		interval     = 100 * time.Millisecond // 600ms in all, three write timeouts
+	)
+	s := &CouponServer{writeTimeout: writeTimeout}
+
+	for _, tt := range []struct {
+		name   string
+		extend bool
+		full   bool
+	}{
+		{"extended per write", true, true},
+		{"fixed deadline", false, false},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewUnstartedServer(throttledStream(s, chunks, interval, tt.extend))
+			srv.Config.WriteTimeout = writeTimeout
+			srv.Start()
+			defer srv.Close()
+
+			got := readLines(t, srv.URL)
+			if tt.full && got != chunks {
+				t.Errorf("got %d of %d chunks, want the whole stream", got, chunks)
+			}
+			if !tt.full && got == chunks {
+				t.Errorf("got all %d chunks past the write timeout, want the stream cut off", chunks)
+			}
+		})
+	}
+}
@@ -0,0 +1,18 @@
+-- Switch coupon code uniqueness from global to per campaign.
+--
+-- init.sql keys coupons by code alone, so a code exists at most once across
+-- all campaigns (APP_CODE_NAMESPACE=global). Run this once before starting
+-- the server with APP_CODE_NAMESPACE=campaign to let different campaigns
+-- reuse the same code. Lookups by code then need a campaign ID, and
+-- transfers fail for codes that already exist in the target campaign.
+-- There is no way back once duplicate codes exist.
+
+BEGIN;
+
+ALTER TABLE coupons DROP CONSTRAINT coupons_pkey;
+ALTER TABLE coupons ADD PRIMARY KEY (campaign_id, code);
+
+-- Lookups that still filter by code alone
+CREATE INDEX IF NOT EXISTS idx_coupons_code ON coupons(code);
+
+COMMIT;
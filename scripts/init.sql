@@ -6,23 +6,110 @@ CREATE TABLE IF NOT EXISTS campaigns (
     id BIGSERIAL PRIMARY KEY,
     available_coupons INTEGER NOT NULL,
     start_date TIMESTAMP WITH TIME ZONE NOT NULL,
+    code_alphabet TEXT NOT NULL DEFAULT '0123456789가나다라마바사아자차카타파하거너더러머버서어저처커터퍼허',
+    code_length INTEGER NOT NULL DEFAULT 10,
+    code_prefix TEXT NOT NULL DEFAULT '',
+    code_routing_tag BOOLEAN NOT NULL DEFAULT FALSE, -- codes end in a tag encoding the campaign ID
+    next_code_index BIGINT NOT NULL DEFAULT 0,
+    discount_value BIGINT NOT NULL DEFAULT 0,  -- value of one coupon in minor currency units
+    budget BIGINT NOT NULL DEFAULT 0,          -- cap on total issued value, 0 = unlimited
+    issued_value BIGINT NOT NULL DEFAULT 0,    -- running total of issued value, maintained only when budget > 0
+    coupon_metadata JSONB,                     -- metadata given to coupons generated for the campaign
+    per_user_limit INTEGER NOT NULL DEFAULT 0, -- max coupons per user, 0 = unlimited
+    max_issue_rps DOUBLE PRECISION NOT NULL DEFAULT 0, -- issuance rate limit, 0 = APP_PER_CAMPAIGN_RPS
+    shuffle_seed BIGINT,                       -- seed of the shuffled issuance order, NULL = creation order
+    reservation_order TEXT NOT NULL DEFAULT 'created_at' -- coupon column available coupons are issued by
+        CHECK (reservation_order IN ('created_at', 'expires_at', 'priority')),
+    slug TEXT UNIQUE,                          -- human-readable name usable instead of the ID, NULL when unset
+    window_start_minute INTEGER,               -- daily issuance window opening, minutes after midnight; NULL = always open
+    window_end_minute INTEGER,                 -- daily window closing (exclusive); below the start when it spans midnight
+    window_time_zone TEXT NOT NULL DEFAULT 'UTC', -- IANA time zone of the window
+    window_days SMALLINT NOT NULL DEFAULT 0,   -- weekdays the window opens on, bit 0 = Monday; 0 = every day
+    deleted_at TIMESTAMP WITH TIME ZONE,       -- set when soft-deleted
+    create_request_id TEXT UNIQUE,             -- client request ID of the CreateCampaign call, for retries
     created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
     updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
 );
 
 -- Create coupons table
 CREATE TABLE IF NOT EXISTS coupons (
-    code VARCHAR(10) PRIMARY KEY,
+    code VARCHAR(32) PRIMARY KEY,
     campaign_id BIGINT NOT NULL REFERENCES campaigns(id),
-    status VARCHAR(20) DEFAULT 'available',
+    status VARCHAR(20) DEFAULT 'available'
+        CHECK (status IN ('available', 'issued', 'reserved', 'redeemed', 'expired', 'revoked')),
+    user_id TEXT,  -- user the coupon was issued to, if known
+    pool TEXT NOT NULL DEFAULT '',  -- tier within the campaign, '' when the campaign has no pools
+    priority INTEGER NOT NULL DEFAULT 0,  -- the pool's priority; higher is issued first when ordered by priority
+    sort_key BIGINT NOT NULL DEFAULT 0,  -- issuance order within the campaign, 0 unless shuffled
+    code_index BIGINT,  -- coupon index the code was generated from
+    metadata JSONB,  -- arbitrary partner attributes
+    expires_at TIMESTAMP WITH TIME ZONE,  -- NULL = never expires
     issued_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
     created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
 );
 
+-- Coupons of finished campaigns moved out of coupons by the archiver
+-- (APP_ARCHIVE_AFTER). Only final statuses are archived, so rows never change.
+CREATE TABLE IF NOT EXISTS archived_coupons (
+    code VARCHAR(32) NOT NULL,
+    campaign_id BIGINT NOT NULL REFERENCES campaigns(id),
+    status VARCHAR(20) NOT NULL,
+    user_id TEXT,
+    pool TEXT NOT NULL DEFAULT '',
+    priority INTEGER NOT NULL DEFAULT 0,
+    sort_key BIGINT NOT NULL DEFAULT 0,
+    code_index BIGINT,
+    metadata JSONB,
+    expires_at TIMESTAMP WITH TIME ZONE,
+    issued_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE,
+    archived_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (campaign_id, code)
+);
+
+-- Last issuance per user, used to enforce the cross-campaign cooldown
+CREATE TABLE IF NOT EXISTS user_issue_cooldowns (
+    user_id TEXT PRIMARY KEY,
+    last_issued_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+-- Coupons issued per idempotency key, so retried IssueCoupon calls replay
+CREATE TABLE IF NOT EXISTS issue_idempotency_keys (
+    idempotency_key TEXT PRIMARY KEY,
+    campaign_id BIGINT NOT NULL REFERENCES campaigns(id) ON DELETE CASCADE,
+    coupon_code VARCHAR(32) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
 -- Create indexes for better performance
 CREATE INDEX IF NOT EXISTS idx_coupons_campaign_id ON coupons(campaign_id);
 CREATE INDEX IF NOT EXISTS idx_coupons_issued_at ON coupons(issued_at);
+-- Supports the issuance export, which walks all campaigns by issuance time
+CREATE INDEX IF NOT EXISTS idx_coupons_issued_at_code ON coupons(issued_at, code) WHERE status NOT IN ('available', 'reserved');
+-- Supports the recent issuance count in campaign stats and the ordered GetCampaign listing
+CREATE INDEX IF NOT EXISTS idx_coupons_campaign_issued_at ON coupons(campaign_id, issued_at, code) WHERE status = 'issued';
+-- Keeps remaining-count queries to an index-only scan of unissued coupons
+CREATE INDEX IF NOT EXISTS idx_coupons_campaign_available ON coupons(campaign_id) WHERE status = 'available';
+-- Serves reservations in issuance order without sorting
+CREATE INDEX IF NOT EXISTS idx_coupons_campaign_sort_key_available ON coupons(campaign_id, sort_key, created_at, code) WHERE status = 'available';
+-- Serve reservations of campaigns ordered by expiry or priority
+CREATE INDEX IF NOT EXISTS idx_coupons_campaign_status_expires_at ON coupons(campaign_id, status, expires_at, sort_key, created_at, code);
+CREATE INDEX IF NOT EXISTS idx_coupons_campaign_status_priority ON coupons(campaign_id, status, priority DESC, sort_key, created_at, code);
+-- Supports issuance from a specific pool
+CREATE INDEX IF NOT EXISTS idx_coupons_campaign_pool_available ON coupons(campaign_id, pool) WHERE status = 'available';
+-- Supports prefix searches (code LIKE 'prefix%') within a campaign
+CREATE INDEX IF NOT EXISTS idx_coupons_campaign_code_prefix ON coupons(campaign_id, code text_pattern_ops);
+-- Supports metadata filters (metadata @> '{"tier":"gold"}')
+CREATE INDEX IF NOT EXISTS idx_coupons_metadata ON coupons USING GIN (metadata jsonb_path_ops);
+-- Supports per-user limit checks
+CREATE INDEX IF NOT EXISTS idx_coupons_campaign_user ON coupons(campaign_id, user_id) WHERE user_id IS NOT NULL;
 CREATE INDEX IF NOT EXISTS idx_campaigns_start_date ON campaigns(start_date);
+-- Supports pruning expired idempotency keys
+CREATE INDEX IF NOT EXISTS idx_issue_idempotency_keys_created_at ON issue_idempotency_keys(created_at);
+-- Lookups of archived coupons by code alone
+CREATE INDEX IF NOT EXISTS idx_archived_coupons_code ON archived_coupons(code);
+-- Most reads only see live campaigns
+CREATE INDEX IF NOT EXISTS idx_campaigns_deleted_at ON campaigns(deleted_at);
 
 -- Create function to update updated_at timestamp
 CREATE OR REPLACE FUNCTION update_updated_at_column()